@@ -0,0 +1,286 @@
+// Package affine provides affine transformations, like translation, scaling,
+// rotation and skewing, of geometries.
+package affine
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/transform"
+)
+
+// ErrSingularMatrix is returned when trying to invert a matrix that has no
+// inverse.
+var ErrSingularMatrix = errors.New("affine: matrix is singular")
+
+// Matrix is a 3D affine transformation matrix. The matrix is stored in row
+// major order, with the last row of the matrix, [0 0 0 1], implied:
+//
+//	| a b c xoff |   | m[0] m[1]  m[2] m[3]  |
+//	| d e f yoff | = | m[4] m[5]  m[6] m[7]  |
+//	| g h i zoff |   | m[8] m[9] m[10] m[11] |
+//	| 0 0 0 1    |   |  0    0     0    1    |
+//
+// A point (x,y,z) is transformed to:
+//
+//	x' = a*x + b*y + c*z + xoff
+//	y' = d*x + e*y + f*z + yoff
+//	z' = g*x + h*y + i*z + zoff
+//
+// The zero value is not a valid transformation, use Identity instead.
+type Matrix [12]float64
+
+// Identity returns the matrix that does not change a point.
+func Identity() Matrix {
+	return Matrix{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+	}
+}
+
+// New2D returns the 2D matrix with the given values:
+//
+//	x' = a*x + b*y + xoff
+//	y' = d*x + e*y + yoff
+func New2D(a, b, d, e, xoff, yoff float64) Matrix {
+	return Matrix{
+		a, b, 0, xoff,
+		d, e, 0, yoff,
+		0, 0, 1, 0,
+	}
+}
+
+// FromGeoTransform returns the matrix for a GDAL style geo transform, which
+// transforms pixel (column, row) coordinates to the coordinates of the CRS:
+//
+//	x = gt[0] + column*gt[1] + row*gt[2]
+//	y = gt[3] + column*gt[4] + row*gt[5]
+//
+// Use Invert to go from CRS coordinates to pixel coordinates.
+func FromGeoTransform(gt [6]float64) Matrix {
+	return New2D(gt[1], gt[2], gt[4], gt[5], gt[0], gt[3])
+}
+
+// Translate returns a matrix that moves a point by dx and dy.
+func Translate(dx, dy float64) Matrix { return Translate3D(dx, dy, 0) }
+
+// Translate3D returns a matrix that moves a point by dx, dy and dz.
+func Translate3D(dx, dy, dz float64) Matrix {
+	return Matrix{
+		1, 0, 0, dx,
+		0, 1, 0, dy,
+		0, 0, 1, dz,
+	}
+}
+
+// Scale returns a matrix that scales a point, about the origin, by sx and sy.
+func Scale(sx, sy float64) Matrix { return Scale3D(sx, sy, 1) }
+
+// Scale3D returns a matrix that scales a point, about the origin, by sx, sy
+// and sz.
+func Scale3D(sx, sy, sz float64) Matrix {
+	return Matrix{
+		sx, 0, 0, 0,
+		0, sy, 0, 0,
+		0, 0, sz, 0,
+	}
+}
+
+// Rotate returns a matrix that rotates a point counter clockwise about the
+// origin by the given angle in radians. It is the same as RotateZ.
+func Rotate(angle float64) Matrix { return RotateZ(angle) }
+
+// RotateX returns a matrix that rotates a point about the x axis by the given
+// angle in radians.
+func RotateX(angle float64) Matrix {
+	sin, cos := sincos(angle)
+	return Matrix{
+		1, 0, 0, 0,
+		0, cos, -sin, 0,
+		0, sin, cos, 0,
+	}
+}
+
+// RotateY returns a matrix that rotates a point about the y axis by the given
+// angle in radians.
+func RotateY(angle float64) Matrix {
+	sin, cos := sincos(angle)
+	return Matrix{
+		cos, 0, sin, 0,
+		0, 1, 0, 0,
+		-sin, 0, cos, 0,
+	}
+}
+
+// RotateZ returns a matrix that rotates a point about the z axis by the given
+// angle in radians; counter clockwise in the xy plane.
+func RotateZ(angle float64) Matrix {
+	sin, cos := sincos(angle)
+	return Matrix{
+		cos, -sin, 0, 0,
+		sin, cos, 0, 0,
+		0, 0, 1, 0,
+	}
+}
+
+// Skew returns a matrix that shears a point along the x axis by xAngle, and
+// along the y axis by yAngle. The angles are in radians.
+func Skew(xAngle, yAngle float64) Matrix {
+	return New2D(1, math.Tan(xAngle), math.Tan(yAngle), 1, 0, 0)
+}
+
+// RotateAbout returns a matrix that rotates a point counter clockwise about
+// the given origin by the given angle in radians.
+func RotateAbout(angle float64, origin [2]float64) Matrix {
+	return about(Rotate(angle), origin)
+}
+
+// ScaleAbout returns a matrix that scales a point about the given origin by
+// sx and sy.
+func ScaleAbout(sx, sy float64, origin [2]float64) Matrix {
+	return about(Scale(sx, sy), origin)
+}
+
+// RotateAboutCentroid returns a matrix that rotates a point counter clockwise
+// about the centroid of the geometry, by the given angle in radians.
+func RotateAboutCentroid(angle float64, g geom.Geometry) (Matrix, error) {
+	c, err := Centroid(g)
+	if err != nil {
+		return Matrix{}, err
+	}
+	return RotateAbout(angle, c), nil
+}
+
+// ScaleAboutCentroid returns a matrix that scales a point about the centroid
+// of the geometry, by sx and sy.
+func ScaleAboutCentroid(sx, sy float64, g geom.Geometry) (Matrix, error) {
+	c, err := Centroid(g)
+	if err != nil {
+		return Matrix{}, err
+	}
+	return ScaleAbout(sx, sy, c), nil
+}
+
+// about returns a matrix that applies m with origin as the origin.
+func about(m Matrix, origin [2]float64) Matrix {
+	return Compose(Translate(-origin[0], -origin[1]), m, Translate(origin[0], origin[1]))
+}
+
+// sincos returns the sin and cos of the angle, making sure multiples of
+// right angles are exact.
+func sincos(angle float64) (sin, cos float64) {
+	sin, cos = math.Sincos(angle)
+	if r := math.Remainder(angle, math.Pi/2); r == 0 || math.Abs(r) < 1e-15 {
+		sin, cos = math.Round(sin), math.Round(cos)
+	}
+	return sin, cos
+}
+
+// Compose returns the matrix that applies each of the given matrices in
+// order. Compose() returns the identity matrix.
+func Compose(ms ...Matrix) Matrix {
+	m := Identity()
+	for _, n := range ms {
+		m = m.Then(n)
+	}
+	return m
+}
+
+// Then returns the matrix that applies m, and then n.
+func (m Matrix) Then(n Matrix) Matrix { return n.Multiply(m) }
+
+// Multiply returns the matrix product m × n; the result applies n first, and
+// then m.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	var r Matrix
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 4; col++ {
+			var v float64
+			for k := 0; k < 3; k++ {
+				v += m[row*4+k] * n[k*4+col]
+			}
+			if col == 3 {
+				v += m[row*4+3]
+			}
+			r[row*4+col] = v
+		}
+	}
+	return r
+}
+
+// Determinant returns the determinant of the matrix.
+func (m Matrix) Determinant() float64 {
+	return m[0]*(m[5]*m[10]-m[6]*m[9]) -
+		m[1]*(m[4]*m[10]-m[6]*m[8]) +
+		m[2]*(m[4]*m[9]-m[5]*m[8])
+}
+
+// Invert returns the matrix that undoes m. ErrSingularMatrix is returned if
+// m does not have an inverse.
+func (m Matrix) Invert() (Matrix, error) {
+	det := m.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Matrix{}, ErrSingularMatrix
+	}
+	// inverse of the linear part, using the adjugate.
+	var inv Matrix
+	inv[0] = (m[5]*m[10] - m[6]*m[9]) / det
+	inv[1] = (m[2]*m[9] - m[1]*m[10]) / det
+	inv[2] = (m[1]*m[6] - m[2]*m[5]) / det
+	inv[4] = (m[6]*m[8] - m[4]*m[10]) / det
+	inv[5] = (m[0]*m[10] - m[2]*m[8]) / det
+	inv[6] = (m[2]*m[4] - m[0]*m[6]) / det
+	inv[8] = (m[4]*m[9] - m[5]*m[8]) / det
+	inv[9] = (m[1]*m[8] - m[0]*m[9]) / det
+	inv[10] = (m[0]*m[5] - m[1]*m[4]) / det
+	// the translation is the inverse linear part applied to the negated
+	// translation.
+	for row := 0; row < 3; row++ {
+		inv[row*4+3] = -(inv[row*4]*m[3] + inv[row*4+1]*m[7] + inv[row*4+2]*m[11])
+	}
+	return inv, nil
+}
+
+// IsIdentity returns whether the matrix is the identity matrix.
+func (m Matrix) IsIdentity() bool { return m == Identity() }
+
+// Apply returns the transformed coordinates of the point x, y, z.
+func (m Matrix) Apply(x, y, z float64) (float64, float64, float64) {
+	return m[0]*x + m[1]*y + m[2]*z + m[3],
+		m[4]*x + m[5]*y + m[6]*z + m[7],
+		m[8]*x + m[9]*y + m[10]*z + m[11]
+}
+
+// ApplyXY returns the transformed point, using a z value of zero.
+func (m Matrix) ApplyXY(pt [2]float64) [2]float64 {
+	x, y, _ := m.Apply(pt[0], pt[1], 0)
+	return [2]float64{x, y}
+}
+
+// TransformExtent returns the extent that contains the transformed corners
+// of the extent.
+func (m Matrix) TransformExtent(e *geom.Extent) *geom.Extent {
+	// affine transformations can't fail.
+	ext, _ := m.mapper().Extent(e)
+	return ext
+}
+
+// Transform returns a new geometry, of the same type, with all of its points
+// transformed. The z values of geometries with a z value are transformed as
+// well, 2D geometries are transformed with a z value of zero. M values are
+// not changed. A geom.Extent is transformed to the extent containing its
+// transformed corners.
+func (m Matrix) Transform(g geom.Geometry) (geom.Geometry, error) {
+	return m.mapper().Geometry(g)
+}
+
+func (m Matrix) mapper() transform.Mapper {
+	return transform.Mapper{
+		Transform: func(x, y, z float64) (float64, float64, float64, error) {
+			x, y, z = m.Apply(x, y, z)
+			return x, y, z, nil
+		},
+	}
+}
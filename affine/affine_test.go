@@ -0,0 +1,360 @@
+package affine_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/affine"
+)
+
+func matrixNear(a, b affine.Matrix) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMatrixApply(t *testing.T) {
+	type tcase struct {
+		m        affine.Matrix
+		pt       [3]float64
+		expected [3]float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			x, y, z := tc.m.Apply(tc.pt[0], tc.pt[1], tc.pt[2])
+			got := [3]float64{x, y, z}
+			for i := range got {
+				if math.Abs(got[i]-tc.expected[i]) > 1e-9 {
+					t.Errorf("apply, expected %v got %v", tc.expected, got)
+					return
+				}
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"identity": {
+			m:        affine.Identity(),
+			pt:       [3]float64{1, 2, 3},
+			expected: [3]float64{1, 2, 3},
+		},
+		"translate": {
+			m:        affine.Translate(10, -5),
+			pt:       [3]float64{1, 2, 3},
+			expected: [3]float64{11, -3, 3},
+		},
+		"translate 3d": {
+			m:        affine.Translate3D(1, 1, 1),
+			pt:       [3]float64{1, 2, 3},
+			expected: [3]float64{2, 3, 4},
+		},
+		"scale": {
+			m:        affine.Scale(2, 3),
+			pt:       [3]float64{1, 2, 3},
+			expected: [3]float64{2, 6, 3},
+		},
+		"rotate 90": {
+			m:        affine.Rotate(math.Pi / 2),
+			pt:       [3]float64{1, 0, 0},
+			expected: [3]float64{0, 1, 0},
+		},
+		"rotate x 90": {
+			m:        affine.RotateX(math.Pi / 2),
+			pt:       [3]float64{0, 1, 0},
+			expected: [3]float64{0, 0, 1},
+		},
+		"rotate y 90": {
+			m:        affine.RotateY(math.Pi / 2),
+			pt:       [3]float64{0, 0, 1},
+			expected: [3]float64{1, 0, 0},
+		},
+		"skew": {
+			m:        affine.Skew(math.Pi/4, 0),
+			pt:       [3]float64{0, 2, 0},
+			expected: [3]float64{2, 2, 0},
+		},
+		"rotate about": {
+			m:        affine.RotateAbout(math.Pi, [2]float64{1, 1}),
+			pt:       [3]float64{2, 1, 0},
+			expected: [3]float64{0, 1, 0},
+		},
+		"scale about": {
+			m:        affine.ScaleAbout(2, 2, [2]float64{1, 1}),
+			pt:       [3]float64{2, 2, 5},
+			expected: [3]float64{3, 3, 5},
+		},
+		"compose": {
+			m:        affine.Compose(affine.Scale(2, 2), affine.Translate(1, 0)),
+			pt:       [3]float64{1, 1, 0},
+			expected: [3]float64{3, 2, 0},
+		},
+		"geo transform": {
+			// 10m pixels, with the origin at the top left.
+			m:        affine.FromGeoTransform([6]float64{500000, 10, 0, 4000000, 0, -10}),
+			pt:       [3]float64{2, 3, 0},
+			expected: [3]float64{500020, 3999970, 0},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMatrixInvert(t *testing.T) {
+	type tcase struct {
+		m   affine.Matrix
+		err error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			inv, err := tc.m.Invert()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if got := tc.m.Then(inv); !matrixNear(got, affine.Identity()) {
+				t.Errorf("m then inverse, expected identity got %v", got)
+			}
+			if got := inv.Then(tc.m); !matrixNear(got, affine.Identity()) {
+				t.Errorf("inverse then m, expected identity got %v", got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"identity": {m: affine.Identity()},
+		"composed": {
+			m: affine.Compose(
+				affine.Translate3D(4, -2, 7),
+				affine.RotateX(0.3),
+				affine.Scale3D(2, 0.5, 3),
+				affine.Skew(0.2, 0.1),
+				affine.RotateZ(1.2),
+			),
+		},
+		"geo transform": {m: affine.FromGeoTransform([6]float64{500000, 10, 0.5, 4000000, 0.25, -10})},
+		"singular":      {m: affine.Scale(0, 1), err: affine.ErrSingularMatrix},
+		"zero":          {m: affine.Matrix{}, err: affine.ErrSingularMatrix},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMatrixTransform(t *testing.T) {
+	type tcase struct {
+		m        affine.Matrix
+		geom     geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := tc.m.Transform(tc.geom)
+			if tc.err != nil {
+				if !reflect.DeepEqual(err, tc.err) {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("transform, expected %#v got %#v", tc.expected, got)
+			}
+		}
+	}
+
+	move := affine.Translate3D(1, 2, 3)
+	tcases := map[string]tcase{
+		"point": {
+			m:        move,
+			geom:     geom.Point{1, 1},
+			expected: geom.Point{2, 3},
+		},
+		"point z": {
+			m:        move,
+			geom:     geom.PointZ{1, 1, 1},
+			expected: geom.PointZ{2, 3, 4},
+		},
+		"point m": {
+			m:        move,
+			geom:     geom.PointM{1, 1, 10},
+			expected: geom.PointM{2, 3, 10},
+		},
+		"point zm": {
+			m:        move,
+			geom:     geom.PointZM{1, 1, 1, 10},
+			expected: geom.PointZM{2, 3, 4, 10},
+		},
+		"point zms": {
+			m:        move,
+			geom:     geom.PointZMS{Srid: 4326, Xyzm: geom.PointZM{1, 1, 1, 10}},
+			expected: geom.PointZMS{Srid: 4326, Xyzm: geom.PointZM{2, 3, 4, 10}},
+		},
+		"multipoint m": {
+			m:        move,
+			geom:     geom.MultiPointM{{0, 0, 5}, {1, 1, 6}},
+			expected: geom.MultiPointM{{1, 2, 5}, {2, 3, 6}},
+		},
+		"line": {
+			m:        affine.Scale(2, 2),
+			geom:     geom.Line{{1, 1}, {2, 2}},
+			expected: geom.Line{{2, 2}, {4, 4}},
+		},
+		"linestring zms": {
+			m:        move,
+			geom:     geom.LineStringZMS{Srid: 3857, Lszm: geom.LineStringZM{{0, 0, 0, 1}, {1, 1, 1, 2}}},
+			expected: geom.LineStringZMS{Srid: 3857, Lszm: geom.LineStringZM{{1, 2, 3, 1}, {2, 3, 4, 2}}},
+		},
+		"multilinestring z": {
+			m:        move,
+			geom:     geom.MultiLineStringZ{{{0, 0, 0}, {1, 1, 1}}},
+			expected: geom.MultiLineStringZ{{{1, 2, 3}, {2, 3, 4}}},
+		},
+		"polygon s": {
+			m:        affine.Scale(2, 2),
+			geom:     geom.PolygonS{Srid: 4326, Pol: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}},
+			expected: geom.PolygonS{Srid: 4326, Pol: geom.Polygon{{{0, 0}, {2, 0}, {2, 2}}}},
+		},
+		"multipolygon": {
+			m:        affine.Translate(1, 1),
+			geom:     geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}}}},
+			expected: geom.MultiPolygon{{{{1, 1}, {2, 1}, {2, 2}}}},
+		},
+		"collection": {
+			m:        affine.Translate(1, 1),
+			geom:     geom.Collection{geom.Point{0, 0}, geom.LineStringM{{0, 0, 1}, {1, 1, 2}}},
+			expected: geom.Collection{geom.Point{1, 1}, geom.LineStringM{{1, 1, 1}, {2, 2, 2}}},
+		},
+		"extent": {
+			m:        affine.Rotate(math.Pi / 2),
+			geom:     geom.Extent{0, 0, 2, 1},
+			expected: geom.Extent{-1, 0, 0, 2},
+		},
+		"extent pointer": {
+			m:        affine.Translate(1, 1),
+			geom:     geom.NewExtent([2]float64{0, 0}, [2]float64{1, 1}),
+			expected: geom.NewExtent([2]float64{1, 1}, [2]float64{2, 2}),
+		},
+		"unknown": {
+			m:    move,
+			geom: 1,
+			err:  geom.ErrUnknownGeometry{Geom: 1},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCentroid(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		expected [2]float64
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := affine.Centroid(tc.geom)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if math.Abs(got[0]-tc.expected[0]) > 1e-9 || math.Abs(got[1]-tc.expected[1]) > 1e-9 {
+				t.Errorf("centroid, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"points": {
+			geom:     geom.MultiPoint{{0, 0}, {2, 0}, {2, 2}, {0, 2}},
+			expected: [2]float64{1, 1},
+		},
+		"linestring": {
+			geom:     geom.LineString{{0, 0}, {4, 0}, {4, 2}},
+			expected: [2]float64{8.0 / 3, 1.0 / 3},
+		},
+		"square": {
+			geom:     geom.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}},
+			expected: [2]float64{1, 1},
+		},
+		"square with hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+				{{2, 0}, {4, 0}, {4, 4}, {2, 4}},
+			},
+			expected: [2]float64{1, 2},
+		},
+		"collection uses highest dimension": {
+			geom:     geom.Collection{geom.Point{100, 100}, geom.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}},
+			expected: [2]float64{1, 1},
+		},
+		"empty": {
+			geom: geom.MultiPoint{},
+			err:  affine.ErrEmptyGeometry,
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestScaleAboutCentroid(t *testing.T) {
+	plyg := geom.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}}}
+	m, err := affine.ScaleAboutCentroid(2, 2, plyg)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	got, err := m.Transform(plyg)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("scale, expected %v got %v", expected, got)
+	}
+
+	for i, angle := range []float64{math.Pi / 2, math.Pi, 3 * math.Pi / 2} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			m, err := affine.RotateAboutCentroid(angle, plyg)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			c, _ := affine.Centroid(plyg)
+			if got := m.ApplyXY(c); got != c {
+				t.Errorf("centroid moved, expected %v got %v", c, got)
+			}
+		})
+	}
+}
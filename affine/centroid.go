@@ -0,0 +1,138 @@
+package affine
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrEmptyGeometry is returned when the centroid of a geometry without any
+// points is requested.
+var ErrEmptyGeometry = errors.New("affine: empty geometry")
+
+// centroid accumulates the weighted centers of the parts of a geometry.
+// Only the parts with the highest dimension contribute to the centroid.
+type centroid struct {
+	// dim is the dimension of the parts added: 0 for points, 1 for lines
+	// and 2 for areas.
+	dim    int
+	weight float64
+	x, y   float64
+}
+
+func (c *centroid) add(dim int, weight float64, pt [2]float64) {
+	if dim < c.dim {
+		return
+	}
+	if dim > c.dim {
+		*c = centroid{dim: dim}
+	}
+	c.weight += weight
+	c.x += weight * pt[0]
+	c.y += weight * pt[1]
+}
+
+func (c *centroid) addPoints(pts [][2]float64) {
+	for _, pt := range pts {
+		c.add(0, 1, pt)
+	}
+}
+
+func (c *centroid) addLine(pts [][2]float64) {
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		l := math.Hypot(b[0]-a[0], b[1]-a[1])
+		if l == 0 {
+			c.add(0, 1, a)
+			continue
+		}
+		c.add(1, l, [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2})
+	}
+	if len(pts) == 1 {
+		c.add(0, 1, pts[0])
+	}
+}
+
+func (c *centroid) addPolygon(rings [][][2]float64) {
+	if len(rings) == 0 {
+		return
+	}
+	for i, ring := range rings {
+		// triangles fanned out from the first point of the ring, using the
+		// first point as the origin to reduce rounding errors.
+		var area, x, y float64
+		for j := 1; j+1 < len(ring); j++ {
+			a, b, o := ring[j], ring[j+1], ring[0]
+			cross := (a[0]-o[0])*(b[1]-o[1]) - (b[0]-o[0])*(a[1]-o[1])
+			area += cross
+			x += cross * (o[0] + a[0] + b[0])
+			y += cross * (o[1] + a[1] + b[1])
+		}
+		if area == 0 {
+			if i == 0 {
+				c.addLine(append(append([][2]float64(nil), ring...), ring[0]))
+				return
+			}
+			continue
+		}
+		// holes subtract from the shell, whatever their winding.
+		weight := math.Abs(area) / 2
+		if i != 0 {
+			weight = -weight
+		}
+		c.add(2, weight, [2]float64{x / (3 * area), y / (3 * area)})
+	}
+}
+
+func (c *centroid) addGeometry(g geom.Geometry) error {
+	switch geo := g.(type) {
+	default:
+		return geom.ErrUnknownGeometry{Geom: g}
+	case geom.Extent:
+		c.addPolygon([][][2]float64{geo.Vertices()})
+	case *geom.Extent:
+		c.addPolygon([][][2]float64{geo.Vertices()})
+	case geom.Triangle:
+		c.addPolygon([][][2]float64{geo[:]})
+	case geom.Line:
+		c.addLine(geo[:])
+	case geom.Pointer:
+		c.addPoints([][2]float64{geo.XY()})
+	case geom.MultiPointer:
+		c.addPoints(geo.Points())
+	case geom.LineStringer:
+		c.addLine(geo.Vertices())
+	case geom.MultiLineStringer:
+		for _, ls := range geo.LineStrings() {
+			c.addLine(ls)
+		}
+	case geom.Polygoner:
+		c.addPolygon(geo.LinearRings())
+	case geom.MultiPolygoner:
+		for _, p := range geo.Polygons() {
+			c.addPolygon(p)
+		}
+	case geom.Collectioner:
+		for _, child := range geo.Geometries() {
+			if err := c.addGeometry(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Centroid returns the center of mass of the geometry, using only the parts
+// of the geometry with the highest dimension. For example the centroid of a
+// collection with a polygon and a point is the centroid of the polygon.
+func Centroid(g geom.Geometry) ([2]float64, error) {
+	var c centroid
+	if err := c.addGeometry(g); err != nil {
+		return [2]float64{}, err
+	}
+	if c.weight == 0 {
+		return [2]float64{}, ErrEmptyGeometry
+	}
+	return [2]float64{c.x / c.weight, c.y / c.weight}, nil
+}
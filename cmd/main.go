@@ -7,8 +7,6 @@ import (
 	"log"
 	"math"
 	"os"
-	"strconv"
-	"strings"
 
 	"github.com/go-spatial/geom/winding"
 
@@ -116,32 +114,11 @@ func main() {
 		usage()
 		return
 	}
-	parts := strings.Split(flag.Args()[0], "/")
-	if len(parts) < 3 {
-		fmt.Fprintf(os.Stderr, "invalid first parameters expected slippy tile\n Got %v\n", flag.Args()[1])
-		usage()
-	}
-
-	z, err := strconv.ParseUint(parts[0], 10, 64)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unabled to parse z: %v", err)
-		usage()
-	}
-	x, err := strconv.ParseUint(parts[1], 10, 64)
+	tile, err := slippy.ParseTile(flag.Args()[0])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unabled to parse x: %v", err)
+		fmt.Fprintf(os.Stderr, "invalid first parameters expected slippy tile\n Got %v: %v\n", flag.Args()[0], err)
 		usage()
 	}
-	y, err := strconv.ParseUint(parts[2], 10, 64)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unabled to parse y: %v", err)
-		usage()
-	}
-	tile := slippy.Tile{
-		Z: slippy.Zoom(z),
-		X: uint(x),
-		Y: uint(y),
-	}
 	fileTemplate := newOutFile(tile, *tag)
 	geo, err := readInputWKT(flag.Args()[1])
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/similarity"
 )
 
 // Compare holds the tolerances for the comparison functions
@@ -338,6 +339,25 @@ func (cmp Compare) GeometryEqual(g1, g2 geom.Geometry) bool {
 	return false
 }
 
+// GeometryNear checks if the Hausdorff distance between the two geometries is
+// within maxDist, plus the comparator's tolerance. Unlike GeometryEqual, the
+// geometries do not need to be of the same type or have the same vertices. The
+// vertices of each geometry are measured to the segments of the other
+// geometry. Geometries that can not be measured are not near.
+func (cmp Compare) GeometryNear(g1, g2 geom.Geometry, maxDist float64) bool {
+	if g1Srid, ok := g1.(geom.SRIDer); ok {
+		g2Srid, ok := g2.(geom.SRIDer)
+		if !ok || g1Srid.SRID() != g2Srid.SRID() {
+			return false
+		}
+	}
+	d, err := similarity.HausdorffDistance(g1, g2, 0)
+	if err != nil {
+		return false
+	}
+	return d <= maxDist+cmp.Tolerance
+}
+
 // Empty functions
 func (Compare) IsEmptyPoint(pt [2]float64) bool      { return IsEmptyPoint(pt) }
 func (Compare) IsEmptyPoints(pts [][2]float64) bool  { return IsEmptyPoints(pts) }
@@ -114,3 +114,8 @@ func CollectionerEqual(col1, col2 geom.Collectioner) bool {
 // GeometryEqual checks if the two geometries are of the same type and then
 // calls the type method to check if they are equal
 func GeometryEqual(g1, g2 geom.Geometry) bool { return DefaultCompare().GeometryEqual(g1, g2) }
+
+// GeometryNear checks if the two geometries are within maxDist of each other using the default comparator
+func GeometryNear(g1, g2 geom.Geometry, maxDist float64) bool {
+	return DefaultCompare().GeometryNear(g1, g2, maxDist)
+}
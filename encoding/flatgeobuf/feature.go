@@ -0,0 +1,89 @@
+package flatgeobuf
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/flatgeobuf/internal/flatbuffers"
+)
+
+// The fields of the feature table.
+const (
+	featureGeometry = iota
+	featureProperties
+	featureColumns
+	featureNumFields
+)
+
+// Feature is a feature of a file.
+type Feature struct {
+	// Geometry is nil for features without a geometry.
+	Geometry geom.Geometry
+	// Properties are the values of the feature's properties, by the name of
+	// their column; see ColumnType for the types of the values.
+	Properties map[string]interface{}
+}
+
+// extent returns the extent of the coordinates of the geometry, and its
+// parts; or nil if it has no coordinates.
+func (g *geometry) extent() *geom.Extent {
+	var e *geom.Extent
+	for i := 0; i+1 < len(g.xy); i += 2 {
+		if e == nil {
+			e = &geom.Extent{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+		}
+		e.AddPoints([2]float64{g.xy[i], g.xy[i+1]})
+	}
+	for i := range g.parts {
+		pe := g.parts[i].extent()
+		switch {
+		case pe == nil:
+		case e == nil:
+			e = pe
+		default:
+			e.Add(pe)
+		}
+	}
+	return e
+}
+
+// decodeFeature decodes a feature, without its size prefix, of a file with
+// the header.
+//
+// Possible errors:
+//
+//	ErrInvalidFeature
+//	ErrInvalidGeometry
+//	ErrUnsupportedGeometryType
+func decodeFeature(data []byte, h *Header) (f *Feature, err error) {
+	defer func() {
+		if err == flatbuffers.ErrOutOfRange {
+			err = ErrInvalidFeature
+		}
+	}()
+	defer flatbuffers.Catch(&err)
+
+	t := flatbuffers.Root(data)
+	f = &Feature{}
+	if gt, ok := t.Table(featureGeometry); ok {
+		g, err := readGeometry(gt, 0)
+		if err != nil {
+			return nil, err
+		}
+		typ := h.GeometryType
+		if typ == GeometryTypeUnknown {
+			typ = g.typ
+		}
+		if f.Geometry, err = toGeom(&g, typ, dims{z: h.HasZ, m: h.HasM}); err != nil {
+			return nil, err
+		}
+	}
+	columns := h.Columns
+	if tables := t.Tables(featureColumns); len(tables) > 0 {
+		columns = readColumns(tables)
+	}
+	if f.Properties, err = decodeProperties(t.Bytes(featureProperties), columns); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
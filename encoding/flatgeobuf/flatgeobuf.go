@@ -0,0 +1,402 @@
+/*
+Package flatgeobuf reads and writes FlatGeobuf files; a binary encoding of
+simple features, with an optional packed Hilbert R-tree of the features'
+extents so the features in an extent can be read without reading the whole
+file.
+
+A file is the magic bytes, the header, the index, and the features; the
+header and each feature are FlatBuffers tables prefixed with their size.
+
+See https://flatgeobuf.org
+*/
+package flatgeobuf
+
+import (
+	"errors"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/flatgeobuf/internal/flatbuffers"
+)
+
+// magic are the first bytes of a file; the fourth byte is the major version
+// and the last is the patch version.
+var magic = [8]byte{'f', 'g', 'b', 3, 'f', 'g', 'b', 0}
+
+const (
+	// maxHeaderSize is the largest header the reader will read.
+	maxHeaderSize = 10 * 1024 * 1024
+	// maxDepth is the deepest geometries are nested in collections.
+	maxDepth = 32
+)
+
+var (
+	// ErrInvalidMagic is returned when the file does not start with the magic bytes of FlatGeobuf version 3.
+	ErrInvalidMagic = errors.New("flatgeobuf: invalid magic bytes")
+	// ErrInvalidHeader is returned when the header can not be decoded.
+	ErrInvalidHeader = errors.New("flatgeobuf: invalid header")
+	// ErrInvalidFeature is returned when a feature can not be decoded.
+	ErrInvalidFeature = errors.New("flatgeobuf: invalid feature")
+	// ErrInvalidGeometry is returned when a geometry can not be decoded.
+	ErrInvalidGeometry = errors.New("flatgeobuf: invalid geometry")
+	// ErrUnsupportedGeometryType is returned for the curve and surface geometry types.
+	ErrUnsupportedGeometryType = errors.New("flatgeobuf: unsupported geometry type")
+	// ErrGeometryTypeMismatch is returned when writing a geometry of a type other than the header's.
+	ErrGeometryTypeMismatch = errors.New("flatgeobuf: geometry type does not match the header")
+	// ErrDimensionMismatch is returned when writing a geometry whose z and m values do not match the header's.
+	ErrDimensionMismatch = errors.New("flatgeobuf: geometry dimensions do not match the header")
+	// ErrNoIndex is returned when filtering the features of a file without an index.
+	ErrNoIndex = errors.New("flatgeobuf: no index")
+	// ErrNoGeometry is returned when writing a feature without a geometry to an indexed file.
+	ErrNoGeometry = errors.New("flatgeobuf: feature has no geometry")
+	// ErrUnknownColumn is returned when writing a property that is not one of the columns.
+	ErrUnknownColumn = errors.New("flatgeobuf: unknown column")
+	// ErrInvalidPropertyValue is returned when writing a property value that does not fit the column type.
+	ErrInvalidPropertyValue = errors.New("flatgeobuf: invalid property value")
+	// ErrAlreadyReading is returned when setting a filter after reading features.
+	ErrAlreadyReading = errors.New("flatgeobuf: filter set after reading features")
+	// ErrClosed is returned when writing to a closed writer.
+	ErrClosed = errors.New("flatgeobuf: writer is closed")
+)
+
+// GeometryType is the type of the geometries.
+type GeometryType uint8
+
+// The geometry types. Only the simple feature types, Point through
+// GeometryCollection, are supported.
+const (
+	GeometryTypeUnknown GeometryType = iota
+	GeometryTypePoint
+	GeometryTypeLineString
+	GeometryTypePolygon
+	GeometryTypeMultiPoint
+	GeometryTypeMultiLineString
+	GeometryTypeMultiPolygon
+	GeometryTypeGeometryCollection
+	GeometryTypeCircularString
+	GeometryTypeCompoundCurve
+	GeometryTypeCurvePolygon
+	GeometryTypeMultiCurve
+	GeometryTypeMultiSurface
+	GeometryTypeCurve
+	GeometryTypeSurface
+	GeometryTypePolyhedralSurface
+	GeometryTypeTIN
+	GeometryTypeTriangle
+)
+
+// ColumnType is the type of the values of a column.
+type ColumnType uint8
+
+// The column types, and the Go type of their values:
+//
+//	ColumnTypeByte      int8
+//	ColumnTypeUByte     uint8
+//	ColumnTypeBool      bool
+//	ColumnTypeShort     int16
+//	ColumnTypeUShort    uint16
+//	ColumnTypeInt       int32
+//	ColumnTypeUInt      uint32
+//	ColumnTypeLong      int64
+//	ColumnTypeULong     uint64
+//	ColumnTypeFloat     float32
+//	ColumnTypeDouble    float64
+//	ColumnTypeString    string
+//	ColumnTypeJSON      json.RawMessage
+//	ColumnTypeDateTime  time.Time
+//	ColumnTypeBinary    []byte
+const (
+	ColumnTypeByte ColumnType = iota
+	ColumnTypeUByte
+	ColumnTypeBool
+	ColumnTypeShort
+	ColumnTypeUShort
+	ColumnTypeInt
+	ColumnTypeUInt
+	ColumnTypeLong
+	ColumnTypeULong
+	ColumnTypeFloat
+	ColumnTypeDouble
+	ColumnTypeString
+	ColumnTypeJSON
+	ColumnTypeDateTime
+	ColumnTypeBinary
+)
+
+// Column describes a property of the features.
+type Column struct {
+	Name        string
+	Type        ColumnType
+	Title       string
+	Description string
+	// Width, Precision and Scale describe the values, as in a database
+	// schema; 0 is unset.
+	Width     int32
+	Precision int32
+	Scale     int32
+	// NotNull is true when every feature has the property.
+	NotNull    bool
+	Unique     bool
+	PrimaryKey bool
+	// Metadata is JSON describing the column.
+	Metadata string
+}
+
+// CRS is the coordinate reference system of the geometries.
+type CRS struct {
+	// Org is the organization of the code; if empty, it is EPSG.
+	Org         string
+	Code        int32
+	Name        string
+	Description string
+	WKT         string
+	// CodeString is the code, for organizations whose codes are not numbers.
+	CodeString string
+}
+
+// Header is the header of a file.
+type Header struct {
+	Name string
+	// Envelope is the extent of the features; nil if not known.
+	Envelope *geom.Extent
+	// GeometryType is the type of the geometries of all the features; or
+	// GeometryTypeUnknown when the features have different types.
+	GeometryType GeometryType
+	// HasZ and HasM are whether the geometries have z and m values. HasT and
+	// HasTM are for time values, which are not read or written.
+	HasZ, HasM, HasT, HasTM bool
+	Columns                 []Column
+	// FeaturesCount is the number of features; 0 if not known.
+	FeaturesCount uint64
+	// IndexNodeSize is the number of children of the nodes of the index; 0
+	// when there is no index.
+	IndexNodeSize uint16
+	CRS           *CRS
+	Title         string
+	Description   string
+	// Metadata is JSON describing the file.
+	Metadata string
+}
+
+// The fields of the tables.
+const (
+	headerName = iota
+	headerEnvelope
+	headerGeometryType
+	headerHasZ
+	headerHasM
+	headerHasT
+	headerHasTM
+	headerColumns
+	headerFeaturesCount
+	headerIndexNodeSize
+	headerCRS
+	headerTitle
+	headerDescription
+	headerMetadata
+	headerNumFields
+)
+
+const (
+	columnName = iota
+	columnType
+	columnTitle
+	columnDescription
+	columnWidth
+	columnPrecision
+	columnScale
+	columnNullable
+	columnUnique
+	columnPrimaryKey
+	columnMetadata
+	columnNumFields
+)
+
+const (
+	crsOrg = iota
+	crsCode
+	crsName
+	crsDescription
+	crsWKT
+	crsCodeString
+	crsNumFields
+)
+
+// createString adds the string, or nothing for an empty string.
+func createString(b *flatbuffers.Builder, s string) int {
+	if s == "" {
+		return 0
+	}
+	return b.CreateString(s)
+}
+
+// unsetAsDefault returns -1, the default of the width, precision and scale of
+// a column, for 0.
+func unsetAsDefault(v int32) int32 {
+	if v == 0 {
+		return -1
+	}
+	return v
+}
+
+// buildColumns adds the columns, returning the offset of the vector; or 0 if
+// there are no columns.
+func buildColumns(b *flatbuffers.Builder, columns []Column) int {
+	if len(columns) == 0 {
+		return 0
+	}
+	offs := make([]int, len(columns))
+	for i, c := range columns {
+		name := b.CreateString(c.Name)
+		title := createString(b, c.Title)
+		description := createString(b, c.Description)
+		metadata := createString(b, c.Metadata)
+
+		b.StartTable(columnNumFields)
+		b.AddOffset(columnName, name)
+		b.AddUint8(columnType, uint8(c.Type), 0)
+		b.AddOffset(columnTitle, title)
+		b.AddOffset(columnDescription, description)
+		// unset is -1 in the file.
+		b.AddInt32(columnWidth, unsetAsDefault(c.Width), -1)
+		b.AddInt32(columnPrecision, unsetAsDefault(c.Precision), -1)
+		b.AddInt32(columnScale, unsetAsDefault(c.Scale), -1)
+		b.AddBool(columnNullable, !c.NotNull, true)
+		b.AddBool(columnUnique, c.Unique, false)
+		b.AddBool(columnPrimaryKey, c.PrimaryKey, false)
+		b.AddOffset(columnMetadata, metadata)
+		offs[i] = b.EndTable()
+	}
+	return b.CreateOffsets(offs)
+}
+
+// readColumns returns the columns of the vector of column tables.
+func readColumns(tables []flatbuffers.Table) []Column {
+	if len(tables) == 0 {
+		return nil
+	}
+	columns := make([]Column, len(tables))
+	for i, t := range tables {
+		unset := func(v int32) int32 {
+			if v == -1 {
+				return 0
+			}
+			return v
+		}
+		columns[i] = Column{
+			Name:        t.String(columnName),
+			Type:        ColumnType(t.Uint8(columnType, 0)),
+			Title:       t.String(columnTitle),
+			Description: t.String(columnDescription),
+			Width:       unset(t.Int32(columnWidth, -1)),
+			Precision:   unset(t.Int32(columnPrecision, -1)),
+			Scale:       unset(t.Int32(columnScale, -1)),
+			NotNull:     !t.Bool(columnNullable, true),
+			Unique:      t.Bool(columnUnique, false),
+			PrimaryKey:  t.Bool(columnPrimaryKey, false),
+			Metadata:    t.String(columnMetadata),
+		}
+	}
+	return columns
+}
+
+// buildCRS adds the crs, returning its offset.
+func buildCRS(b *flatbuffers.Builder, crs *CRS) int {
+	org := createString(b, crs.Org)
+	name := createString(b, crs.Name)
+	description := createString(b, crs.Description)
+	wkt := createString(b, crs.WKT)
+	codeString := createString(b, crs.CodeString)
+
+	b.StartTable(crsNumFields)
+	b.AddOffset(crsOrg, org)
+	b.AddInt32(crsCode, crs.Code, 0)
+	b.AddOffset(crsName, name)
+	b.AddOffset(crsDescription, description)
+	b.AddOffset(crsWKT, wkt)
+	b.AddOffset(crsCodeString, codeString)
+	return b.EndTable()
+}
+
+// MarshalBinary encodes the header as a size prefixed FlatBuffers table.
+func (h *Header) MarshalBinary() ([]byte, error) {
+	var b flatbuffers.Builder
+	b.Reset()
+	name := createString(&b, h.Name)
+	var envelope int
+	if h.Envelope != nil {
+		envelope = b.CreateFloat64s(h.Envelope[:])
+	}
+	columns := buildColumns(&b, h.Columns)
+	var crs int
+	if h.CRS != nil {
+		crs = buildCRS(&b, h.CRS)
+	}
+	title := createString(&b, h.Title)
+	description := createString(&b, h.Description)
+	metadata := createString(&b, h.Metadata)
+
+	b.StartTable(headerNumFields)
+	b.AddOffset(headerName, name)
+	b.AddOffset(headerEnvelope, envelope)
+	b.AddUint8(headerGeometryType, uint8(h.GeometryType), 0)
+	b.AddBool(headerHasZ, h.HasZ, false)
+	b.AddBool(headerHasM, h.HasM, false)
+	b.AddBool(headerHasT, h.HasT, false)
+	b.AddBool(headerHasTM, h.HasTM, false)
+	b.AddOffset(headerColumns, columns)
+	b.AddUint64(headerFeaturesCount, h.FeaturesCount, 0)
+	// the default node size is 16, so 0, no index, is written.
+	b.AddUint16(headerIndexNodeSize, h.IndexNodeSize, 16)
+	b.AddOffset(headerCRS, crs)
+	b.AddOffset(headerTitle, title)
+	b.AddOffset(headerDescription, description)
+	b.AddOffset(headerMetadata, metadata)
+	buf := b.FinishSizePrefixed(b.EndTable())
+	return append([]byte(nil), buf...), nil
+}
+
+// UnmarshalBinary decodes the header from a FlatBuffers table, without the
+// size prefix.
+//
+// Possible errors:
+//
+//	ErrInvalidHeader
+func (h *Header) UnmarshalBinary(data []byte) (err error) {
+	defer func() {
+		if err != nil {
+			err = ErrInvalidHeader
+		}
+	}()
+	defer flatbuffers.Catch(&err)
+
+	t := flatbuffers.Root(data)
+	*h = Header{
+		Name:          t.String(headerName),
+		GeometryType:  GeometryType(t.Uint8(headerGeometryType, 0)),
+		HasZ:          t.Bool(headerHasZ, false),
+		HasM:          t.Bool(headerHasM, false),
+		HasT:          t.Bool(headerHasT, false),
+		HasTM:         t.Bool(headerHasTM, false),
+		Columns:       readColumns(t.Tables(headerColumns)),
+		FeaturesCount: t.Uint64(headerFeaturesCount, 0),
+		IndexNodeSize: t.Uint16(headerIndexNodeSize, 16),
+		Title:         t.String(headerTitle),
+		Description:   t.String(headerDescription),
+		Metadata:      t.String(headerMetadata),
+	}
+	// the envelope may have the z and m ranges after the x and y ones.
+	if envelope := t.Float64s(headerEnvelope); len(envelope) >= 4 {
+		h.Envelope = &geom.Extent{envelope[0], envelope[1], envelope[2], envelope[3]}
+	}
+	if crs, ok := t.Table(headerCRS); ok {
+		h.CRS = &CRS{
+			Org:         crs.String(crsOrg),
+			Code:        crs.Int32(crsCode, 0),
+			Name:        crs.String(crsName),
+			Description: crs.String(crsDescription),
+			WKT:         crs.String(crsWKT),
+			CodeString:  crs.String(crsCodeString),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package flatgeobuf
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestHeader(t *testing.T) {
+	type tcase struct {
+		header Header
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			data, err := tc.header.MarshalBinary()
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			var got Header
+			// the size prefix is not part of the table
+			if err := got.UnmarshalBinary(data[4:]); err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.header) {
+				t.Errorf("header, expected %+v got %+v", tc.header, got)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"empty": {},
+		"default node size": {
+			header: Header{IndexNodeSize: 16},
+		},
+		"full": {
+			header: Header{
+				Name:         "countries",
+				Envelope:     &geom.Extent{-180, -90, 180, 83.6},
+				GeometryType: GeometryTypeMultiPolygon,
+				HasZ:         true,
+				HasM:         true,
+				Columns: []Column{
+					{Name: "id", Type: ColumnTypeLong, NotNull: true, Unique: true, PrimaryKey: true},
+					{Name: "name", Type: ColumnTypeString, Title: "Name", Description: "the name", Width: 80, Metadata: `{"lang":"en"}`},
+					{Name: "area", Type: ColumnTypeDouble, Precision: 10, Scale: 2},
+				},
+				FeaturesCount: 250,
+				IndexNodeSize: 8,
+				CRS: &CRS{
+					Org:         "EPSG",
+					Code:        4326,
+					Name:        "WGS 84",
+					Description: "World Geodetic System 1984",
+					WKT:         `GEOGCS["WGS 84"]`,
+					CodeString:  "4326",
+				},
+				Title:       "Countries",
+				Description: "The countries of the world",
+				Metadata:    `{"source":"test"}`,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	var h Header
+	if err := h.UnmarshalBinary([]byte{12, 0, 0, 0}); err != ErrInvalidHeader {
+		t.Errorf("error, expected %v got %v", ErrInvalidHeader, err)
+	}
+}
@@ -0,0 +1,462 @@
+package flatgeobuf
+
+import (
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding"
+	"github.com/go-spatial/geom/encoding/flatgeobuf/internal/flatbuffers"
+)
+
+// The fields of the geometry table.
+const (
+	geometryEnds = iota
+	geometryXY
+	geometryZ
+	geometryM
+	geometryT
+	geometryTM
+	geometryType
+	geometryParts
+	geometryNumFields
+)
+
+// geometry is a geometry as it is stored; the coordinates of all the points
+// in flat arrays, the ends, in points, of the parts of line strings and
+// polygons, and the geometries of multi polygons and collections as parts.
+type geometry struct {
+	typ   GeometryType
+	ends  []uint32
+	xy    []float64
+	z, m  []float64
+	parts []geometry
+}
+
+// dims are whether the points of a geometry have z and m values.
+type dims struct{ z, m bool }
+
+// The points of all the dimensions are converted to and from {x, y, z, m}.
+func fromXY(p [2]float64) [4]float64   { return [4]float64{p[0], p[1]} }
+func fromXYZ(p [3]float64) [4]float64  { return [4]float64{p[0], p[1], p[2]} }
+func fromXYM(p [3]float64) [4]float64  { return [4]float64{p[0], p[1], 0, p[2]} }
+func fromXYZM(p [4]float64) [4]float64 { return p }
+func toXY(p [4]float64) [2]float64     { return [2]float64{p[0], p[1]} }
+func toXYZ(p [4]float64) [3]float64    { return [3]float64{p[0], p[1], p[2]} }
+func toXYM(p [4]float64) [3]float64    { return [3]float64{p[0], p[1], p[3]} }
+func toXYZM(p [4]float64) [4]float64   { return p }
+
+func convertPoints[P, Q any](pts []P, conv func(P) Q) []Q {
+	if pts == nil {
+		return nil
+	}
+	out := make([]Q, len(pts))
+	for i := range pts {
+		out[i] = conv(pts[i])
+	}
+	return out
+}
+
+func convertLines[P, Q any](lines [][]P, conv func(P) Q) [][]Q {
+	if lines == nil {
+		return nil
+	}
+	out := make([][]Q, len(lines))
+	for i := range lines {
+		out[i] = convertPoints(lines[i], conv)
+	}
+	return out
+}
+
+func convertPolygons[P, Q any](plys [][][]P, conv func(P) Q) [][][]Q {
+	if plys == nil {
+		return nil
+	}
+	out := make([][][]Q, len(plys))
+	for i := range plys {
+		out[i] = convertLines(plys[i], conv)
+	}
+	return out
+}
+
+// addPoints appends the points to the coordinates of the geometry.
+func (g *geometry) addPoints(d dims, pts [][4]float64) {
+	for _, p := range pts {
+		g.xy = append(g.xy, p[0], p[1])
+		if d.z {
+			g.z = append(g.z, p[2])
+		}
+		if d.m {
+			g.m = append(g.m, p[3])
+		}
+	}
+}
+
+// addLines appends the lines as parts, ending at ends; closing them if they
+// are rings. There are no ends for a single line.
+func (g *geometry) addLines(d dims, lines [][][4]float64, rings bool) {
+	var n uint32
+	for _, line := range lines {
+		g.addPoints(d, line)
+		n += uint32(len(line))
+		if rings && len(line) > 0 && (line[0][0] != line[len(line)-1][0] || line[0][1] != line[len(line)-1][1]) {
+			g.addPoints(d, line[:1])
+			n++
+		}
+		g.ends = append(g.ends, n)
+	}
+	if len(g.ends) == 1 {
+		g.ends = nil
+	}
+}
+
+// fromGeom returns the stored form of the geometry, whose points have to have
+// the given dimensions.
+//
+// Possible errors:
+//
+//	ErrDimensionMismatch
+//	encoding.ErrUnknownGeometry
+func fromGeom(geo geom.Geometry, d dims) (g geometry, err error) {
+	var (
+		gd     dims
+		pts    [][4]float64
+		lines  [][][4]float64
+		plys   [][][][4]float64
+		xy     = dims{}
+		xyz    = dims{z: true}
+		xym    = dims{m: true}
+		xyzm   = dims{z: true, m: true}
+		single = func(p [4]float64) [][4]float64 { return [][4]float64{p} }
+	)
+	switch gg := geo.(type) {
+	case geom.Point:
+		g.typ, gd, pts = GeometryTypePoint, xy, single(fromXY(gg))
+	case geom.PointZ:
+		g.typ, gd, pts = GeometryTypePoint, xyz, single(fromXYZ(gg))
+	case geom.PointM:
+		g.typ, gd, pts = GeometryTypePoint, xym, single(fromXYM(gg))
+	case geom.PointZM:
+		g.typ, gd, pts = GeometryTypePoint, xyzm, single(fromXYZM(gg))
+	case geom.MultiPointZ:
+		g.typ, gd, pts = GeometryTypeMultiPoint, xyz, convertPoints(gg, fromXYZ)
+	case geom.MultiPointM:
+		g.typ, gd, pts = GeometryTypeMultiPoint, xym, convertPoints(gg, fromXYM)
+	case geom.MultiPointZM:
+		g.typ, gd, pts = GeometryTypeMultiPoint, xyzm, convertPoints(gg, fromXYZM)
+	case geom.LineStringZ:
+		g.typ, gd, pts = GeometryTypeLineString, xyz, convertPoints(gg, fromXYZ)
+	case geom.LineStringM:
+		g.typ, gd, pts = GeometryTypeLineString, xym, convertPoints(gg, fromXYM)
+	case geom.LineStringZM:
+		g.typ, gd, pts = GeometryTypeLineString, xyzm, convertPoints(gg, fromXYZM)
+	case geom.MultiLineStringZ:
+		g.typ, gd, lines = GeometryTypeMultiLineString, xyz, convertLines(gg, fromXYZ)
+	case geom.MultiLineStringM:
+		g.typ, gd, lines = GeometryTypeMultiLineString, xym, convertLines(gg, fromXYM)
+	case geom.MultiLineStringZM:
+		g.typ, gd, lines = GeometryTypeMultiLineString, xyzm, convertLines(gg, fromXYZM)
+	case geom.PolygonZ:
+		g.typ, gd, lines = GeometryTypePolygon, xyz, convertLines(gg, fromXYZ)
+	case geom.PolygonM:
+		g.typ, gd, lines = GeometryTypePolygon, xym, convertLines(gg, fromXYM)
+	case geom.PolygonZM:
+		g.typ, gd, lines = GeometryTypePolygon, xyzm, convertLines(gg, fromXYZM)
+	case MultiPolygonZ:
+		g.typ, gd, plys = GeometryTypeMultiPolygon, xyz, convertPolygons(gg, fromXYZ)
+	case MultiPolygonM:
+		g.typ, gd, plys = GeometryTypeMultiPolygon, xym, convertPolygons(gg, fromXYM)
+	case MultiPolygonZM:
+		g.typ, gd, plys = GeometryTypeMultiPolygon, xyzm, convertPolygons(gg, fromXYZM)
+
+	// the interfaces of the two dimensional geometries.
+	case geom.Pointer:
+		g.typ, gd, pts = GeometryTypePoint, xy, single(fromXY(gg.XY()))
+	case geom.MultiPointer:
+		g.typ, gd, pts = GeometryTypeMultiPoint, xy, convertPoints(gg.Points(), fromXY)
+	case geom.LineStringer:
+		g.typ, gd, pts = GeometryTypeLineString, xy, convertPoints(gg.Vertices(), fromXY)
+	case geom.MultiLineStringer:
+		g.typ, gd, lines = GeometryTypeMultiLineString, xy, convertLines(gg.LineStrings(), fromXY)
+	case geom.Polygoner:
+		g.typ, gd, lines = GeometryTypePolygon, xy, convertLines(gg.LinearRings(), fromXY)
+	case geom.MultiPolygoner:
+		g.typ, gd, plys = GeometryTypeMultiPolygon, xy, convertPolygons(gg.Polygons(), fromXY)
+	case geom.Collectioner:
+		g.typ = GeometryTypeGeometryCollection
+		geos := gg.Geometries()
+		g.parts = make([]geometry, len(geos))
+		for i := range geos {
+			if g.parts[i], err = fromGeom(geos[i], d); err != nil {
+				return g, err
+			}
+		}
+		return g, nil
+	default:
+		return g, encoding.ErrUnknownGeometry{Geom: geo}
+	}
+	if gd != d {
+		return g, ErrDimensionMismatch
+	}
+
+	switch g.typ {
+	case GeometryTypePoint, GeometryTypeMultiPoint, GeometryTypeLineString:
+		g.addPoints(d, pts)
+	case GeometryTypeMultiLineString:
+		g.addLines(d, lines, false)
+	case GeometryTypePolygon:
+		g.addLines(d, lines, true)
+	case GeometryTypeMultiPolygon:
+		g.parts = make([]geometry, len(plys))
+		for i := range plys {
+			g.parts[i].typ = GeometryTypePolygon
+			g.parts[i].addLines(d, plys[i], true)
+		}
+	}
+	return g, nil
+}
+
+// points returns the points of the geometry's coordinates.
+//
+// Possible errors:
+//
+//	ErrInvalidGeometry
+func (g *geometry) points(d dims) ([][4]float64, error) {
+	n := len(g.xy) / 2
+	if len(g.xy)%2 != 0 || (d.z && len(g.z) != n) || (d.m && len(g.m) != n) {
+		return nil, ErrInvalidGeometry
+	}
+	pts := make([][4]float64, n)
+	for i := range pts {
+		pts[i][0], pts[i][1] = g.xy[2*i], g.xy[2*i+1]
+		if d.z {
+			pts[i][2] = g.z[i]
+		}
+		if d.m {
+			pts[i][3] = g.m[i]
+		}
+	}
+	return pts, nil
+}
+
+// lines returns the points of the geometry split at the ends; with the
+// closing point of the rings removed.
+//
+// Possible errors:
+//
+//	ErrInvalidGeometry
+func (g *geometry) lines(d dims, rings bool) ([][][4]float64, error) {
+	pts, err := g.points(d)
+	if err != nil {
+		return nil, err
+	}
+	ends := g.ends
+	if len(ends) == 0 {
+		if len(pts) == 0 {
+			return [][][4]float64{}, nil
+		}
+		ends = []uint32{uint32(len(pts))}
+	}
+	lines := make([][][4]float64, len(ends))
+	var start uint32
+	for i, end := range ends {
+		if end < start || end > uint32(len(pts)) {
+			return nil, ErrInvalidGeometry
+		}
+		line := pts[start:end:end]
+		if rings && len(line) > 1 && line[0][0] == line[len(line)-1][0] && line[0][1] == line[len(line)-1][1] {
+			line = line[:len(line)-1]
+		}
+		lines[i] = line
+		start = end
+	}
+	return lines, nil
+}
+
+// toGeom returns the geometry, of the given type, for the stored form.
+//
+// Possible errors:
+//
+//	ErrInvalidGeometry
+//	ErrUnsupportedGeometryType
+func toGeom(g *geometry, typ GeometryType, d dims) (geom.Geometry, error) {
+	switch typ {
+	case GeometryTypePoint:
+		pts, err := g.points(d)
+		if err != nil || len(pts) > 1 {
+			return nil, ErrInvalidGeometry
+		}
+		if len(pts) == 0 {
+			// an empty point
+			return nil, nil
+		}
+		switch d {
+		case dims{}:
+			return geom.Point(toXY(pts[0])), nil
+		case dims{z: true}:
+			return geom.PointZ(toXYZ(pts[0])), nil
+		case dims{m: true}:
+			return geom.PointM(toXYM(pts[0])), nil
+		default:
+			return geom.PointZM(toXYZM(pts[0])), nil
+		}
+
+	case GeometryTypeMultiPoint, GeometryTypeLineString:
+		pts, err := g.points(d)
+		if err != nil {
+			return nil, err
+		}
+		multi := typ == GeometryTypeMultiPoint
+		switch d {
+		case dims{}:
+			if multi {
+				return geom.MultiPoint(convertPoints(pts, toXY)), nil
+			}
+			return geom.LineString(convertPoints(pts, toXY)), nil
+		case dims{z: true}:
+			if multi {
+				return geom.MultiPointZ(convertPoints(pts, toXYZ)), nil
+			}
+			return geom.LineStringZ(convertPoints(pts, toXYZ)), nil
+		case dims{m: true}:
+			if multi {
+				return geom.MultiPointM(convertPoints(pts, toXYM)), nil
+			}
+			return geom.LineStringM(convertPoints(pts, toXYM)), nil
+		default:
+			if multi {
+				return geom.MultiPointZM(convertPoints(pts, toXYZM)), nil
+			}
+			return geom.LineStringZM(convertPoints(pts, toXYZM)), nil
+		}
+
+	case GeometryTypeMultiLineString, GeometryTypePolygon:
+		polygon := typ == GeometryTypePolygon
+		lines, err := g.lines(d, polygon)
+		if err != nil {
+			return nil, err
+		}
+		switch d {
+		case dims{}:
+			if polygon {
+				return geom.Polygon(convertLines(lines, toXY)), nil
+			}
+			return geom.MultiLineString(convertLines(lines, toXY)), nil
+		case dims{z: true}:
+			if polygon {
+				return geom.PolygonZ(convertLines(lines, toXYZ)), nil
+			}
+			return geom.MultiLineStringZ(convertLines(lines, toXYZ)), nil
+		case dims{m: true}:
+			if polygon {
+				return geom.PolygonM(convertLines(lines, toXYM)), nil
+			}
+			return geom.MultiLineStringM(convertLines(lines, toXYM)), nil
+		default:
+			if polygon {
+				return geom.PolygonZM(convertLines(lines, toXYZM)), nil
+			}
+			return geom.MultiLineStringZM(convertLines(lines, toXYZM)), nil
+		}
+
+	case GeometryTypeMultiPolygon:
+		plys := make([][][][4]float64, len(g.parts))
+		for i := range g.parts {
+			var err error
+			if plys[i], err = g.parts[i].lines(d, true); err != nil {
+				return nil, err
+			}
+		}
+		switch d {
+		case dims{}:
+			return geom.MultiPolygon(convertPolygons(plys, toXY)), nil
+		case dims{z: true}:
+			return MultiPolygonZ(convertPolygons(plys, toXYZ)), nil
+		case dims{m: true}:
+			return MultiPolygonM(convertPolygons(plys, toXYM)), nil
+		default:
+			return MultiPolygonZM(convertPolygons(plys, toXYZM)), nil
+		}
+
+	case GeometryTypeGeometryCollection:
+		col := make(geom.Collection, len(g.parts))
+		for i := range g.parts {
+			if g.parts[i].typ == GeometryTypeUnknown {
+				return nil, ErrInvalidGeometry
+			}
+			var err error
+			if col[i], err = toGeom(&g.parts[i], g.parts[i].typ, d); err != nil {
+				return nil, err
+			}
+		}
+		return col, nil
+
+	case GeometryTypeUnknown:
+		return nil, ErrInvalidGeometry
+	default:
+		return nil, ErrUnsupportedGeometryType
+	}
+}
+
+// buildGeometry adds the geometry, returning its offset; the type is only
+// added if withType is set.
+func buildGeometry(b *flatbuffers.Builder, g *geometry, withType bool) int {
+	var parts int
+	if len(g.parts) > 0 {
+		offs := make([]int, len(g.parts))
+		for i := range g.parts {
+			offs[i] = buildGeometry(b, &g.parts[i], true)
+		}
+		parts = b.CreateOffsets(offs)
+	}
+	var ends, xy, z, m int
+	if len(g.ends) > 0 {
+		ends = b.CreateUint32s(g.ends)
+	}
+	if len(g.xy) > 0 {
+		xy = b.CreateFloat64s(g.xy)
+	}
+	if len(g.z) > 0 {
+		z = b.CreateFloat64s(g.z)
+	}
+	if len(g.m) > 0 {
+		m = b.CreateFloat64s(g.m)
+	}
+
+	b.StartTable(geometryNumFields)
+	b.AddOffset(geometryEnds, ends)
+	b.AddOffset(geometryXY, xy)
+	b.AddOffset(geometryZ, z)
+	b.AddOffset(geometryM, m)
+	if withType {
+		b.AddUint8(geometryType, uint8(g.typ), 0)
+	}
+	b.AddOffset(geometryParts, parts)
+	return b.EndTable()
+}
+
+// readGeometry returns the stored form of the geometry table. It panics if
+// the table is outside of its buffer; see flatbuffers.Catch.
+//
+// Possible errors:
+//
+//	ErrInvalidGeometry
+func readGeometry(t flatbuffers.Table, depth int) (geometry, error) {
+	if depth > maxDepth {
+		return geometry{}, ErrInvalidGeometry
+	}
+	g := geometry{
+		typ:  GeometryType(t.Uint8(geometryType, 0)),
+		ends: t.Uint32s(geometryEnds),
+		xy:   t.Float64s(geometryXY),
+		z:    t.Float64s(geometryZ),
+		m:    t.Float64s(geometryM),
+	}
+	if parts := t.Tables(geometryParts); len(parts) > 0 {
+		g.parts = make([]geometry, len(parts))
+		for i := range parts {
+			var err error
+			if g.parts[i], err = readGeometry(parts[i], depth+1); err != nil {
+				return g, err
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,230 @@
+package flatgeobuf
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding"
+	"github.com/go-spatial/geom/encoding/flatgeobuf/internal/flatbuffers"
+)
+
+func TestGeometry(t *testing.T) {
+	type tcase struct {
+		geo  geom.Geometry
+		dims dims
+		// expected is the decoded geometry; if nil, geo
+		expected geom.Geometry
+		// ends are the expected ends
+		ends []uint32
+		err  error
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			g, err := fromGeom(tc.geo, tc.dims)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if !reflect.DeepEqual(g.ends, tc.ends) {
+				t.Errorf("ends, expected %v got %v", tc.ends, g.ends)
+			}
+
+			// through a buffer
+			var b flatbuffers.Builder
+			b.Reset()
+			buf := b.Finish(buildGeometry(&b, &g, true))
+			stored, err := readGeometry(flatbuffers.Root(buf), 0)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			got, err := toGeom(&stored, stored.typ, tc.dims)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			expected := tc.expected
+			if expected == nil {
+				expected = tc.geo
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("geometry, expected %#v got %#v", expected, got)
+			}
+		}
+	}
+	var (
+		z  = dims{z: true}
+		m  = dims{m: true}
+		zm = dims{z: true, m: true}
+	)
+	tests := map[string]tcase{
+		"point":    {geo: geom.Point{1, 2}},
+		"point z":  {geo: geom.PointZ{1, 2, 3}, dims: z},
+		"point m":  {geo: geom.PointM{1, 2, 4}, dims: m},
+		"point zm": {geo: geom.PointZM{1, 2, 3, 4}, dims: zm},
+		"point pointer": {
+			geo:      &geom.Point{1, 2},
+			expected: geom.Point{1, 2},
+		},
+		"multi point":    {geo: geom.MultiPoint{{1, 2}, {3, 4}}},
+		"multi point z":  {geo: geom.MultiPointZ{{1, 2, 3}, {4, 5, 6}}, dims: z},
+		"multi point m":  {geo: geom.MultiPointM{{1, 2, 3}, {4, 5, 6}}, dims: m},
+		"multi point zm": {geo: geom.MultiPointZM{{1, 2, 3, 4}, {5, 6, 7, 8}}, dims: zm},
+		"line string":    {geo: geom.LineString{{1, 2}, {3, 4}, {5, 6}}},
+		"line string z":  {geo: geom.LineStringZ{{1, 2, 3}, {4, 5, 6}}, dims: z},
+		"line string m":  {geo: geom.LineStringM{{1, 2, 3}, {4, 5, 6}}, dims: m},
+		"line string zm": {geo: geom.LineStringZM{{1, 2, 3, 4}, {5, 6, 7, 8}}, dims: zm},
+		"multi line string": {
+			geo:  geom.MultiLineString{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}, {9, 10}}},
+			ends: []uint32{2, 5},
+		},
+		"single line multi line string": {
+			geo: geom.MultiLineString{{{1, 2}, {3, 4}}},
+		},
+		"multi line string z": {
+			geo:  geom.MultiLineStringZ{{{1, 2, 3}, {4, 5, 6}}, {{7, 8, 9}, {10, 11, 12}}},
+			dims: z,
+			ends: []uint32{2, 4},
+		},
+		"multi line string m": {
+			geo:  geom.MultiLineStringM{{{1, 2, 3}, {4, 5, 6}}, {{7, 8, 9}, {10, 11, 12}}},
+			dims: m,
+			ends: []uint32{2, 4},
+		},
+		"multi line string zm": {
+			geo:  geom.MultiLineStringZM{{{1, 2, 3, 4}, {5, 6, 7, 8}}, {{9, 10, 11, 12}, {13, 14, 15, 16}}},
+			dims: zm,
+			ends: []uint32{2, 4},
+		},
+		"polygon": {
+			// the rings are closed when stored
+			geo:  geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, {{2, 2}, {2, 4}, {4, 4}}},
+			ends: []uint32{5, 9},
+		},
+		"closed polygon": {
+			geo:      geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 0}}},
+			expected: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}}},
+		},
+		"polygon z": {
+			geo:  geom.PolygonZ{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}},
+			dims: z,
+		},
+		"polygon m": {
+			geo:  geom.PolygonM{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}},
+			dims: m,
+		},
+		"polygon zm": {
+			geo:  geom.PolygonZM{{{0, 0, 1, 5}, {10, 0, 2, 6}, {10, 10, 3, 7}}},
+			dims: zm,
+		},
+		"multi polygon": {
+			geo: geom.MultiPolygon{
+				{{{0, 0}, {10, 0}, {10, 10}}},
+				{{{20, 20}, {30, 20}, {30, 30}}, {{22, 21}, {28, 21}, {28, 27}}},
+			},
+		},
+		"multi polygon z": {
+			geo:  MultiPolygonZ{{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}}},
+			dims: z,
+		},
+		"multi polygon m": {
+			geo:  MultiPolygonM{{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}}},
+			dims: m,
+		},
+		"multi polygon zm": {
+			geo:  MultiPolygonZM{{{{0, 0, 1, 4}, {10, 0, 2, 5}, {10, 10, 3, 6}}}},
+			dims: zm,
+		},
+		"collection": {
+			geo: geom.Collection{
+				geom.Point{1, 2},
+				geom.LineString{{1, 2}, {3, 4}},
+				geom.Collection{geom.Polygon{{{0, 0}, {10, 0}, {10, 10}}}},
+			},
+		},
+		"collection z": {
+			geo:  geom.Collection{geom.PointZ{1, 2, 3}, MultiPolygonZ{{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}}}},
+			dims: z,
+		},
+		"empty collection": {
+			geo: geom.Collection{},
+		},
+		"dimension mismatch": {
+			geo:  geom.PointZ{1, 2, 3},
+			dims: m,
+			err:  ErrDimensionMismatch,
+		},
+		"dimension mismatch in collection": {
+			geo: geom.Collection{geom.Point{1, 2}, geom.PointZ{1, 2, 3}},
+			err: ErrDimensionMismatch,
+		},
+		"unknown geometry": {
+			geo: geom.Circle{Center: [2]float64{0, 0}, Radius: 1},
+			err: encoding.ErrUnknownGeometry{Geom: geom.Circle{Center: [2]float64{0, 0}, Radius: 1}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestInvalidGeometry(t *testing.T) {
+	type tcase struct {
+		g    geometry
+		typ  GeometryType
+		dims dims
+		err  error
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if _, err := toGeom(&tc.g, tc.typ, tc.dims); err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"odd coordinates": {
+			g:   geometry{xy: []float64{1, 2, 3}},
+			typ: GeometryTypeLineString,
+			err: ErrInvalidGeometry,
+		},
+		"missing z": {
+			g:    geometry{xy: []float64{1, 2}},
+			typ:  GeometryTypePoint,
+			dims: dims{z: true},
+			err:  ErrInvalidGeometry,
+		},
+		"point with two points": {
+			g:   geometry{xy: []float64{1, 2, 3, 4}},
+			typ: GeometryTypePoint,
+			err: ErrInvalidGeometry,
+		},
+		"end past the points": {
+			g:   geometry{xy: []float64{1, 2, 3, 4}, ends: []uint32{1, 3}},
+			typ: GeometryTypeMultiLineString,
+			err: ErrInvalidGeometry,
+		},
+		"decreasing ends": {
+			g:   geometry{xy: []float64{1, 2, 3, 4}, ends: []uint32{2, 1}},
+			typ: GeometryTypeMultiLineString,
+			err: ErrInvalidGeometry,
+		},
+		"collection part without a type": {
+			g:   geometry{parts: []geometry{{xy: []float64{1, 2}}}},
+			typ: GeometryTypeGeometryCollection,
+			err: ErrInvalidGeometry,
+		},
+		"unknown": {
+			typ: GeometryTypeUnknown,
+			err: ErrInvalidGeometry,
+		},
+		"curve": {
+			typ: GeometryTypeCircularString,
+			err: ErrUnsupportedGeometryType,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
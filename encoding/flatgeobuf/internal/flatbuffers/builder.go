@@ -0,0 +1,312 @@
+/*
+Package flatbuffers is the small part of the FlatBuffers binary format needed
+by FlatGeobuf; a builder of buffers and an accessor of the tables in them.
+
+A buffer is built back to front, so the children of a table, its strings,
+vectors and sub tables, are added before the table; the offsets the builder
+returns are from the end of the buffer.
+*/
+package flatbuffers
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Builder builds a buffer. The zero value is ready to use.
+type Builder struct {
+	// the data is buf[head:]
+	buf      []byte
+	head     int
+	minAlign int
+	// vtable is the offset of each field of the current table; 0 for the
+	// fields that have not been added.
+	vtable    []int
+	objectEnd int
+}
+
+// Reset clears the builder so it can build a new buffer, reusing its memory.
+func (b *Builder) Reset() {
+	b.head = len(b.buf)
+	b.minAlign = 0
+	b.vtable = b.vtable[:0]
+}
+
+// Offset returns the offset, from the end of the buffer, of the last value
+// added.
+func (b *Builder) Offset() int { return len(b.buf) - b.head }
+
+// grow makes room for n more bytes in front of the data.
+func (b *Builder) grow(n int) {
+	if b.head >= n {
+		return
+	}
+	size := 2 * len(b.buf)
+	if size < len(b.buf)+n {
+		size = len(b.buf) + n
+	}
+	if size < 64 {
+		size = 64
+	}
+	buf := make([]byte, size)
+	copy(buf[size-b.Offset():], b.buf[b.head:])
+	b.head += size - len(b.buf)
+	b.buf = buf
+}
+
+func (b *Builder) pad(n int) {
+	b.grow(n)
+	for i := 0; i < n; i++ {
+		b.head--
+		b.buf[b.head] = 0
+	}
+}
+
+// prep aligns the buffer so that a value of the given size, added after
+// additional bytes, is aligned to its size.
+func (b *Builder) prep(size, additional int) {
+	if size > b.minAlign {
+		b.minAlign = size
+	}
+	b.pad((-(b.Offset() + additional)) & (size - 1))
+	b.grow(size + additional)
+}
+
+func (b *Builder) placeUint8(v uint8) {
+	b.head--
+	b.buf[b.head] = v
+}
+
+func (b *Builder) placeUint16(v uint16) {
+	b.head -= 2
+	binary.LittleEndian.PutUint16(b.buf[b.head:], v)
+}
+
+func (b *Builder) placeUint32(v uint32) {
+	b.head -= 4
+	binary.LittleEndian.PutUint32(b.buf[b.head:], v)
+}
+
+func (b *Builder) placeUint64(v uint64) {
+	b.head -= 8
+	binary.LittleEndian.PutUint64(b.buf[b.head:], v)
+}
+
+// PrependUint8 adds the value.
+func (b *Builder) PrependUint8(v uint8) {
+	b.prep(1, 0)
+	b.placeUint8(v)
+}
+
+// PrependUint16 adds the value.
+func (b *Builder) PrependUint16(v uint16) {
+	b.prep(2, 0)
+	b.placeUint16(v)
+}
+
+// PrependUint32 adds the value.
+func (b *Builder) PrependUint32(v uint32) {
+	b.prep(4, 0)
+	b.placeUint32(v)
+}
+
+// PrependUint64 adds the value.
+func (b *Builder) PrependUint64(v uint64) {
+	b.prep(8, 0)
+	b.placeUint64(v)
+}
+
+// PrependUOffset adds an offset to the value at the given offset, which has
+// to have been added before.
+func (b *Builder) PrependUOffset(off int) {
+	b.prep(4, 0)
+	b.placeUint32(uint32(b.Offset() - off + 4))
+}
+
+// startVector prepares for n elements of the given size.
+func (b *Builder) startVector(elemSize, n, alignment int) {
+	b.prep(4, elemSize*n)
+	b.prep(alignment, elemSize*n)
+}
+
+// endVector adds the length of a vector of n elements, returning the offset
+// of the vector.
+func (b *Builder) endVector(n int) int {
+	b.prep(4, 0)
+	b.placeUint32(uint32(n))
+	return b.Offset()
+}
+
+// CreateString adds the string, returning its offset.
+func (b *Builder) CreateString(s string) int {
+	b.prep(4, len(s)+1)
+	b.placeUint8(0)
+	b.head -= len(s)
+	copy(b.buf[b.head:], s)
+	return b.endVector(len(s))
+}
+
+// CreateBytes adds the vector of bytes, returning its offset.
+func (b *Builder) CreateBytes(v []byte) int {
+	b.startVector(1, len(v), 1)
+	b.head -= len(v)
+	copy(b.buf[b.head:], v)
+	return b.endVector(len(v))
+}
+
+// CreateUint32s adds the vector of uint32s, returning its offset.
+func (b *Builder) CreateUint32s(v []uint32) int {
+	b.startVector(4, len(v), 4)
+	for i := len(v) - 1; i >= 0; i-- {
+		b.placeUint32(v[i])
+	}
+	return b.endVector(len(v))
+}
+
+// CreateUint64s adds the vector of uint64s, returning its offset.
+func (b *Builder) CreateUint64s(v []uint64) int {
+	b.startVector(8, len(v), 8)
+	for i := len(v) - 1; i >= 0; i-- {
+		b.placeUint64(v[i])
+	}
+	return b.endVector(len(v))
+}
+
+// CreateFloat64s adds the vector of float64s, returning its offset.
+func (b *Builder) CreateFloat64s(v []float64) int {
+	b.startVector(8, len(v), 8)
+	for i := len(v) - 1; i >= 0; i-- {
+		b.placeUint64(math.Float64bits(v[i]))
+	}
+	return b.endVector(len(v))
+}
+
+// CreateOffsets adds the vector of offsets, to tables or strings added
+// before, returning its offset.
+func (b *Builder) CreateOffsets(offs []int) int {
+	b.startVector(4, len(offs), 4)
+	for i := len(offs) - 1; i >= 0; i-- {
+		b.PrependUOffset(offs[i])
+	}
+	return b.endVector(len(offs))
+}
+
+// StartTable starts a table with the given number of fields. The children of
+// the table have to be added before it is started.
+func (b *Builder) StartTable(numFields int) {
+	b.vtable = append(b.vtable[:0], make([]int, numFields)...)
+	b.objectEnd = b.Offset()
+}
+
+// slot records the last value added as the field.
+func (b *Builder) slot(field int) { b.vtable[field] = b.Offset() }
+
+// AddBool adds the field, unless it is the default.
+func (b *Builder) AddBool(field int, v, def bool) {
+	if v == def {
+		return
+	}
+	var u uint8
+	if v {
+		u = 1
+	}
+	b.PrependUint8(u)
+	b.slot(field)
+}
+
+// AddUint8 adds the field, unless it is the default.
+func (b *Builder) AddUint8(field int, v, def uint8) {
+	if v == def {
+		return
+	}
+	b.PrependUint8(v)
+	b.slot(field)
+}
+
+// AddUint16 adds the field, unless it is the default.
+func (b *Builder) AddUint16(field int, v, def uint16) {
+	if v == def {
+		return
+	}
+	b.PrependUint16(v)
+	b.slot(field)
+}
+
+// AddInt32 adds the field, unless it is the default.
+func (b *Builder) AddInt32(field int, v, def int32) {
+	if v == def {
+		return
+	}
+	b.PrependUint32(uint32(v))
+	b.slot(field)
+}
+
+// AddUint64 adds the field, unless it is the default.
+func (b *Builder) AddUint64(field int, v, def uint64) {
+	if v == def {
+		return
+	}
+	b.PrependUint64(v)
+	b.slot(field)
+}
+
+// AddOffset adds the field, an offset to a value added before the table was
+// started; unless the offset is 0.
+func (b *Builder) AddOffset(field int, off int) {
+	if off == 0 {
+		return
+	}
+	b.PrependUOffset(off)
+	b.slot(field)
+}
+
+// EndTable adds the vtable of the table, returning the offset of the table.
+func (b *Builder) EndTable() int {
+	b.PrependUint32(0)
+	table := b.Offset()
+
+	n := len(b.vtable)
+	for n > 0 && b.vtable[n-1] == 0 {
+		n--
+	}
+	for i := n - 1; i >= 0; i-- {
+		var off uint16
+		if b.vtable[i] != 0 {
+			off = uint16(table - b.vtable[i])
+		}
+		b.PrependUint16(off)
+	}
+	b.PrependUint16(uint16(table - b.objectEnd))
+	b.PrependUint16(uint16((n + 2) * 2))
+
+	// the table starts with the distance back to its vtable.
+	binary.LittleEndian.PutUint32(b.buf[len(b.buf)-table:], uint32(b.Offset()-table))
+	b.vtable = b.vtable[:0]
+	return table
+}
+
+// rootAlign returns the alignment of the buffer; the largest alignment of
+// its values.
+func (b *Builder) rootAlign() int {
+	if b.minAlign < 4 {
+		return 4
+	}
+	return b.minAlign
+}
+
+// Finish adds the offset to the root table, returning the buffer. The buffer
+// is only valid until the builder is reset.
+func (b *Builder) Finish(root int) []byte {
+	b.prep(b.rootAlign(), 4)
+	b.PrependUOffset(root)
+	return b.buf[b.head:]
+}
+
+// FinishSizePrefixed is like Finish, with the buffer starting with its size
+// as a uint32.
+func (b *Builder) FinishSizePrefixed(root int) []byte {
+	b.prep(b.rootAlign(), 8)
+	b.PrependUOffset(root)
+	b.PrependUint32(uint32(b.Offset()))
+	return b.buf[b.head:]
+}
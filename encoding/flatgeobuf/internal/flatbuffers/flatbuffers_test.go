@@ -0,0 +1,116 @@
+package flatbuffers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildRead(t *testing.T) {
+	var b Builder
+	for i := 0; i < 2; i++ {
+		// the second time reuses the memory
+		b.Reset()
+
+		b.StartTable(2)
+		b.AddUint8(1, 7, 0)
+		child := b.EndTable()
+		name := b.CreateString("name")
+		values := b.CreateFloat64s([]float64{1.5, -2, 3})
+		ends := b.CreateUint32s([]uint32{3, 5})
+		ids := b.CreateUint64s([]uint64{1 << 40})
+		data := b.CreateBytes([]byte{1, 2, 3})
+		children := b.CreateOffsets([]int{child, child})
+
+		b.StartTable(12)
+		b.AddOffset(0, name)
+		b.AddOffset(1, values)
+		b.AddBool(2, true, false)
+		b.AddUint16(3, 16, 16)
+		b.AddInt32(4, -5, -1)
+		b.AddUint64(5, 42, 0)
+		b.AddOffset(6, ends)
+		b.AddOffset(7, ids)
+		b.AddOffset(8, data)
+		b.AddOffset(9, child)
+		b.AddOffset(10, children)
+		buf := b.Finish(b.EndTable())
+
+		root := Root(buf)
+		if got := root.String(0); got != "name" {
+			t.Errorf("string, expected name got %q", got)
+		}
+		if got := root.Float64s(1); !reflect.DeepEqual(got, []float64{1.5, -2, 3}) {
+			t.Errorf("float64s, expected [1.5 -2 3] got %v", got)
+		}
+		if !root.Bool(2, false) {
+			t.Errorf("bool, expected true got false")
+		}
+		if root.Has(3) {
+			t.Errorf("default, expected to not be in the table")
+		}
+		if got := root.Uint16(3, 16); got != 16 {
+			t.Errorf("uint16, expected the default 16 got %v", got)
+		}
+		if got := root.Int32(4, -1); got != -5 {
+			t.Errorf("int32, expected -5 got %v", got)
+		}
+		if got := root.Uint64(5, 0); got != 42 {
+			t.Errorf("uint64, expected 42 got %v", got)
+		}
+		if got := root.Uint32s(6); !reflect.DeepEqual(got, []uint32{3, 5}) {
+			t.Errorf("uint32s, expected [3 5] got %v", got)
+		}
+		if got := root.Uint64s(7); !reflect.DeepEqual(got, []uint64{1 << 40}) {
+			t.Errorf("uint64s, expected [%v] got %v", uint64(1<<40), got)
+		}
+		if got := root.Bytes(8); !reflect.DeepEqual(got, []byte{1, 2, 3}) {
+			t.Errorf("bytes, expected [1 2 3] got %v", got)
+		}
+		c, ok := root.Table(9)
+		if !ok || c.Uint8(1, 0) != 7 || c.Has(0) {
+			t.Errorf("table, expected field 1 of 7 got %v, %v", ok, c.Uint8(1, 0))
+		}
+		if got := root.Tables(10); len(got) != 2 || got[1].Uint8(1, 0) != 7 {
+			t.Errorf("tables, expected 2 tables with field 1 of 7 got %v", got)
+		}
+		// past the fields of the vtable
+		if root.Has(11) || root.Bytes(11) != nil {
+			t.Errorf("missing field, expected to not be in the table")
+		}
+	}
+}
+
+func TestSizePrefixed(t *testing.T) {
+	var b Builder
+	b.StartTable(1)
+	b.AddUint8(0, 1, 0)
+	buf := b.FinishSizePrefixed(b.EndTable())
+	if n := int(uint32At(buf, 0)); n != len(buf)-4 {
+		t.Errorf("size, expected %v got %v", len(buf)-4, n)
+	}
+	if got := Root(buf[4:]).Uint8(0, 0); got != 1 {
+		t.Errorf("uint8, expected 1 got %v", got)
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	read := func(buf []byte) (err error) {
+		defer Catch(&err)
+		Root(buf).String(0)
+		return nil
+	}
+	var b Builder
+	// no padding after the string, which ends in a 0 that is not read
+	name := b.CreateString("eleven char")
+	b.StartTable(1)
+	b.AddOffset(0, name)
+	buf := b.Finish(b.EndTable())
+	if err := read(buf); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	for n := 0; n < len(buf)-1; n++ {
+		if err := read(buf[:n]); err != ErrOutOfRange {
+			t.Errorf("%v bytes: error, expected %v got %v", n, ErrOutOfRange, err)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package flatbuffers
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// ErrOutOfRange is the error of the accessors of a buffer whose offsets point
+// outside of it.
+var ErrOutOfRange = errors.New("flatbuffers: offset out of range")
+
+// outOfRange is the panic of the accessors; see Catch.
+type outOfRange struct{}
+
+// Catch recovers from the panic of an accessor reading outside of its buffer,
+// setting err to ErrOutOfRange. It has to be deferred:
+//
+//	defer flatbuffers.Catch(&err)
+func Catch(err *error) {
+	if r := recover(); r != nil {
+		if _, ok := r.(outOfRange); !ok {
+			panic(r)
+		}
+		*err = ErrOutOfRange
+	}
+}
+
+// check panics unless n bytes, at pos, are in the buffer.
+func check(buf []byte, pos, n int) {
+	if pos < 0 || n < 0 || pos > len(buf)-n {
+		panic(outOfRange{})
+	}
+}
+
+func uint16At(buf []byte, pos int) uint16 {
+	check(buf, pos, 2)
+	return binary.LittleEndian.Uint16(buf[pos:])
+}
+
+func uint32At(buf []byte, pos int) uint32 {
+	check(buf, pos, 4)
+	return binary.LittleEndian.Uint32(buf[pos:])
+}
+
+func uint64At(buf []byte, pos int) uint64 {
+	check(buf, pos, 8)
+	return binary.LittleEndian.Uint64(buf[pos:])
+}
+
+// Table is a table of a buffer. The accessors panic when reading outside of
+// the buffer; see Catch.
+type Table struct {
+	buf []byte
+	pos int
+}
+
+// Root returns the root table of the buffer.
+func Root(buf []byte) Table {
+	return Table{buf: buf, pos: int(uint32At(buf, 0))}
+}
+
+// field returns the offset, from the start of the table, of the field; 0
+// when the field is not in the table.
+func (t Table) field(field int) int {
+	vtable := t.pos - int(int32(uint32At(t.buf, t.pos)))
+	o := 4 + 2*field
+	if o >= int(uint16At(t.buf, vtable)) {
+		return 0
+	}
+	return int(uint16At(t.buf, vtable+o))
+}
+
+// Has returns whether the field is in the table.
+func (t Table) Has(field int) bool { return t.field(field) != 0 }
+
+// Bool returns the field, or the default if it is not in the table.
+func (t Table) Bool(field int, def bool) bool {
+	o := t.field(field)
+	if o == 0 {
+		return def
+	}
+	check(t.buf, t.pos+o, 1)
+	return t.buf[t.pos+o] != 0
+}
+
+// Uint8 returns the field, or the default if it is not in the table.
+func (t Table) Uint8(field int, def uint8) uint8 {
+	o := t.field(field)
+	if o == 0 {
+		return def
+	}
+	check(t.buf, t.pos+o, 1)
+	return t.buf[t.pos+o]
+}
+
+// Uint16 returns the field, or the default if it is not in the table.
+func (t Table) Uint16(field int, def uint16) uint16 {
+	o := t.field(field)
+	if o == 0 {
+		return def
+	}
+	return uint16At(t.buf, t.pos+o)
+}
+
+// Int32 returns the field, or the default if it is not in the table.
+func (t Table) Int32(field int, def int32) int32 {
+	o := t.field(field)
+	if o == 0 {
+		return def
+	}
+	return int32(uint32At(t.buf, t.pos+o))
+}
+
+// Uint64 returns the field, or the default if it is not in the table.
+func (t Table) Uint64(field int, def uint64) uint64 {
+	o := t.field(field)
+	if o == 0 {
+		return def
+	}
+	return uint64At(t.buf, t.pos+o)
+}
+
+// indirect returns the position the offset field points at, or 0 if the
+// field is not in the table.
+func (t Table) indirect(field int) int {
+	o := t.field(field)
+	if o == 0 {
+		return 0
+	}
+	return t.pos + o + int(uint32At(t.buf, t.pos+o))
+}
+
+// vector returns the position of the first element, and the number of
+// elements, of the vector field; checking the elements are in the buffer.
+func (t Table) vector(field, elemSize int) (pos, n int) {
+	p := t.indirect(field)
+	if p == 0 {
+		return 0, 0
+	}
+	n = int(uint32At(t.buf, p))
+	if n > len(t.buf)/elemSize {
+		panic(outOfRange{})
+	}
+	check(t.buf, p+4, n*elemSize)
+	return p + 4, n
+}
+
+// String returns the string field, or "" if it is not in the table.
+func (t Table) String(field int) string {
+	return string(t.Bytes(field))
+}
+
+// Bytes returns the vector of bytes field, or nil if it is not in the table.
+// The bytes are not copied.
+func (t Table) Bytes(field int) []byte {
+	p, n := t.vector(field, 1)
+	if p == 0 {
+		return nil
+	}
+	return t.buf[p : p+n : p+n]
+}
+
+// Uint32s returns the vector of uint32s field, or nil if it is not in the
+// table.
+func (t Table) Uint32s(field int) []uint32 {
+	p, n := t.vector(field, 4)
+	if p == 0 {
+		return nil
+	}
+	v := make([]uint32, n)
+	for i := range v {
+		v[i] = binary.LittleEndian.Uint32(t.buf[p+4*i:])
+	}
+	return v
+}
+
+// Uint64s returns the vector of uint64s field, or nil if it is not in the
+// table.
+func (t Table) Uint64s(field int) []uint64 {
+	p, n := t.vector(field, 8)
+	if p == 0 {
+		return nil
+	}
+	v := make([]uint64, n)
+	for i := range v {
+		v[i] = binary.LittleEndian.Uint64(t.buf[p+8*i:])
+	}
+	return v
+}
+
+// Float64s returns the vector of float64s field, or nil if it is not in the
+// table.
+func (t Table) Float64s(field int) []float64 {
+	p, n := t.vector(field, 8)
+	if p == 0 {
+		return nil
+	}
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = math.Float64frombits(binary.LittleEndian.Uint64(t.buf[p+8*i:]))
+	}
+	return v
+}
+
+// Table returns the table field, and whether it is in the table.
+func (t Table) Table(field int) (Table, bool) {
+	p := t.indirect(field)
+	if p == 0 {
+		return Table{}, false
+	}
+	return Table{buf: t.buf, pos: p}, true
+}
+
+// Tables returns the vector of tables field, or nil if it is not in the
+// table.
+func (t Table) Tables(field int) []Table {
+	p, n := t.vector(field, 4)
+	if p == 0 {
+		return nil
+	}
+	v := make([]Table, n)
+	for i := range v {
+		pos := p + 4*i
+		v[i] = Table{buf: t.buf, pos: pos + int(binary.LittleEndian.Uint32(t.buf[pos:]))}
+	}
+	return v
+}
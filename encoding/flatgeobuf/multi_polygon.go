@@ -0,0 +1,23 @@
+package flatgeobuf
+
+// The geom package does not have multi polygons with z or m values, so the
+// ones read from a file are returned as these types; they can also be
+// written.
+
+// MultiPolygonZ is a geometry of multiple polygons with z values.
+type MultiPolygonZ [][][][3]float64
+
+// PolygonZs returns the polygons.
+func (mp MultiPolygonZ) PolygonZs() [][][][3]float64 { return mp }
+
+// MultiPolygonM is a geometry of multiple polygons with m values.
+type MultiPolygonM [][][][3]float64
+
+// PolygonMs returns the polygons.
+func (mp MultiPolygonM) PolygonMs() [][][][3]float64 { return mp }
+
+// MultiPolygonZM is a geometry of multiple polygons with z and m values.
+type MultiPolygonZM [][][][4]float64
+
+// PolygonZMs returns the polygons.
+func (mp MultiPolygonZM) PolygonZMs() [][][][4]float64 { return mp }
@@ -0,0 +1,297 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"time"
+)
+
+// dateTimeLayouts are the layouts tried when reading a DateTime value; the
+// values are ISO 8601, which may leave out the time zone.
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// encodeProperties returns the properties encoded for the columns; each
+// property is the index of the column as a uint16 and the value, with strings,
+// JSON, date times and binary values prefixed with their length as a uint32.
+// Properties with a nil value are left out.
+//
+// Possible errors:
+//
+//	ErrUnknownColumn
+//	ErrInvalidPropertyValue
+func encodeProperties(buf []byte, columns []Column, index map[string]int, props map[string]interface{}) ([]byte, error) {
+	for name := range props {
+		if _, ok := index[name]; !ok {
+			return nil, ErrUnknownColumn
+		}
+	}
+	le := binary.LittleEndian
+	for i, c := range columns {
+		v, ok := props[c.Name]
+		if !ok || v == nil {
+			continue
+		}
+		buf = le.AppendUint16(buf, uint16(i))
+
+		var err error
+		switch c.Type {
+		case ColumnTypeByte:
+			var n int64
+			if n, err = toInt(v, math.MinInt8, math.MaxInt8); err == nil {
+				buf = append(buf, byte(int8(n)))
+			}
+		case ColumnTypeUByte:
+			var n uint64
+			if n, err = toUint(v, math.MaxUint8); err == nil {
+				buf = append(buf, byte(n))
+			}
+		case ColumnTypeBool:
+			b, ok := v.(bool)
+			if !ok {
+				return nil, ErrInvalidPropertyValue
+			}
+			if b {
+				buf = append(buf, 1)
+			} else {
+				buf = append(buf, 0)
+			}
+		case ColumnTypeShort:
+			var n int64
+			if n, err = toInt(v, math.MinInt16, math.MaxInt16); err == nil {
+				buf = le.AppendUint16(buf, uint16(n))
+			}
+		case ColumnTypeUShort:
+			var n uint64
+			if n, err = toUint(v, math.MaxUint16); err == nil {
+				buf = le.AppendUint16(buf, uint16(n))
+			}
+		case ColumnTypeInt:
+			var n int64
+			if n, err = toInt(v, math.MinInt32, math.MaxInt32); err == nil {
+				buf = le.AppendUint32(buf, uint32(n))
+			}
+		case ColumnTypeUInt:
+			var n uint64
+			if n, err = toUint(v, math.MaxUint32); err == nil {
+				buf = le.AppendUint32(buf, uint32(n))
+			}
+		case ColumnTypeLong:
+			var n int64
+			if n, err = toInt(v, math.MinInt64, math.MaxInt64); err == nil {
+				buf = le.AppendUint64(buf, uint64(n))
+			}
+		case ColumnTypeULong:
+			var n uint64
+			if n, err = toUint(v, math.MaxUint64); err == nil {
+				buf = le.AppendUint64(buf, n)
+			}
+		case ColumnTypeFloat:
+			var f float64
+			if f, err = toFloat(v); err == nil {
+				buf = le.AppendUint32(buf, math.Float32bits(float32(f)))
+			}
+		case ColumnTypeDouble:
+			var f float64
+			if f, err = toFloat(v); err == nil {
+				buf = le.AppendUint64(buf, math.Float64bits(f))
+			}
+		case ColumnTypeString:
+			s, ok := v.(string)
+			if !ok {
+				return nil, ErrInvalidPropertyValue
+			}
+			buf = appendBytes(buf, []byte(s))
+		case ColumnTypeJSON:
+			var data []byte
+			switch vv := v.(type) {
+			case json.RawMessage:
+				data = vv
+			case string:
+				data = []byte(vv)
+			default:
+				if data, err = json.Marshal(v); err != nil {
+					return nil, ErrInvalidPropertyValue
+				}
+			}
+			buf = appendBytes(buf, data)
+		case ColumnTypeDateTime:
+			switch vv := v.(type) {
+			case time.Time:
+				buf = appendBytes(buf, []byte(vv.Format(time.RFC3339Nano)))
+			case string:
+				buf = appendBytes(buf, []byte(vv))
+			default:
+				return nil, ErrInvalidPropertyValue
+			}
+		case ColumnTypeBinary:
+			b, ok := v.([]byte)
+			if !ok {
+				return nil, ErrInvalidPropertyValue
+			}
+			buf = appendBytes(buf, b)
+		default:
+			return nil, ErrInvalidPropertyValue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func appendBytes(buf, b []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(b)))
+	return append(buf, b...)
+}
+
+// toInt returns the value of any of the integer types, if it is between min
+// and max.
+func toInt(v interface{}, min, max int64) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := rv.Int(); n >= min && n <= max {
+			return n, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := rv.Uint(); n <= uint64(max) {
+			return int64(n), nil
+		}
+	}
+	return 0, ErrInvalidPropertyValue
+}
+
+// toUint returns the value of any of the integer types, if it is between 0
+// and max.
+func toUint(v interface{}, max uint64) (uint64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := rv.Int(); n >= 0 && uint64(n) <= max {
+			return uint64(n), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := rv.Uint(); n <= max {
+			return n, nil
+		}
+	}
+	return 0, ErrInvalidPropertyValue
+}
+
+// toFloat returns the value of any of the float or integer types.
+func toFloat(v interface{}) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	}
+	return 0, ErrInvalidPropertyValue
+}
+
+// decodeProperties returns the properties encoded for the columns; see
+// encodeProperties. A DateTime value that is not in one of the
+// dateTimeLayouts is returned as a string.
+//
+// Possible errors:
+//
+//	ErrInvalidFeature
+func decodeProperties(data []byte, columns []Column) (map[string]interface{}, error) {
+	props := make(map[string]interface{})
+	le := binary.LittleEndian
+	// next returns the next n bytes of the data.
+	next := func(n int) ([]byte, bool) {
+		if n < 0 || n > len(data) {
+			return nil, false
+		}
+		b := data[:n:n]
+		data = data[n:]
+		return b, true
+	}
+	for len(data) > 0 {
+		b, ok := next(2)
+		if !ok {
+			return nil, ErrInvalidFeature
+		}
+		i := int(le.Uint16(b))
+		if i >= len(columns) {
+			return nil, ErrInvalidFeature
+		}
+		c := columns[i]
+
+		size := 0
+		switch c.Type {
+		case ColumnTypeByte, ColumnTypeUByte, ColumnTypeBool:
+			size = 1
+		case ColumnTypeShort, ColumnTypeUShort:
+			size = 2
+		case ColumnTypeInt, ColumnTypeUInt, ColumnTypeFloat:
+			size = 4
+		case ColumnTypeLong, ColumnTypeULong, ColumnTypeDouble:
+			size = 8
+		case ColumnTypeString, ColumnTypeJSON, ColumnTypeDateTime, ColumnTypeBinary:
+			if b, ok = next(4); !ok {
+				return nil, ErrInvalidFeature
+			}
+			size = int(le.Uint32(b))
+		default:
+			return nil, ErrInvalidFeature
+		}
+		if b, ok = next(size); !ok {
+			return nil, ErrInvalidFeature
+		}
+
+		var v interface{}
+		switch c.Type {
+		case ColumnTypeByte:
+			v = int8(b[0])
+		case ColumnTypeUByte:
+			v = b[0]
+		case ColumnTypeBool:
+			v = b[0] != 0
+		case ColumnTypeShort:
+			v = int16(le.Uint16(b))
+		case ColumnTypeUShort:
+			v = le.Uint16(b)
+		case ColumnTypeInt:
+			v = int32(le.Uint32(b))
+		case ColumnTypeUInt:
+			v = le.Uint32(b)
+		case ColumnTypeLong:
+			v = int64(le.Uint64(b))
+		case ColumnTypeULong:
+			v = le.Uint64(b)
+		case ColumnTypeFloat:
+			v = math.Float32frombits(le.Uint32(b))
+		case ColumnTypeDouble:
+			v = math.Float64frombits(le.Uint64(b))
+		case ColumnTypeString:
+			v = string(b)
+		case ColumnTypeJSON:
+			v = json.RawMessage(append([]byte(nil), b...))
+		case ColumnTypeDateTime:
+			v = string(b)
+			for _, layout := range dateTimeLayouts {
+				if t, err := time.Parse(layout, string(b)); err == nil {
+					v = t
+					break
+				}
+			}
+		case ColumnTypeBinary:
+			v = append([]byte(nil), b...)
+		}
+		props[c.Name] = v
+	}
+	return props, nil
+}
@@ -0,0 +1,127 @@
+package flatgeobuf
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProperties(t *testing.T) {
+	columns := []Column{
+		{Name: "byte", Type: ColumnTypeByte},
+		{Name: "ubyte", Type: ColumnTypeUByte},
+		{Name: "bool", Type: ColumnTypeBool},
+		{Name: "short", Type: ColumnTypeShort},
+		{Name: "ushort", Type: ColumnTypeUShort},
+		{Name: "int", Type: ColumnTypeInt},
+		{Name: "uint", Type: ColumnTypeUInt},
+		{Name: "long", Type: ColumnTypeLong},
+		{Name: "ulong", Type: ColumnTypeULong},
+		{Name: "float", Type: ColumnTypeFloat},
+		{Name: "double", Type: ColumnTypeDouble},
+		{Name: "string", Type: ColumnTypeString},
+		{Name: "json", Type: ColumnTypeJSON},
+		{Name: "datetime", Type: ColumnTypeDateTime},
+		{Name: "binary", Type: ColumnTypeBinary},
+	}
+	index := make(map[string]int)
+	for i, c := range columns {
+		index[c.Name] = i
+	}
+
+	type tcase struct {
+		props    map[string]interface{}
+		expected map[string]interface{}
+		err      error
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			data, err := encodeProperties(nil, columns, index, tc.props)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			got, err := decodeProperties(data, columns)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("properties, expected %#v got %#v", tc.expected, got)
+			}
+		}
+	}
+	date := time.Date(2020, 2, 29, 13, 14, 15, 500, time.UTC)
+	tests := map[string]tcase{
+		"typed": {
+			props: map[string]interface{}{
+				"byte": int8(-5), "ubyte": uint8(250), "bool": true,
+				"short": int16(-300), "ushort": uint16(60000),
+				"int": int32(-70000), "uint": uint32(4000000000),
+				"long": int64(-1 << 40), "ulong": uint64(1 << 63),
+				"float": float32(1.5), "double": 2.25,
+				"string": "café", "json": json.RawMessage(`{"a":[1,2]}`),
+				"datetime": date, "binary": []byte{0, 1, 2},
+			},
+			expected: map[string]interface{}{
+				"byte": int8(-5), "ubyte": uint8(250), "bool": true,
+				"short": int16(-300), "ushort": uint16(60000),
+				"int": int32(-70000), "uint": uint32(4000000000),
+				"long": int64(-1 << 40), "ulong": uint64(1 << 63),
+				"float": float32(1.5), "double": 2.25,
+				"string": "café", "json": json.RawMessage(`{"a":[1,2]}`),
+				"datetime": date, "binary": []byte{0, 1, 2},
+			},
+		},
+		"converted": {
+			props: map[string]interface{}{
+				"byte": 5, "long": uint8(7), "double": 3, "json": []int{1, 2},
+				"datetime": "2020-02-29T13:14:15", "string": nil,
+			},
+			expected: map[string]interface{}{
+				"byte": int8(5), "long": int64(7), "double": 3.0, "json": json.RawMessage(`[1,2]`),
+				"datetime": time.Date(2020, 2, 29, 13, 14, 15, 0, time.UTC),
+			},
+		},
+		"not a date": {
+			props:    map[string]interface{}{"datetime": "yesterday"},
+			expected: map[string]interface{}{"datetime": "yesterday"},
+		},
+		"none": {
+			expected: map[string]interface{}{},
+		},
+		"unknown column": {
+			props: map[string]interface{}{"other": 1},
+			err:   ErrUnknownColumn,
+		},
+		"out of range": {
+			props: map[string]interface{}{"ubyte": 256},
+			err:   ErrInvalidPropertyValue,
+		},
+		"negative unsigned": {
+			props: map[string]interface{}{"uint": -1},
+			err:   ErrInvalidPropertyValue,
+		},
+		"wrong type": {
+			props: map[string]interface{}{"string": 1},
+			err:   ErrInvalidPropertyValue,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	for name, data := range map[string][]byte{
+		"short index":  {1},
+		"bad column":   {15, 0, 1},
+		"short value":  {3, 0, 1},
+		"short length": {11, 0, 5, 0},
+		"long length":  {11, 0, 5, 0, 0, 0, 'a'},
+	} {
+		if _, err := decodeProperties(data, columns); err != ErrInvalidFeature {
+			t.Errorf("%v: error, expected %v got %v", name, ErrInvalidFeature, err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package flatgeobuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/index/packedrtree"
+)
+
+// Reader reads the features of a file, in order. The file is read as a
+// stream, so a reader can read a file being downloaded; bytes that are not
+// needed are skipped by seeking if the underlying reader is an io.Seeker.
+type Reader struct {
+	r      io.Reader
+	header Header
+	// indexSize is the size of the index; which is read by Filter, or
+	// skipped by the first Next.
+	indexSize uint64
+	started   bool
+
+	// pos is the position in the features.
+	pos uint64
+	// read is the number of features read.
+	read uint64
+	// offsets are the offsets of the features left to read, when filtered.
+	offsets  []uint64
+	filtered bool
+}
+
+// NewReader returns a reader of the file, having read its header.
+//
+// Possible errors:
+//
+//	ErrInvalidMagic
+//	ErrInvalidHeader
+func NewReader(r io.Reader) (*Reader, error) {
+	var start [12]byte
+	if _, err := io.ReadFull(r, start[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrInvalidMagic
+		}
+		return nil, err
+	}
+	// the patch version, the last byte, is not checked.
+	if !bytes.Equal(start[:7], magic[:7]) {
+		return nil, ErrInvalidMagic
+	}
+	size := binary.LittleEndian.Uint32(start[8:])
+	if size > maxHeaderSize {
+		return nil, ErrInvalidHeader
+	}
+	data, err := readN(r, uint64(size))
+	if err != nil {
+		return nil, ErrInvalidHeader
+	}
+	rd := &Reader{r: r}
+	if err := rd.header.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	if rd.header.IndexNodeSize == 1 {
+		return nil, ErrInvalidHeader
+	}
+	if rd.header.IndexNodeSize > 0 && rd.header.FeaturesCount > 0 {
+		rd.indexSize = packedrtree.Size(rd.header.FeaturesCount, rd.header.IndexNodeSize)
+		if rd.indexSize == 0 {
+			return nil, ErrInvalidHeader
+		}
+	}
+	return rd, nil
+}
+
+// readN returns the next n bytes of r; the buffer grows as the bytes are read,
+// so a bad size does not allocate more memory than the data there is.
+func readN(r io.Reader, n uint64) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(n)); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// skip skips the next n bytes of the reader.
+func (rd *Reader) skip(n uint64) error {
+	if n == 0 {
+		return nil
+	}
+	if s, ok := rd.r.(io.Seeker); ok {
+		_, err := s.Seek(int64(n), io.SeekCurrent)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, rd.r, int64(n))
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Header returns the header of the file.
+func (rd *Reader) Header() Header { return rd.header }
+
+// Filter limits the features read by Next to the ones whose geometry's
+// extent intersects the extent, using the index of the file. It has to be
+// called before the first call to Next.
+//
+// Possible errors:
+//
+//	ErrNoIndex
+//	ErrAlreadyReading
+//	ErrInvalidHeader
+func (rd *Reader) Filter(e *geom.Extent) error {
+	if rd.started {
+		return ErrAlreadyReading
+	}
+	if rd.header.IndexNodeSize == 0 {
+		return ErrNoIndex
+	}
+	rd.started, rd.filtered = true, true
+	if rd.header.FeaturesCount == 0 {
+		return nil
+	}
+	nodes, err := readN(rd.r, rd.indexSize)
+	if err != nil {
+		return err
+	}
+	tree, err := packedrtree.FromNodes(nodes, rd.header.FeaturesCount, rd.header.IndexNodeSize)
+	if err != nil {
+		// the index does not match the header.
+		return ErrInvalidHeader
+	}
+	rd.offsets = tree.SearchOffsets(e)
+	// the features are read in the order they are in the file.
+	sort.Slice(rd.offsets, func(i, j int) bool { return rd.offsets[i] < rd.offsets[j] })
+	return nil
+}
+
+// Next returns the next feature; io.EOF is returned after the last one.
+//
+// Possible errors:
+//
+//	io.EOF
+//	io.ErrUnexpectedEOF
+//	ErrInvalidFeature
+//	ErrInvalidGeometry
+//	ErrUnsupportedGeometryType
+func (rd *Reader) Next() (*Feature, error) {
+	if !rd.started {
+		rd.started = true
+		if err := rd.skip(rd.indexSize); err != nil {
+			return nil, err
+		}
+	}
+	if rd.filtered {
+		if len(rd.offsets) == 0 {
+			return nil, io.EOF
+		}
+		offset := rd.offsets[0]
+		rd.offsets = rd.offsets[1:]
+		if offset < rd.pos {
+			return nil, ErrInvalidFeature
+		}
+		if err := rd.skip(offset - rd.pos); err != nil {
+			return nil, err
+		}
+		rd.pos = offset
+	} else if rd.header.FeaturesCount > 0 && rd.read >= rd.header.FeaturesCount {
+		return nil, io.EOF
+	}
+
+	var prefix [4]byte
+	if _, err := io.ReadFull(rd.r, prefix[:]); err != nil {
+		// the end of a file with an unknown number of features.
+		if errors.Is(err, io.EOF) && !rd.filtered && rd.header.FeaturesCount == 0 {
+			return nil, io.EOF
+		}
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	size := binary.LittleEndian.Uint32(prefix[:])
+	data, err := readN(rd.r, uint64(size))
+	if err != nil {
+		return nil, err
+	}
+	rd.pos += 4 + uint64(size)
+	rd.read++
+	return decodeFeature(data, &rd.header)
+}
@@ -0,0 +1,207 @@
+package flatgeobuf
+
+import (
+	"io"
+	"os"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/flatgeobuf/internal/flatbuffers"
+	"github.com/go-spatial/geom/planar/index/packedrtree"
+)
+
+// Writer writes a file.
+//
+// Without an index the header is written by NewWriter, and each feature as it
+// is written; the FeaturesCount and Envelope of the header are written as
+// given. With an index, which has to come before the features, the features
+// are buffered in a temporary file and the file is written by Close; the
+// features are written in the order of the index, and the FeaturesCount and
+// Envelope of the header are set from the features.
+type Writer struct {
+	w      io.Writer
+	header Header
+	dims   dims
+	// columns is the index of each column, by name.
+	columns map[string]int
+	b       flatbuffers.Builder
+	props   []byte
+
+	// the features of an indexed file, in the temporary file.
+	tmp   *os.File
+	items []packedrtree.Item
+	sizes []uint64
+
+	closed bool
+}
+
+// NewWriter returns a writer of a file to w, with the header. The caller
+// has to call Close.
+//
+// Possible errors:
+//
+//	packedrtree.ErrInvalidNodeSize
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	if h.IndexNodeSize == 1 {
+		return nil, packedrtree.ErrInvalidNodeSize
+	}
+	wr := &Writer{
+		w:       w,
+		header:  h,
+		dims:    dims{z: h.HasZ, m: h.HasM},
+		columns: make(map[string]int, len(h.Columns)),
+	}
+	for i, c := range h.Columns {
+		wr.columns[c.Name] = i
+	}
+	if h.IndexNodeSize > 0 {
+		tmp, err := os.CreateTemp("", "flatgeobuf-*")
+		if err != nil {
+			return nil, err
+		}
+		wr.tmp = tmp
+		return wr, nil
+	}
+	if err := wr.writeHeader(); err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
+
+// writeHeader writes the magic bytes and the header.
+func (w *Writer) writeHeader() error {
+	hdr, err := w.header.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(magic[:]); err != nil {
+		return err
+	}
+	_, err = w.w.Write(hdr)
+	return err
+}
+
+// Write writes the feature. The type and dimensions of the geometry have to
+// be the ones of the header, unless the header's geometry type is
+// GeometryTypeUnknown. The properties have to be in the columns of the
+// header.
+//
+// Possible errors:
+//
+//	ErrClosed
+//	ErrGeometryTypeMismatch
+//	ErrDimensionMismatch
+//	ErrNoGeometry
+//	ErrUnknownColumn
+//	ErrInvalidPropertyValue
+//	encoding.ErrUnknownGeometry
+func (w *Writer) Write(f Feature) error {
+	if w.closed {
+		return ErrClosed
+	}
+	var (
+		g      geometry
+		extent *geom.Extent
+		err    error
+	)
+	if f.Geometry != nil {
+		if g, err = fromGeom(f.Geometry, w.dims); err != nil {
+			return err
+		}
+		if w.header.GeometryType != GeometryTypeUnknown && g.typ != w.header.GeometryType {
+			return ErrGeometryTypeMismatch
+		}
+		extent = g.extent()
+	}
+	if w.tmp != nil && extent == nil {
+		return ErrNoGeometry
+	}
+	if w.props, err = encodeProperties(w.props[:0], w.header.Columns, w.columns, f.Properties); err != nil {
+		return err
+	}
+
+	w.b.Reset()
+	var geo, props int
+	if f.Geometry != nil {
+		geo = buildGeometry(&w.b, &g, w.header.GeometryType == GeometryTypeUnknown)
+	}
+	if len(w.props) > 0 {
+		props = w.b.CreateBytes(w.props)
+	}
+	w.b.StartTable(featureNumFields)
+	w.b.AddOffset(featureGeometry, geo)
+	w.b.AddOffset(featureProperties, props)
+	data := w.b.FinishSizePrefixed(w.b.EndTable())
+
+	if w.tmp == nil {
+		_, err := w.w.Write(data)
+		return err
+	}
+	if _, err := w.tmp.Write(data); err != nil {
+		return err
+	}
+	w.items = append(w.items, packedrtree.Item{Extent: *extent, Offset: uint64(len(w.items))})
+	w.sizes = append(w.sizes, uint64(len(data)))
+	return nil
+}
+
+// Close finishes the file; for an indexed file it writes the file, and removes
+// the temporary file. It does not close the underlying writer.
+func (w *Writer) Close() (err error) {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	if w.tmp == nil {
+		return nil
+	}
+	defer func() {
+		name := w.tmp.Name()
+		if cerr := w.tmp.Close(); err == nil {
+			err = cerr
+		}
+		if rerr := os.Remove(name); err == nil {
+			err = rerr
+		}
+	}()
+
+	w.header.FeaturesCount = uint64(len(w.items))
+	if len(w.items) == 0 {
+		w.header.Envelope = nil
+		return w.writeHeader()
+	}
+
+	// the offset of each feature in the temporary file.
+	offsets := make([]uint64, len(w.items))
+	for i := 1; i < len(offsets); i++ {
+		offsets[i] = offsets[i-1] + w.sizes[i-1]
+	}
+	// the features are written in the order of the tree, so the offsets of
+	// the items are the offsets in that order.
+	packedrtree.SortItems(w.items)
+	order := make([]uint64, len(w.items))
+	var offset uint64
+	for i := range w.items {
+		order[i] = w.items[i].Offset
+		w.items[i].Offset = offset
+		offset += w.sizes[order[i]]
+	}
+	tree, err := packedrtree.New(w.items, w.header.IndexNodeSize)
+	if err != nil {
+		return err
+	}
+	envelope := tree.Extent()
+	w.header.Envelope = &envelope
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(tree.Nodes()); err != nil {
+		return err
+	}
+	for _, i := range order {
+		if _, err := io.Copy(w.w, io.NewSectionReader(w.tmp, int64(offsets[i]), int64(w.sizes[i]))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
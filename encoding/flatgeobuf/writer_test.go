@@ -0,0 +1,322 @@
+package flatgeobuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/index/packedrtree"
+)
+
+// stream hides the Seek method of the reader, so the bytes are skipped by
+// reading them.
+type stream struct{ io.Reader }
+
+func writeFile(t *testing.T, h Header, features []Feature) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, h)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	for _, f := range features {
+		if err := w.Write(f); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	return buf.Bytes()
+}
+
+func readAll(t *testing.T, rd *Reader) []Feature {
+	t.Helper()
+	var features []Feature
+	for {
+		f, err := rd.Next()
+		if err == io.EOF {
+			return features
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		features = append(features, *f)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	columns := []Column{
+		{Name: "name", Type: ColumnTypeString},
+		{Name: "rank", Type: ColumnTypeInt},
+	}
+	type tcase struct {
+		header   Header
+		features []Feature
+		// expected are the features read; if nil, the features
+		expected []Feature
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			data := writeFile(t, tc.header, tc.features)
+			if !bytes.Equal(data[:8], magic[:]) {
+				t.Errorf("magic, expected %v got %v", magic, data[:8])
+			}
+			expected := tc.expected
+			if expected == nil {
+				expected = tc.features
+			}
+			for name, r := range map[string]io.Reader{
+				"seeker": bytes.NewReader(data),
+				"stream": stream{bytes.NewReader(data)},
+			} {
+				rd, err := NewReader(r)
+				if err != nil {
+					t.Fatalf("%v: error, expected nil got %v", name, err)
+				}
+				if got := readAll(t, rd); !reflect.DeepEqual(got, expected) {
+					t.Errorf("%v: features, expected %v got %v", name, expected, got)
+				}
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"no features": {
+			header: Header{GeometryType: GeometryTypePoint},
+		},
+		"streamed": {
+			header: Header{GeometryType: GeometryTypePoint, Columns: columns},
+			features: []Feature{
+				{Geometry: geom.Point{1, 2}, Properties: map[string]interface{}{"name": "one", "rank": int32(1)}},
+				{Geometry: geom.Point{3, 4}, Properties: map[string]interface{}{"name": "two"}},
+				{Properties: map[string]interface{}{"rank": int32(3)}},
+			},
+		},
+		"mixed types": {
+			header: Header{HasZ: true},
+			features: []Feature{
+				{Geometry: geom.PointZ{1, 2, 3}, Properties: map[string]interface{}{}},
+				{Geometry: geom.LineStringZ{{1, 2, 3}, {4, 5, 6}}, Properties: map[string]interface{}{}},
+				{Geometry: geom.Collection{geom.PointZ{1, 2, 3}}, Properties: map[string]interface{}{}},
+			},
+		},
+		"multi polygon z": {
+			header: Header{GeometryType: GeometryTypeMultiPolygon, HasZ: true},
+			features: []Feature{
+				{Geometry: MultiPolygonZ{{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}}}, Properties: map[string]interface{}{}},
+			},
+		},
+		"multi polygon m": {
+			header: Header{GeometryType: GeometryTypeMultiPolygon, HasM: true},
+			features: []Feature{
+				{Geometry: MultiPolygonM{{{{0, 0, 1}, {10, 0, 2}, {10, 10, 3}}}}, Properties: map[string]interface{}{}},
+			},
+		},
+		"multi polygon zm": {
+			header: Header{GeometryType: GeometryTypeMultiPolygon, HasZ: true, HasM: true},
+			features: []Feature{
+				{Geometry: MultiPolygonZM{{{{0, 0, 1, 4}, {10, 0, 2, 5}, {10, 10, 3, 6}}}}, Properties: map[string]interface{}{}},
+			},
+		},
+		"indexed": {
+			header: Header{GeometryType: GeometryTypePolygon, Columns: columns, IndexNodeSize: 2},
+			features: []Feature{
+				{Geometry: geom.Polygon{{{10, 10}, {11, 10}, {11, 11}}}, Properties: map[string]interface{}{"name": "far"}},
+				{Geometry: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}, Properties: map[string]interface{}{"name": "near"}},
+			},
+			// in the order of the index
+			expected: []Feature{
+				{Geometry: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}, Properties: map[string]interface{}{"name": "near"}},
+				{Geometry: geom.Polygon{{{10, 10}, {11, 10}, {11, 11}}}, Properties: map[string]interface{}{"name": "far"}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestFilter(t *testing.T) {
+	var (
+		features []Feature
+		extents  = make(map[int]geom.Extent)
+	)
+	// a grid of small squares and a few long lines
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 30; y++ {
+			id := len(features)
+			fx, fy := float64(x), float64(y)
+			features = append(features, Feature{
+				Geometry:   geom.Polygon{{{fx, fy}, {fx + 0.5, fy}, {fx + 0.5, fy + 0.5}, {fx, fy + 0.5}}},
+				Properties: map[string]interface{}{"id": int64(id)},
+			})
+			extents[id] = geom.Extent{fx, fy, fx + 0.5, fy + 0.5}
+		}
+	}
+	for i := 0; i < 5; i++ {
+		id := len(features)
+		fi := float64(i * 6)
+		features = append(features, Feature{
+			Geometry:   geom.LineString{{0, fi}, {30, fi + 1}},
+			Properties: map[string]interface{}{"id": int64(id)},
+		})
+		extents[id] = geom.Extent{0, fi, 30, fi + 1}
+	}
+	h := Header{
+		Columns:       []Column{{Name: "id", Type: ColumnTypeLong}},
+		IndexNodeSize: packedrtree.DefaultNodeSize,
+	}
+	data := writeFile(t, h, features)
+
+	rd, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	got := rd.Header()
+	if got.FeaturesCount != uint64(len(features)) {
+		t.Errorf("features count, expected %v got %v", len(features), got.FeaturesCount)
+	}
+	if got.Envelope == nil || *got.Envelope != (geom.Extent{0, 0, 30, 29.5}) {
+		t.Errorf("envelope, expected %v got %v", geom.Extent{0, 0, 30, 29.5}, got.Envelope)
+	}
+
+	for _, e := range []geom.Extent{
+		{-10, -10, -5, -5},
+		{3.2, 3.2, 3.8, 3.8},
+		{3.2, 3.2, 7.7, 5.1},
+		{0, 0, 0, 0},
+		{12.5, 24.5, 12.5, 24.5},
+		{-100, -100, 100, 100},
+	} {
+		var expected []int64
+		for id, fe := range extents {
+			if fe.MinX() <= e.MaxX() && fe.MaxX() >= e.MinX() && fe.MinY() <= e.MaxY() && fe.MaxY() >= e.MinY() {
+				expected = append(expected, int64(id))
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+
+		for name, r := range map[string]io.Reader{
+			"seeker": bytes.NewReader(data),
+			"stream": stream{bytes.NewReader(data)},
+		} {
+			rd, err := NewReader(r)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if err := rd.Filter(&e); err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			var ids []int64
+			for _, f := range readAll(t, rd) {
+				ids = append(ids, f.Properties["id"].(int64))
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			if !reflect.DeepEqual(ids, expected) {
+				t.Errorf("%v %v: ids, expected %v got %v", name, e, expected, ids)
+			}
+		}
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := NewWriter(&buf, Header{IndexNodeSize: 1}); err != packedrtree.ErrInvalidNodeSize {
+		t.Errorf("error, expected %v got %v", packedrtree.ErrInvalidNodeSize, err)
+	}
+
+	w, err := NewWriter(&buf, Header{GeometryType: GeometryTypePoint, IndexNodeSize: 16})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	for name, tc := range map[string]struct {
+		feature Feature
+		err     error
+	}{
+		"type":           {feature: Feature{Geometry: geom.LineString{{0, 0}, {1, 1}}}, err: ErrGeometryTypeMismatch},
+		"dimensions":     {feature: Feature{Geometry: geom.PointZ{0, 0, 0}}, err: ErrDimensionMismatch},
+		"no geometry":    {feature: Feature{}, err: ErrNoGeometry},
+		"unknown column": {feature: Feature{Geometry: geom.Point{0, 0}, Properties: map[string]interface{}{"a": 1}}, err: ErrUnknownColumn},
+	} {
+		if err := w.Write(tc.feature); err != tc.err {
+			t.Errorf("%v: error, expected %v got %v", name, tc.err, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err := w.Write(Feature{Geometry: geom.Point{0, 0}}); err != ErrClosed {
+		t.Errorf("error, expected %v got %v", ErrClosed, err)
+	}
+	if err := w.Close(); err != ErrClosed {
+		t.Errorf("error, expected %v got %v", ErrClosed, err)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("fgb\x02fgb\x00\x00\x00\x00\x00"))); err != ErrInvalidMagic {
+		t.Errorf("error, expected %v got %v", ErrInvalidMagic, err)
+	}
+	if _, err := NewReader(bytes.NewReader([]byte("fgb"))); err != ErrInvalidMagic {
+		t.Errorf("error, expected %v got %v", ErrInvalidMagic, err)
+	}
+
+	features := []Feature{{Geometry: geom.Point{1, 2}}, {Geometry: geom.Point{3, 4}}}
+	data := writeFile(t, Header{GeometryType: GeometryTypePoint}, features)
+	if _, err := NewReader(bytes.NewReader(data[:20])); err != ErrInvalidHeader {
+		t.Errorf("error, expected %v got %v", ErrInvalidHeader, err)
+	}
+	rd, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err := rd.Filter(&geom.Extent{0, 0, 1, 1}); err != ErrNoIndex {
+		t.Errorf("error, expected %v got %v", ErrNoIndex, err)
+	}
+
+	data = writeFile(t, Header{GeometryType: GeometryTypePoint, IndexNodeSize: 16}, features)
+
+	// the offset of the root's first child, the first node after the header,
+	// pointing past the index
+	rd, err = NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	headerSize := 12 + int(binary.LittleEndian.Uint32(data[8:]))
+	corrupt := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint64(corrupt[headerSize+32:], 1000)
+	if rd, err = NewReader(bytes.NewReader(corrupt)); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err := rd.Filter(&geom.Extent{0, 0, 1, 1}); err != ErrInvalidHeader {
+		t.Errorf("error, expected %v got %v", ErrInvalidHeader, err)
+	}
+
+	// a features count whose index size overflows
+	h := Header{GeometryType: GeometryTypePoint, FeaturesCount: 1 << 62, IndexNodeSize: 16}
+	hdr, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if _, err := NewReader(bytes.NewReader(append(magic[:], hdr...))); err != ErrInvalidHeader {
+		t.Errorf("error, expected %v got %v", ErrInvalidHeader, err)
+	}
+
+	rd, err = NewReader(bytes.NewReader(data[:len(data)-3]))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if _, err := rd.Next(); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err := rd.Filter(&geom.Extent{0, 0, 1, 1}); err != ErrAlreadyReading {
+		t.Errorf("error, expected %v got %v", ErrAlreadyReading, err)
+	}
+	if _, err := rd.Next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("error, expected %v got %v", io.ErrUnexpectedEOF, err)
+	}
+}
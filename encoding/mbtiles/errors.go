@@ -0,0 +1,10 @@
+package mbtiles
+
+import "github.com/gdey/errors"
+
+const (
+	ErrTileNotFound    = errors.String("tile not found")
+	ErrNotMBTiles      = errors.String("file does not have a tiles table")
+	ErrInvalidMetadata = errors.String("invalid metadata")
+	ErrNilHandle       = errors.String("mbtiles handle is nil")
+)
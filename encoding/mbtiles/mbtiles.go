@@ -0,0 +1,423 @@
+//go:build cgo
+// +build cgo
+
+/*
+Package mbtiles reads and writes MBTiles 1.3 files; sqlite databases of map
+tiles.
+
+The tiles are addressed by slippy tiles; the flipping of the rows to the TMS
+scheme used by the files is done by the package. New files store each
+distinct tile blob once, with a map table pointing at it, and a tiles view
+for readers. Files using a plain tiles table can be read and written too.
+*/
+package mbtiles
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/go-spatial/geom/slippy"
+	// registers the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// ApplicationID is the application id of MBTiles files; "MPBX".
+	ApplicationID = 0x4d504258
+
+	// driverName is the name the sqlite driver is registered as by go-sqlite3.
+	driverName = "sqlite3"
+
+	// schemaSQL creates the tables of a new file, with the tiles
+	// deduplicated.
+	schemaSQL = `
+	CREATE TABLE IF NOT EXISTS metadata (
+		name TEXT NOT NULL PRIMARY KEY,
+		value TEXT
+	);
+	CREATE TABLE IF NOT EXISTS map (
+		zoom_level INTEGER NOT NULL,
+		tile_column INTEGER NOT NULL,
+		tile_row INTEGER NOT NULL,
+		tile_id TEXT NOT NULL,
+		PRIMARY KEY (zoom_level, tile_column, tile_row)
+	);
+	CREATE TABLE IF NOT EXISTS images (
+		tile_id TEXT NOT NULL PRIMARY KEY,
+		tile_data BLOB
+	);
+	CREATE VIEW IF NOT EXISTS tiles AS
+		SELECT
+			map.zoom_level AS zoom_level,
+			map.tile_column AS tile_column,
+			map.tile_row AS tile_row,
+			images.tile_data AS tile_data
+		FROM map JOIN images ON images.tile_id = map.tile_id;
+	`
+)
+
+var (
+	initialSQL = fmt.Sprintf(
+		`
+		PRAGMA application_id = %d;
+		`,
+		ApplicationID,
+	)
+)
+
+// Handle is the handle to an MBTiles file.
+type Handle struct {
+	*sql.DB
+	// deduplicated is true when the tiles are stored in the map and images
+	// tables.
+	deduplicated bool
+}
+
+// nonZeroFileExists checks if a file exists, has a size greater then zero
+// and is not a directory.
+func nonZeroFileExists(filename string) bool {
+	info, err := os.Stat(filename)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return info.Size() > 0
+}
+
+// Open opens an existing MBTiles file.
+//
+// Possible errors:
+//
+//	os.ErrNotExist
+//	ErrNotMBTiles
+func Open(filename string) (*Handle, error) {
+	if !nonZeroFileExists(filename) {
+		return nil, os.ErrNotExist
+	}
+	db, err := sql.Open(driverName, filename)
+	if err != nil {
+		return nil, err
+	}
+	h := &Handle{DB: db}
+
+	tables := make(map[string]string)
+	rows, err := db.Query(`SELECT name, type FROM sqlite_master WHERE type IN ('table', 'view')`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			db.Close()
+			return nil, err
+		}
+		tables[name] = typ
+	}
+	if err := rows.Err(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, ok := tables["tiles"]; !ok {
+		db.Close()
+		return nil, ErrNotMBTiles
+	}
+	h.deduplicated = tables["tiles"] == "view" && tables["map"] == "table" && tables["images"] == "table"
+	return h, nil
+}
+
+// New creates a new MBTiles file, which must not already exist.
+func New(filename string) (*Handle, error) {
+	// We will not overwrite an existing file.
+	if nonZeroFileExists(filename) {
+		return nil, os.ErrExist
+	}
+	db, err := sql.Open(driverName, filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, sql := range []string{initialSQL, schemaSQL} {
+		if _, err := db.Exec(sql); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return &Handle{DB: db, deduplicated: true}, nil
+}
+
+// Deduplicated returns whether identical tiles are stored once; true for
+// files created by New.
+func (h *Handle) Deduplicated() bool { return h != nil && h.deduplicated }
+
+// Metadata returns the content of the metadata table. Rows with a value that
+// can not be parsed are returned in Metadata.Other.
+func (h *Handle) Metadata() (*Metadata, error) {
+	if h == nil {
+		return nil, ErrNilHandle
+	}
+	rows, err := h.Query(`SELECT name, value FROM metadata`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	md := new(Metadata)
+	for rows.Next() {
+		var (
+			name  string
+			value sql.NullString
+		)
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		md.setRow(name, value.String)
+	}
+	return md, rows.Err()
+}
+
+// SetMetadata replaces the content of the metadata table.
+func (h *Handle) SetMetadata(md *Metadata) error {
+	if h == nil {
+		return ErrNilHandle
+	}
+	values, err := md.rows()
+	if err != nil {
+		return err
+	}
+	tx, err := h.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM metadata`); err != nil {
+		return err
+	}
+	for name, value := range values {
+		if _, err := tx.Exec(`INSERT INTO metadata (name, value) VALUES (?, ?)`, name, value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Tile returns the data of the tile.
+//
+// Possible errors:
+//
+//	ErrTileNotFound
+func (h *Handle) Tile(tile slippy.Tile) ([]byte, error) {
+	if h == nil {
+		return nil, ErrNilHandle
+	}
+	z, x, y := tile.TMS()
+	var data []byte
+	err := h.QueryRow(
+		`SELECT tile_data FROM tiles WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?`,
+		z, x, y,
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrTileNotFound
+	}
+	return data, err
+}
+
+// TileData is a tile and its data.
+type TileData struct {
+	Tile slippy.Tile
+	Data []byte
+}
+
+// tileID returns the id of the data for the images table.
+func tileID(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// execer is the part of sql.DB and sql.Tx used to put tiles.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func (h *Handle) putTile(db execer, tile slippy.Tile, data []byte) error {
+	z, x, y := tile.TMS()
+	if !h.deduplicated {
+		_, err := db.Exec(
+			`INSERT OR REPLACE INTO tiles (zoom_level, tile_column, tile_row, tile_data) VALUES (?, ?, ?, ?)`,
+			z, x, y, data,
+		)
+		return err
+	}
+	id := tileID(data)
+	if _, err := db.Exec(`INSERT OR IGNORE INTO images (tile_id, tile_data) VALUES (?, ?)`, id, data); err != nil {
+		return err
+	}
+	_, err := db.Exec(
+		`INSERT OR REPLACE INTO map (zoom_level, tile_column, tile_row, tile_id) VALUES (?, ?, ?, ?)`,
+		z, x, y, id,
+	)
+	return err
+}
+
+// PutTile adds, or replaces, the tile. Use PutTiles to add many tiles, as
+// each call is a transaction.
+func (h *Handle) PutTile(tile slippy.Tile, data []byte) error {
+	if h == nil {
+		return ErrNilHandle
+	}
+	return h.putTile(h.DB, tile, data)
+}
+
+// PutTiles adds, or replaces, the tiles in a single transaction.
+func (h *Handle) PutTiles(tiles ...TileData) error {
+	if h == nil {
+		return ErrNilHandle
+	}
+	tx, err := h.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, td := range tiles {
+		if err := h.putTile(tx, td.Tile, td.Data); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteTile removes the tile; the data of deduplicated files is removed
+// once no tile uses it.
+func (h *Handle) DeleteTile(tile slippy.Tile) error {
+	if h == nil {
+		return ErrNilHandle
+	}
+	z, x, y := tile.TMS()
+	if !h.deduplicated {
+		_, err := h.Exec(`DELETE FROM tiles WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?`, z, x, y)
+		return err
+	}
+	tx, err := h.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var id string
+	err = tx.QueryRow(`SELECT tile_id FROM map WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?`, z, x, y).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM map WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?`, z, x, y); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM images WHERE tile_id = ? AND NOT EXISTS (SELECT 1 FROM map WHERE tile_id = ?)`, id, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// EachTile calls fn with each tile and its data, ordered by zoom, column
+// and row, until fn returns false.
+func (h *Handle) EachTile(fn func(tile slippy.Tile, data []byte) bool) error {
+	if h == nil {
+		return ErrNilHandle
+	}
+	rows, err := h.Query(`SELECT zoom_level, tile_column, tile_row, tile_data FROM tiles ORDER BY zoom_level, tile_column, tile_row`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			z, x, y uint
+			data    []byte
+		)
+		if err := rows.Scan(&z, &x, &y, &data); err != nil {
+			return err
+		}
+		if !fn(slippy.FromTMS(slippy.Zoom(z), x, y), data) {
+			return nil
+		}
+	}
+	return rows.Err()
+}
+
+// Deduplicate moves the tiles of a file with a plain tiles table into the
+// map and images tables, storing each distinct tile blob once. It does
+// nothing for files that are already deduplicated.
+func (h *Handle) Deduplicate() (err error) {
+	if h == nil {
+		return ErrNilHandle
+	}
+	if h.deduplicated {
+		return nil
+	}
+	tx, err := h.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`ALTER TABLE tiles RENAME TO tiles_plain`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(schemaSQL); err != nil {
+		return err
+	}
+
+	// the tiles are read in batches, as they can not all be held in memory
+	const batchSize = 1000
+	h.deduplicated = true
+	defer func() {
+		if err != nil {
+			h.deduplicated = false
+		}
+	}()
+	for rowid := int64(-1); ; {
+		var tiles []TileData
+		tiles, rowid, err = plainTiles(tx, rowid, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, td := range tiles {
+			if err = h.putTile(tx, td.Tile, td.Data); err != nil {
+				return err
+			}
+		}
+		if len(tiles) < batchSize {
+			break
+		}
+	}
+	if _, err = tx.Exec(`DROP TABLE tiles_plain`); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// plainTiles returns up to n tiles of the tiles_plain table after the rowid,
+// and the rowid of the last one.
+func plainTiles(tx *sql.Tx, after int64, n int) (tiles []TileData, last int64, err error) {
+	rows, err := tx.Query(
+		`SELECT rowid, zoom_level, tile_column, tile_row, tile_data FROM tiles_plain WHERE rowid > ? ORDER BY rowid LIMIT ?`,
+		after, n,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	last = after
+	for rows.Next() {
+		var (
+			z, x, y uint
+			data    []byte
+		)
+		if err := rows.Scan(&last, &z, &x, &y, &data); err != nil {
+			return nil, 0, err
+		}
+		tiles = append(tiles, TileData{Tile: slippy.FromTMS(slippy.Zoom(z), x, y), Data: data})
+	}
+	return tiles, last, rows.Err()
+}
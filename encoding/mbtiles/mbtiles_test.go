@@ -0,0 +1,291 @@
+//go:build cgo
+// +build cgo
+
+package mbtiles
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+)
+
+func zoom(z slippy.Zoom) *slippy.Zoom { return &z }
+
+func TestMetadata(t *testing.T) {
+	h, err := New(filepath.Join(t.TempDir(), "metadata.mbtiles"))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	defer h.Close()
+
+	md := &Metadata{
+		Name:        "test",
+		Format:      FormatPBF,
+		Bounds:      &geom.Extent{-180, -85.05112878, 180, 85.05112878},
+		Center:      &[3]float64{-122.1906, 37.7599, 10},
+		MinZoom:     zoom(0),
+		MaxZoom:     zoom(14),
+		Attribution: "© contributors",
+		Type:        TypeOverlay,
+		Version:     "1.0",
+		JSON: &MetadataJSON{
+			VectorLayers: []VectorLayer{
+				{ID: "roads", Fields: map[string]string{"name": "String", "lanes": "Number"}, MinZoom: zoom(5), MaxZoom: zoom(14)},
+				{ID: "water", Fields: map[string]string{}},
+			},
+		},
+		Other: map[string]string{"generator": "geom"},
+	}
+	if err := h.SetMetadata(md); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	got, err := h.Metadata()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !reflect.DeepEqual(got, md) {
+		t.Errorf("metadata, expected %+v got %+v", md, got)
+	}
+
+	// setting the metadata replaces the rows
+	if err := h.SetMetadata(&Metadata{Name: "other"}); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	got, err = h.Metadata()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !reflect.DeepEqual(got, &Metadata{Name: "other"}) {
+		t.Errorf("metadata, expected only the name got %+v", got)
+	}
+
+	// rows that can not be parsed are kept in Other
+	for _, row := range [][2]string{{"minzoom", "low"}, {"maxzoom", "4"}, {"json", "{"}} {
+		if _, err := h.Exec(`INSERT INTO metadata (name, value) VALUES (?, ?)`, row[0], row[1]); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+	}
+	got, err = h.Metadata()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := &Metadata{
+		Name:    "other",
+		MaxZoom: zoom(4),
+		Other:   map[string]string{"minzoom": "low", "json": "{"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("metadata, expected %+v got %+v", expected, got)
+	}
+}
+
+func TestMetadataJSONOther(t *testing.T) {
+	const value = `{"vector_layers":[{"id":"roads","fields":{}}],"tilestats":{"layerCount":1},"tilejson":"3.0.0","bounds":[0,0,1,1]}`
+	var mj MetadataJSON
+	if err := json.Unmarshal([]byte(value), &mj); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := map[string]json.RawMessage{
+		"tilejson": json.RawMessage(`"3.0.0"`),
+		"bounds":   json.RawMessage(`[0,0,1,1]`),
+	}
+	if !reflect.DeepEqual(mj.Other, expected) {
+		t.Errorf("other, expected %s got %s", expected, mj.Other)
+	}
+	if len(mj.VectorLayers) != 1 || mj.VectorLayers[0].ID != "roads" {
+		t.Errorf("vector layers, expected [roads] got %v", mj.VectorLayers)
+	}
+
+	// the unknown keys are written back
+	bs, err := json.Marshal(mj)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	var got, want map[string]interface{}
+	if err := json.Unmarshal(bs, &got); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err := json.Unmarshal([]byte(value), &want); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json, expected %s got %s", value, bs)
+	}
+}
+
+func TestTiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tiles.mbtiles")
+	h, err := New(filename)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !h.Deduplicated() {
+		t.Errorf("deduplicated, expected true")
+	}
+
+	ocean := []byte("ocean")
+	tiles := []TileData{
+		{Tile: slippy.Tile{Z: 0}, Data: []byte("world")},
+		{Tile: slippy.Tile{Z: 2, X: 0, Y: 0}, Data: ocean},
+		{Tile: slippy.Tile{Z: 2, X: 1, Y: 3}, Data: []byte("land")},
+		{Tile: slippy.Tile{Z: 2, X: 3, Y: 1}, Data: ocean},
+	}
+	if err := h.PutTiles(tiles...); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err := h.PutTile(slippy.Tile{Z: 2, X: 3, Y: 3}, ocean); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	h.Close()
+
+	if _, err := New(filename); err != os.ErrExist {
+		t.Errorf("error, expected %v got %v", os.ErrExist, err)
+	}
+	h, err = Open(filename)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	defer h.Close()
+
+	data, err := h.Tile(slippy.Tile{Z: 2, X: 1, Y: 3})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if string(data) != "land" {
+		t.Errorf("data, expected land got %s", data)
+	}
+	// the rows are stored flipped
+	var row int
+	if err := h.QueryRow(`SELECT tile_row FROM tiles WHERE zoom_level = 2 AND tile_column = 1`).Scan(&row); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if row != 0 {
+		t.Errorf("tile_row, expected 0 got %v", row)
+	}
+	if _, err := h.Tile(slippy.Tile{Z: 2, X: 2, Y: 2}); err != ErrTileNotFound {
+		t.Errorf("error, expected %v got %v", ErrTileNotFound, err)
+	}
+
+	// identical tiles are stored once
+	var images int
+	if err := h.QueryRow(`SELECT count(*) FROM images`).Scan(&images); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if images != 3 {
+		t.Errorf("images, expected 3 got %v", images)
+	}
+
+	var got []slippy.Tile
+	err = h.EachTile(func(tile slippy.Tile, data []byte) bool {
+		got = append(got, tile)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := []slippy.Tile{{Z: 0}, {Z: 2, X: 0, Y: 0}, {Z: 2, X: 1, Y: 3}, {Z: 2, X: 3, Y: 3}, {Z: 2, X: 3, Y: 1}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("tiles, expected %v got %v", expected, got)
+	}
+
+	// the data is removed with its last tile
+	for _, tile := range []slippy.Tile{{Z: 2, X: 0, Y: 0}, {Z: 2, X: 1, Y: 3}} {
+		if err := h.DeleteTile(tile); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+	}
+	if err := h.QueryRow(`SELECT count(*) FROM images`).Scan(&images); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if images != 2 {
+		t.Errorf("images, expected 2 got %v", images)
+	}
+	if data, _ := h.Tile(slippy.Tile{Z: 2, X: 3, Y: 1}); !bytes.Equal(data, ocean) {
+		t.Errorf("data, expected ocean got %s", data)
+	}
+}
+
+func TestPlainTiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "plain.mbtiles")
+	db, err := sql.Open(driverName, filename)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE metadata (name TEXT, value TEXT);
+		CREATE TABLE tiles (zoom_level INTEGER, tile_column INTEGER, tile_row INTEGER, tile_data BLOB);
+		CREATE UNIQUE INDEX tile_index ON tiles (zoom_level, tile_column, tile_row);
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	h, err := Open(filename)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	defer h.Close()
+	if h.Deduplicated() {
+		t.Errorf("deduplicated, expected false")
+	}
+	for x := uint(0); x < 4; x++ {
+		if err := h.PutTile(slippy.Tile{Z: 2, X: x, Y: 1}, []byte("same")); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+	}
+	if err := h.PutTile(slippy.Tile{Z: 2, X: 0, Y: 1}, []byte("replaced")); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	if err := h.Deduplicate(); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !h.Deduplicated() {
+		t.Errorf("deduplicated, expected true")
+	}
+	var images int
+	if err := h.QueryRow(`SELECT count(*) FROM images`).Scan(&images); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if images != 2 {
+		t.Errorf("images, expected 2 got %v", images)
+	}
+	for x, expected := range []string{"replaced", "same", "same", "same"} {
+		data, err := h.Tile(slippy.Tile{Z: 2, X: uint(x), Y: 1})
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if string(data) != expected {
+			t.Errorf("data, expected %v got %s", expected, data)
+		}
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Open(filepath.Join(dir, "missing.mbtiles")); err != os.ErrNotExist {
+		t.Errorf("error, expected %v got %v", os.ErrNotExist, err)
+	}
+
+	filename := filepath.Join(dir, "other.sqlite")
+	db, err := sql.Open(driverName, filename)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE other (id INTEGER)`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if _, err := Open(filename); err != ErrNotMBTiles {
+		t.Errorf("error, expected %v got %v", ErrNotMBTiles, err)
+	}
+}
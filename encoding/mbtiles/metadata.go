@@ -0,0 +1,256 @@
+package mbtiles
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+)
+
+// Formats of the tile data
+const (
+	FormatPBF  = "pbf"
+	FormatPNG  = "png"
+	FormatJPG  = "jpg"
+	FormatWEBP = "webp"
+)
+
+// Types of tilesets
+const (
+	TypeOverlay   = "overlay"
+	TypeBaseLayer = "baselayer"
+)
+
+// The names of the metadata rows defined by the MBTiles 1.3 spec.
+const (
+	keyName        = "name"
+	keyFormat      = "format"
+	keyBounds      = "bounds"
+	keyCenter      = "center"
+	keyMinZoom     = "minzoom"
+	keyMaxZoom     = "maxzoom"
+	keyAttribution = "attribution"
+	keyDescription = "description"
+	keyType        = "type"
+	keyVersion     = "version"
+	keyJSON        = "json"
+)
+
+// Metadata is the content of the metadata table. The fields that are not
+// set are left out of the table.
+type Metadata struct {
+	Name   string
+	Format string
+	// Bounds is the extent of the tiles in longitude and latitude.
+	Bounds *geom.Extent
+	// Center is the longitude, latitude and zoom of the default view.
+	Center      *[3]float64
+	MinZoom     *slippy.Zoom
+	MaxZoom     *slippy.Zoom
+	Attribution string
+	Description string
+	Type        string
+	Version     string
+	// JSON describes the layers of vector tiles; it is required when the
+	// Format is FormatPBF.
+	JSON *MetadataJSON
+	// Other holds the rows that are not defined by the spec, and the rows
+	// defined by the spec whose value could not be parsed.
+	Other map[string]string
+}
+
+// MetadataJSON is the value of the json metadata row.
+type MetadataJSON struct {
+	VectorLayers []VectorLayer   `json:"vector_layers"`
+	TileStats    json.RawMessage `json:"tilestats,omitempty"`
+	// Other holds the keys that are not defined by the spec, so they are
+	// kept when the metadata is written back.
+	Other map[string]json.RawMessage `json:"-"`
+}
+
+// metadataJSON is MetadataJSON without its methods.
+type metadataJSON MetadataJSON
+
+// MarshalJSON is the marshalling function for JSON. The Other keys are added
+// to the object, unless they are one of the keys defined by the spec.
+func (mj MetadataJSON) MarshalJSON() ([]byte, error) {
+	bs, err := json.Marshal(metadataJSON(mj))
+	if err != nil || len(mj.Other) == 0 {
+		return bs, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(bs, &obj); err != nil {
+		return nil, err
+	}
+	for k, v := range mj.Other {
+		if _, ok := obj[k]; !ok && k != "vector_layers" && k != "tilestats" {
+			obj[k] = v
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON is the unmarshalling function for JSON.
+func (mj *MetadataJSON) UnmarshalJSON(bs []byte) error {
+	var md metadataJSON
+	if err := json.Unmarshal(bs, &md); err != nil {
+		return err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(bs, &obj); err != nil {
+		return err
+	}
+	delete(obj, "vector_layers")
+	delete(obj, "tilestats")
+	md.Other = nil
+	if len(obj) > 0 {
+		md.Other = obj
+	}
+	*mj = MetadataJSON(md)
+	return nil
+}
+
+// VectorLayer describes a layer of the vector tiles.
+type VectorLayer struct {
+	ID string `json:"id"`
+	// Fields maps the attribute names to their type; Number, Boolean or String.
+	Fields      map[string]string `json:"fields"`
+	Description string            `json:"description,omitempty"`
+	MinZoom     *slippy.Zoom      `json:"minzoom,omitempty"`
+	MaxZoom     *slippy.Zoom      `json:"maxzoom,omitempty"`
+}
+
+// formatFloats returns the values separated by commas.
+func formatFloats(vs ...float64) string {
+	strs := make([]string, len(vs))
+	for i, v := range vs {
+		strs[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(strs, ",")
+}
+
+// parseFloats parses n values separated by commas.
+func parseFloats(s string, n int) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, ErrInvalidMetadata
+	}
+	vs := make([]float64, n)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, ErrInvalidMetadata
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func parseZoom(s string) (*slippy.Zoom, error) {
+	z, err := strconv.ParseUint(strings.TrimSpace(s), 10, 8)
+	if err != nil {
+		return nil, ErrInvalidMetadata
+	}
+	zoom := slippy.Zoom(z)
+	return &zoom, nil
+}
+
+// rows returns the metadata rows.
+func (md *Metadata) rows() (map[string]string, error) {
+	rows := make(map[string]string, len(md.Other)+11)
+	for k, v := range md.Other {
+		rows[k] = v
+	}
+	for k, v := range map[string]string{
+		keyName:        md.Name,
+		keyFormat:      md.Format,
+		keyAttribution: md.Attribution,
+		keyDescription: md.Description,
+		keyType:        md.Type,
+		keyVersion:     md.Version,
+	} {
+		if v != "" {
+			rows[k] = v
+		}
+	}
+	if md.Bounds != nil {
+		rows[keyBounds] = formatFloats(md.Bounds[:]...)
+	}
+	if md.Center != nil {
+		rows[keyCenter] = formatFloats(md.Center[:]...)
+	}
+	if md.MinZoom != nil {
+		rows[keyMinZoom] = strconv.FormatUint(uint64(*md.MinZoom), 10)
+	}
+	if md.MaxZoom != nil {
+		rows[keyMaxZoom] = strconv.FormatUint(uint64(*md.MaxZoom), 10)
+	}
+	if md.JSON != nil {
+		bs, err := json.Marshal(md.JSON)
+		if err != nil {
+			return nil, err
+		}
+		rows[keyJSON] = string(bs)
+	}
+	return rows, nil
+}
+
+// setRow sets the field of the metadata for the row. Rows with a value that
+// can not be parsed are added to Other, so one bad row does not hide the rest
+// of the metadata.
+func (md *Metadata) setRow(name, value string) {
+	var err error
+	switch name {
+	case keyName:
+		md.Name = value
+	case keyFormat:
+		md.Format = value
+	case keyAttribution:
+		md.Attribution = value
+	case keyDescription:
+		md.Description = value
+	case keyType:
+		md.Type = value
+	case keyVersion:
+		md.Version = value
+	case keyBounds:
+		var vs []float64
+		if vs, err = parseFloats(value, 4); err == nil {
+			md.Bounds = &geom.Extent{vs[0], vs[1], vs[2], vs[3]}
+		}
+	case keyCenter:
+		var vs []float64
+		if vs, err = parseFloats(value, 3); err == nil {
+			md.Center = &[3]float64{vs[0], vs[1], vs[2]}
+		}
+	case keyMinZoom:
+		var z *slippy.Zoom
+		if z, err = parseZoom(value); err == nil {
+			md.MinZoom = z
+		}
+	case keyMaxZoom:
+		var z *slippy.Zoom
+		if z, err = parseZoom(value); err == nil {
+			md.MaxZoom = z
+		}
+	case keyJSON:
+		mj := new(MetadataJSON)
+		if err = json.Unmarshal([]byte(value), mj); err == nil {
+			md.JSON = mj
+		}
+	default:
+		md.setOther(name, value)
+	}
+	if err != nil {
+		md.setOther(name, value)
+	}
+}
+
+func (md *Metadata) setOther(name, value string) {
+	if md.Other == nil {
+		md.Other = make(map[string]string)
+	}
+	md.Other[name] = value
+}
@@ -0,0 +1,191 @@
+package pmtiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+)
+
+// Entry is an entry of a directory. An entry with a RunLength of 0 points at
+// a leaf directory; otherwise the data is the data of the RunLength tiles
+// starting at TileID. The offset of tile data is from the start of the tile
+// data section, and the offset of a leaf directory from the start of the leaf
+// directories section.
+type Entry struct {
+	TileID    uint64
+	Offset    uint64
+	Length    uint32
+	RunLength uint32
+}
+
+// IsLeaf returns whether the entry points at a leaf directory.
+func (e Entry) IsLeaf() bool { return e.RunLength == 0 }
+
+// EncodeDirectory encodes the entries, which must be sorted by tile id, and
+// compresses them with the given compression.
+//
+// The entries are stored as columns of varints; the number of entries, the
+// tile ids as deltas from the previous one, the run lengths, the lengths, and
+// the offsets. An offset is stored as 0 when the data follows the data of
+// the previous entry, otherwise as the offset plus 1.
+//
+// Possible errors:
+//
+//	ErrUnsupportedCompression
+func EncodeDirectory(entries []Entry, c Compression) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		tmp [binary.MaxVarintLen64]byte
+	)
+	put := func(v uint64) {
+		n := binary.PutUvarint(tmp[:], v)
+		buf.Write(tmp[:n])
+	}
+
+	put(uint64(len(entries)))
+	var last uint64
+	for _, e := range entries {
+		put(e.TileID - last)
+		last = e.TileID
+	}
+	for _, e := range entries {
+		put(uint64(e.RunLength))
+	}
+	for _, e := range entries {
+		put(uint64(e.Length))
+	}
+	for i, e := range entries {
+		if i > 0 && e.Offset == entries[i-1].Offset+uint64(entries[i-1].Length) {
+			put(0)
+			continue
+		}
+		put(e.Offset + 1)
+	}
+	return Compress(c, buf.Bytes())
+}
+
+// DecodeDirectory decompresses and decodes a directory; see EncodeDirectory.
+//
+// Possible errors:
+//
+//	ErrInvalidDirectory
+//	ErrUnsupportedCompression
+func DecodeDirectory(data []byte, c Compression) ([]Entry, error) {
+	data, err := Decompress(c, data)
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(data)
+	get := func() (uint64, error) {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			return 0, ErrInvalidDirectory
+		}
+		return v, nil
+	}
+
+	n, err := get()
+	if err != nil {
+		return nil, err
+	}
+	// every entry takes at least a byte in each column.
+	if n > uint64(len(data)) {
+		return nil, ErrInvalidDirectory
+	}
+	entries := make([]Entry, n)
+	var last uint64
+	for i := range entries {
+		v, err := get()
+		if err != nil {
+			return nil, err
+		}
+		last += v
+		entries[i].TileID = last
+	}
+	for i := range entries {
+		v, err := get()
+		if err != nil {
+			return nil, err
+		}
+		entries[i].RunLength = uint32(v)
+	}
+	for i := range entries {
+		v, err := get()
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Length = uint32(v)
+	}
+	for i := range entries {
+		v, err := get()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case v > 0:
+			entries[i].Offset = v - 1
+		case i > 0:
+			entries[i].Offset = entries[i-1].Offset + uint64(entries[i-1].Length)
+		default:
+			return nil, ErrInvalidDirectory
+		}
+	}
+	return entries, nil
+}
+
+// findEntry returns the entry for the tile id; the tile entry whose run
+// contains the id, or the leaf entry whose directory would have it.
+func findEntry(entries []Entry, id uint64) (Entry, bool) {
+	// the first entry past the id
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].TileID > id })
+	if i == 0 {
+		return Entry{}, false
+	}
+	e := entries[i-1]
+	if e.IsLeaf() || id < e.TileID+uint64(e.RunLength) {
+		return e, true
+	}
+	return Entry{}, false
+}
+
+// buildDirectories encodes the entries as a root directory, which has to fit
+// in RootSize along with the header, and the leaf directories it points at.
+func buildDirectories(entries []Entry, c Compression) (root, leaves []byte, err error) {
+	root, err = EncodeDirectory(entries, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(root)+HeaderSize <= RootSize {
+		return root, nil, nil
+	}
+
+	for leafSize := 4096; ; leafSize += leafSize / 5 {
+		var (
+			rootEntries []Entry
+			buf         bytes.Buffer
+		)
+		for start := 0; start < len(entries); start += leafSize {
+			end := start + leafSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			leaf, err := EncodeDirectory(entries[start:end], c)
+			if err != nil {
+				return nil, nil, err
+			}
+			rootEntries = append(rootEntries, Entry{
+				TileID: entries[start].TileID,
+				Offset: uint64(buf.Len()),
+				Length: uint32(len(leaf)),
+			})
+			buf.Write(leaf)
+		}
+		root, err = EncodeDirectory(rootEntries, c)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(root)+HeaderSize <= RootSize {
+			return root, buf.Bytes(), nil
+		}
+	}
+}
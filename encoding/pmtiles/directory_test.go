@@ -0,0 +1,84 @@
+package pmtiles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDirectory(t *testing.T) {
+	type tcase struct {
+		entries     []Entry
+		compression Compression
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			data, err := EncodeDirectory(tc.entries, tc.compression)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			got, err := DecodeDirectory(data, tc.compression)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got) == 0 && len(tc.entries) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.entries) {
+				t.Errorf("entries, expected %v got %v", tc.entries, got)
+			}
+		}
+	}
+	entries := []Entry{
+		{TileID: 0, Offset: 0, Length: 10, RunLength: 1},
+		// contiguous with the previous entry
+		{TileID: 1, Offset: 10, Length: 20, RunLength: 1},
+		// a run sharing earlier data
+		{TileID: 5, Offset: 0, Length: 10, RunLength: 300},
+		// a leaf
+		{TileID: 1000, Offset: 0, Length: 4000, RunLength: 0},
+	}
+	tests := map[string]tcase{
+		"empty":          {compression: CompressionNone},
+		"uncompressed":   {entries: entries, compression: CompressionNone},
+		"gzip":           {entries: entries, compression: CompressionGzip},
+		"unknown is raw": {entries: entries, compression: CompressionUnknown},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDirectoryErrors(t *testing.T) {
+	if _, err := EncodeDirectory(nil, CompressionZstd); err != ErrUnsupportedCompression {
+		t.Errorf("error, expected %v got %v", ErrUnsupportedCompression, err)
+	}
+	for name, data := range map[string][]byte{
+		"empty":            {},
+		"truncated":        {2, 0, 1, 1, 1},
+		"too many entries": {200, 1},
+		// the first offset can not be relative to the previous entry
+		"no first offset": {1, 0, 1, 1, 0},
+	} {
+		if _, err := DecodeDirectory(data, CompressionNone); err != ErrInvalidDirectory {
+			t.Errorf("%v: error, expected %v got %v", name, ErrInvalidDirectory, err)
+		}
+	}
+}
+
+func TestFindEntry(t *testing.T) {
+	entries := []Entry{
+		{TileID: 5, RunLength: 1},
+		{TileID: 10, RunLength: 5},
+		{TileID: 100, RunLength: 0},
+	}
+	for id, expected := range map[uint64]int{0: -1, 5: 0, 6: -1, 10: 1, 14: 1, 15: -1, 100: 2, 5000: 2} {
+		e, ok := findEntry(entries, id)
+		if ok != (expected >= 0) {
+			t.Errorf("%v: found, expected %v got %v", id, expected >= 0, ok)
+			continue
+		}
+		if ok && e != entries[expected] {
+			t.Errorf("%v: entry, expected %v got %v", id, entries[expected], e)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Package pmtiles reads and writes PMTiles version 3 archives; a single file
+of map tiles meant to be read with range requests, so it can be served from
+static hosting.
+
+An archive is a fixed size header, a root directory, the JSON metadata,
+optional leaf directories, and the tile data. The directories map tile ids,
+the position of a tile along a Hilbert curve at its zoom, to the data of the
+tile; runs of tiles with the same data, such as ocean tiles, are stored as a
+single entry.
+
+See https://github.com/protomaps/PMTiles/blob/main/spec/v3/spec.md
+*/
+package pmtiles
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+)
+
+const (
+	// HeaderSize is the size, in bytes, of the header.
+	HeaderSize = 127
+	// RootSize is the largest size of the header and the root directory;
+	// readers fetch this many bytes first.
+	RootSize = 16384
+	// Version is the version of the spec that is supported.
+	Version = 3
+
+	magic = "PMTiles"
+	// maxZoom is the largest zoom a tile id can be computed for.
+	maxZoom = 31
+)
+
+var (
+	// ErrInvalidHeader is returned when the archive does not start with a PMTiles version 3 header.
+	ErrInvalidHeader = errors.New("pmtiles: invalid header")
+	// ErrInvalidDirectory is returned when a directory can not be decoded.
+	ErrInvalidDirectory = errors.New("pmtiles: invalid directory")
+	// ErrUnsupportedCompression is returned for a compression other than none and gzip.
+	ErrUnsupportedCompression = errors.New("pmtiles: unsupported compression")
+	// ErrTileNotFound is returned when the archive does not have the tile.
+	ErrTileNotFound = errors.New("pmtiles: tile not found")
+	// ErrInvalidTile is returned for tiles beyond the zoom supported by tile ids or outside of their zoom's grid.
+	ErrInvalidTile = errors.New("pmtiles: invalid tile")
+	// ErrClosed is returned when writing to a closed writer.
+	ErrClosed = errors.New("pmtiles: writer is closed")
+)
+
+// Compression is the compression of the directories, metadata or tiles.
+type Compression uint8
+
+// The compressions.
+const (
+	CompressionUnknown Compression = iota
+	CompressionNone
+	CompressionGzip
+	CompressionBrotli
+	CompressionZstd
+)
+
+// TileType is the format of the tiles.
+type TileType uint8
+
+// The tile types.
+const (
+	TileTypeUnknown TileType = iota
+	TileTypeMVT
+	TileTypePNG
+	TileTypeJPEG
+	TileTypeWebP
+	TileTypeAVIF
+)
+
+// Compress returns the data compressed with the compression; only
+// CompressionNone and CompressionGzip are supported.
+func Compress(c Compression, data []byte) ([]byte, error) {
+	switch c {
+	case CompressionNone, CompressionUnknown:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, ErrUnsupportedCompression
+	}
+}
+
+// Decompress returns the data decompressed with the compression; only
+// CompressionNone and CompressionGzip are supported.
+func Decompress(c Compression, data []byte) ([]byte, error) {
+	switch c {
+	case CompressionNone, CompressionUnknown:
+		return data, nil
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	default:
+		return nil, ErrUnsupportedCompression
+	}
+}
+
+// Header is the header of an archive. The offsets are from the start of the
+// archive.
+type Header struct {
+	RootOffset        uint64
+	RootLength        uint64
+	MetadataOffset    uint64
+	MetadataLength    uint64
+	LeafDirsOffset    uint64
+	LeafDirsLength    uint64
+	TileDataOffset    uint64
+	TileDataLength    uint64
+	NumAddressedTiles uint64
+	NumTileEntries    uint64
+	NumTileContents   uint64
+	// Clustered is true when the tile data is in the order of the tile ids.
+	Clustered           bool
+	InternalCompression Compression
+	TileCompression     Compression
+	TileType            TileType
+	MinZoom             slippy.Zoom
+	MaxZoom             slippy.Zoom
+	// Bounds is the extent of the tiles, in longitude and latitude.
+	Bounds     geom.Extent
+	CenterZoom slippy.Zoom
+	// Center is the longitude and latitude of the default view.
+	Center [2]float64
+}
+
+// e7 converts degrees to the fixed point integers of the header.
+func e7(v float64) uint32 {
+	if v < 0 {
+		return uint32(int32(v*1e7 - 0.5))
+	}
+	return uint32(int32(v*1e7 + 0.5))
+}
+
+func fromE7(v uint32) float64 { return float64(int32(v)) / 1e7 }
+
+// MarshalBinary encodes the header.
+func (h *Header) MarshalBinary() ([]byte, error) {
+	b := make([]byte, HeaderSize)
+	copy(b, magic)
+	b[7] = Version
+	le := binary.LittleEndian
+	for i, v := range []uint64{
+		h.RootOffset, h.RootLength,
+		h.MetadataOffset, h.MetadataLength,
+		h.LeafDirsOffset, h.LeafDirsLength,
+		h.TileDataOffset, h.TileDataLength,
+		h.NumAddressedTiles, h.NumTileEntries, h.NumTileContents,
+	} {
+		le.PutUint64(b[8+8*i:], v)
+	}
+	if h.Clustered {
+		b[96] = 1
+	}
+	b[97] = byte(h.InternalCompression)
+	b[98] = byte(h.TileCompression)
+	b[99] = byte(h.TileType)
+	b[100] = byte(h.MinZoom)
+	b[101] = byte(h.MaxZoom)
+	le.PutUint32(b[102:], e7(h.Bounds.MinX()))
+	le.PutUint32(b[106:], e7(h.Bounds.MinY()))
+	le.PutUint32(b[110:], e7(h.Bounds.MaxX()))
+	le.PutUint32(b[114:], e7(h.Bounds.MaxY()))
+	b[118] = byte(h.CenterZoom)
+	le.PutUint32(b[119:], e7(h.Center[0]))
+	le.PutUint32(b[123:], e7(h.Center[1]))
+	return b, nil
+}
+
+// UnmarshalBinary decodes the header.
+//
+// Possible errors:
+//
+//	ErrInvalidHeader
+func (h *Header) UnmarshalBinary(b []byte) error {
+	if len(b) < HeaderSize || string(b[:7]) != magic || b[7] != Version {
+		return ErrInvalidHeader
+	}
+	le := binary.LittleEndian
+	for i, v := range []*uint64{
+		&h.RootOffset, &h.RootLength,
+		&h.MetadataOffset, &h.MetadataLength,
+		&h.LeafDirsOffset, &h.LeafDirsLength,
+		&h.TileDataOffset, &h.TileDataLength,
+		&h.NumAddressedTiles, &h.NumTileEntries, &h.NumTileContents,
+	} {
+		*v = le.Uint64(b[8+8*i:])
+	}
+	h.Clustered = b[96] == 1
+	h.InternalCompression = Compression(b[97])
+	h.TileCompression = Compression(b[98])
+	h.TileType = TileType(b[99])
+	h.MinZoom = slippy.Zoom(b[100])
+	h.MaxZoom = slippy.Zoom(b[101])
+	h.Bounds = geom.Extent{
+		fromE7(le.Uint32(b[102:])), fromE7(le.Uint32(b[106:])),
+		fromE7(le.Uint32(b[110:])), fromE7(le.Uint32(b[114:])),
+	}
+	h.CenterZoom = slippy.Zoom(b[118])
+	h.Center = [2]float64{fromE7(le.Uint32(b[119:])), fromE7(le.Uint32(b[123:]))}
+	return nil
+}
+
+// rotate rotates the quadrant of the Hilbert curve.
+func rotate(n, x, y, rx, ry uint64) (uint64, uint64) {
+	if ry == 0 {
+		if rx != 0 {
+			x = n - 1 - x
+			y = n - 1 - y
+		}
+		return y, x
+	}
+	return x, y
+}
+
+// TileID returns the id of the tile; the number of tiles at the lower zooms
+// plus the position of the tile along a Hilbert curve at its zoom.
+//
+// Possible errors:
+//
+//	ErrInvalidTile
+func TileID(tile slippy.Tile) (uint64, error) {
+	if tile.Z > maxZoom {
+		return 0, ErrInvalidTile
+	}
+	n := uint64(1) << tile.Z
+	x, y := uint64(tile.X), uint64(tile.Y)
+	if x >= n || y >= n {
+		return 0, ErrInvalidTile
+	}
+	id := (n*n - 1) / 3
+	for s := n / 2; s > 0; s /= 2 {
+		var rx, ry uint64
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		id += s * s * ((3 * rx) ^ ry)
+		x, y = rotate(s, x, y, rx, ry)
+	}
+	return id, nil
+}
+
+// TileFromID returns the tile for the tile id; see TileID.
+//
+// Possible errors:
+//
+//	ErrInvalidTile
+func TileFromID(id uint64) (slippy.Tile, error) {
+	var acc uint64
+	for z := slippy.Zoom(0); z <= maxZoom; z++ {
+		n := uint64(1) << z
+		if id >= acc+n*n {
+			acc += n * n
+			continue
+		}
+		d := id - acc
+		var x, y uint64
+		for s := uint64(1); s < n; s *= 2 {
+			rx := 1 & (d / 2)
+			ry := 1 & (d ^ rx)
+			x, y = rotate(s, x, y, rx, ry)
+			x += s * rx
+			y += s * ry
+			d /= 4
+		}
+		return slippy.Tile{Z: z, X: uint(x), Y: uint(y)}, nil
+	}
+	return slippy.Tile{}, ErrInvalidTile
+}
@@ -0,0 +1,126 @@
+package pmtiles
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+)
+
+func TestTileID(t *testing.T) {
+	type tcase struct {
+		tile slippy.Tile
+		id   uint64
+		err  error
+	}
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			id, err := TileID(tc.tile)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if id != tc.id {
+				t.Errorf("id, expected %v got %v", tc.id, id)
+			}
+			tile, err := TileFromID(id)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if tile != tc.tile {
+				t.Errorf("tile, expected %v got %v", tc.tile, tile)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"0/0/0":        {tile: slippy.Tile{Z: 0, X: 0, Y: 0}, id: 0},
+		"1/0/0":        {tile: slippy.Tile{Z: 1, X: 0, Y: 0}, id: 1},
+		"1/0/1":        {tile: slippy.Tile{Z: 1, X: 0, Y: 1}, id: 2},
+		"1/1/1":        {tile: slippy.Tile{Z: 1, X: 1, Y: 1}, id: 3},
+		"1/1/0":        {tile: slippy.Tile{Z: 1, X: 1, Y: 0}, id: 4},
+		"2/0/0":        {tile: slippy.Tile{Z: 2, X: 0, Y: 0}, id: 5},
+		"3/3/0":        {tile: slippy.Tile{Z: 3, X: 3, Y: 0}, id: 26},
+		"3/7/7":        {tile: slippy.Tile{Z: 3, X: 7, Y: 7}, id: 63},
+		"12/3423/1763": {tile: slippy.Tile{Z: 12, X: 3423, Y: 1763}, id: 19078479},
+		"outside grid": {tile: slippy.Tile{Z: 1, X: 2, Y: 0}, err: ErrInvalidTile},
+		"zoom too big": {tile: slippy.Tile{Z: 32}, err: ErrInvalidTile},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestTileIDOrder(t *testing.T) {
+	// the ids of a zoom are consecutive, and neighbors along the curve are
+	// adjacent tiles.
+	var prev slippy.Tile
+	for id := uint64(5); id < 21; id++ {
+		tile, err := TileFromID(id)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if tile.Z != 2 {
+			t.Fatalf("zoom of %v, expected 2 got %v", id, tile.Z)
+		}
+		if id > 5 {
+			dx, dy := int(tile.X)-int(prev.X), int(tile.Y)-int(prev.Y)
+			if dx*dx+dy*dy != 1 {
+				t.Errorf("tile %v, expected to be next to %v", tile, prev)
+			}
+		}
+		prev = tile
+	}
+}
+
+func TestHeader(t *testing.T) {
+	h := Header{
+		RootOffset:          127,
+		RootLength:          25,
+		MetadataOffset:      152,
+		MetadataLength:      247,
+		LeafDirsOffset:      399,
+		LeafDirsLength:      1000,
+		TileDataOffset:      1399,
+		TileDataLength:      4000,
+		NumAddressedTiles:   10,
+		NumTileEntries:      9,
+		NumTileContents:     8,
+		Clustered:           true,
+		InternalCompression: CompressionGzip,
+		TileCompression:     CompressionBrotli,
+		TileType:            TileTypeMVT,
+		MinZoom:             1,
+		MaxZoom:             3,
+		Bounds:              geom.Extent{-122.5, -45.25, 180, 85.0511287},
+		CenterZoom:          2,
+		Center:              [2]float64{-73.9857, 40.7484},
+	}
+	b, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(b) != HeaderSize {
+		t.Fatalf("size, expected %v got %v", HeaderSize, len(b))
+	}
+	if string(b[:7]) != "PMTiles" || b[7] != 3 {
+		t.Errorf("magic, expected PMTiles version 3 got %q", b[:8])
+	}
+	var got Header
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !reflect.DeepEqual(got, h) {
+		t.Errorf("header, expected %+v got %+v", h, got)
+	}
+
+	b[7] = 2
+	if err := got.UnmarshalBinary(b); err != ErrInvalidHeader {
+		t.Errorf("error, expected %v got %v", ErrInvalidHeader, err)
+	}
+	if err := got.UnmarshalBinary(b[:100]); err != ErrInvalidHeader {
+		t.Errorf("error, expected %v got %v", ErrInvalidHeader, err)
+	}
+}
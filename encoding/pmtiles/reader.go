@@ -0,0 +1,184 @@
+package pmtiles
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/go-spatial/geom/slippy"
+)
+
+const (
+	// MaxMetadataSize is the largest metadata, in bytes, the reader will read.
+	MaxMetadataSize = 64 << 20
+	// MaxLeafSize is the largest leaf directory, in bytes, the reader will read.
+	MaxLeafSize = 16 << 20
+)
+
+// maxDepth is the number of directories, including the root, a tile lookup
+// will read; the spec limits archives to a root and one level of leaves, but
+// the reader allows for deeper ones.
+const maxDepth = 4
+
+// Reader reads an archive. The header and root directory are read when the
+// reader is created; everything else is read as needed, so the reader works
+// well over range requests.
+type Reader struct {
+	r      io.ReaderAt
+	header Header
+	root   []Entry
+}
+
+// NewReader returns a reader of the archive.
+//
+// Possible errors:
+//
+//	ErrInvalidHeader
+//	ErrInvalidDirectory
+//	ErrUnsupportedCompression
+func NewReader(r io.ReaderAt) (*Reader, error) {
+	buf := make([]byte, RootSize)
+	n, err := r.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	rd := &Reader{r: r}
+	if err := rd.header.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+	// the root directory is in the first RootSize bytes.
+	h := &rd.header
+	if h.RootOffset > RootSize || h.RootLength > RootSize-HeaderSize || h.RootOffset+h.RootLength > uint64(len(buf)) {
+		return nil, ErrInvalidHeader
+	}
+	root := buf[h.RootOffset : h.RootOffset+h.RootLength]
+	if rd.root, err = DecodeDirectory(root, h.InternalCompression); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+// read returns the length bytes at the offset, from the start of a section,
+// of the archive. The offsets and lengths come from the archive, so a length
+// over max, or a position past the end of an int64, is the given error; and
+// the buffer grows as the bytes are read, rather than being allocated up
+// front.
+func (rd *Reader) read(section, offset, length, max uint64, bad error) ([]byte, error) {
+	pos := section + offset
+	if length > max || pos < section || pos > math.MaxInt64-length {
+		return nil, bad
+	}
+	data, err := io.ReadAll(io.NewSectionReader(rd.r, int64(pos), int64(length)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) != length {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return data, nil
+}
+
+// Header returns the header of the archive.
+func (rd *Reader) Header() Header { return rd.header }
+
+// Metadata unmarshals the JSON metadata of the archive into v.
+//
+// Possible errors:
+//
+//	ErrInvalidHeader
+//	ErrUnsupportedCompression
+func (rd *Reader) Metadata(v interface{}) error {
+	data, err := rd.read(rd.header.MetadataOffset, 0, rd.header.MetadataLength, MaxMetadataSize, ErrInvalidHeader)
+	if err != nil {
+		return err
+	}
+	if data, err = Decompress(rd.header.InternalCompression, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// leaf returns the entries of the leaf directory of the entry.
+func (rd *Reader) leaf(e Entry) ([]Entry, error) {
+	data, err := rd.read(rd.header.LeafDirsOffset, e.Offset, uint64(e.Length), MaxLeafSize, ErrInvalidDirectory)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeDirectory(data, rd.header.InternalCompression)
+}
+
+// Tile returns the data of the tile, as stored; it is compressed with the
+// header's TileCompression.
+//
+// Possible errors:
+//
+//	ErrTileNotFound
+//	ErrInvalidTile
+//	ErrInvalidDirectory
+func (rd *Reader) Tile(tile slippy.Tile) ([]byte, error) {
+	if tile.Z < rd.header.MinZoom || tile.Z > rd.header.MaxZoom {
+		return nil, ErrTileNotFound
+	}
+	id, err := TileID(tile)
+	if err != nil {
+		return nil, err
+	}
+	entries := rd.root
+	for depth := 0; depth < maxDepth; depth++ {
+		e, ok := findEntry(entries, id)
+		if !ok {
+			return nil, ErrTileNotFound
+		}
+		if !e.IsLeaf() {
+			return rd.read(rd.header.TileDataOffset, e.Offset, uint64(e.Length), math.MaxUint32, ErrInvalidDirectory)
+		}
+		if entries, err = rd.leaf(e); err != nil {
+			return nil, err
+		}
+	}
+	return nil, ErrInvalidDirectory
+}
+
+// EachTile calls fn with each tile of the archive, and its data as stored, in
+// the order of the tile ids; until fn returns false. The tiles of a run share
+// the same data slice, so it must not be modified.
+func (rd *Reader) EachTile(fn func(slippy.Tile, []byte) bool) error {
+	_, err := rd.eachTile(rd.root, 0, fn)
+	return err
+}
+
+func (rd *Reader) eachTile(entries []Entry, depth int, fn func(slippy.Tile, []byte) bool) (bool, error) {
+	if depth >= maxDepth {
+		return false, ErrInvalidDirectory
+	}
+	for _, e := range entries {
+		if e.IsLeaf() {
+			leaf, err := rd.leaf(e)
+			if err != nil {
+				return false, err
+			}
+			more, err := rd.eachTile(leaf, depth+1, fn)
+			if !more || err != nil {
+				return false, err
+			}
+			continue
+		}
+		data, err := rd.read(rd.header.TileDataOffset, e.Offset, uint64(e.Length), math.MaxUint32, ErrInvalidDirectory)
+		if err != nil {
+			return false, err
+		}
+		for id := e.TileID; id < e.TileID+uint64(e.RunLength); id++ {
+			tile, err := TileFromID(id)
+			if err != nil {
+				return false, err
+			}
+			if !fn(tile, data) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
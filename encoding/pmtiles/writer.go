@@ -0,0 +1,260 @@
+package pmtiles
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+)
+
+// Options are the options of a Writer.
+type Options struct {
+	// TileType is the format of the tiles.
+	TileType TileType
+	// TileCompression is the compression the tile data is already in; the
+	// writer stores the data as given.
+	TileCompression Compression
+	// InternalCompression is the compression of the directories and the
+	// metadata; if CompressionUnknown, CompressionGzip is used.
+	InternalCompression Compression
+	// Metadata is marshaled to JSON as the metadata of the archive; if nil,
+	// the metadata is an empty object.
+	Metadata interface{}
+	// Bounds is the extent of the tiles, in longitude and latitude; if nil, it
+	// is the extent of the tiles written.
+	Bounds *geom.Extent
+	// Center is the longitude, latitude and zoom of the default view; if nil,
+	// it is the center of the bounds at the min zoom.
+	Center *[3]float64
+	// TempDir is the directory of the temporary file the tile data is
+	// buffered in until Close; if empty, os.TempDir is used.
+	TempDir string
+}
+
+// Writer writes an archive. The directories come before the tile data in an
+// archive, so the tile data is buffered in a temporary file and the archive
+// is written by Close.
+type Writer struct {
+	w    io.Writer
+	opts Options
+	tmp  *os.File
+	size uint64
+	// entries are the tiles written, with the offset into tmp.
+	entries []Entry
+	// contents is the offset, into tmp, of the data with the given hash.
+	contents map[[sha256.Size]byte]uint64
+	closed   bool
+}
+
+// NewWriter returns a writer of an archive to w. The caller has to call
+// Close to write the archive.
+func NewWriter(w io.Writer, opts Options) (*Writer, error) {
+	if opts.InternalCompression == CompressionUnknown {
+		opts.InternalCompression = CompressionGzip
+	}
+	if _, err := Compress(opts.InternalCompression, nil); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(opts.TempDir, "pmtiles-*")
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{
+		w:        w,
+		opts:     opts,
+		tmp:      tmp,
+		contents: make(map[[sha256.Size]byte]uint64),
+	}, nil
+}
+
+// WriteTile adds the tile to the archive. Tiles with the same data are
+// stored once. Writing a tile a second time replaces its data.
+//
+// Possible errors:
+//
+//	ErrClosed
+//	ErrInvalidTile
+func (w *Writer) WriteTile(tile slippy.Tile, data []byte) error {
+	if w.closed {
+		return ErrClosed
+	}
+	id, err := TileID(tile)
+	if err != nil {
+		return err
+	}
+	if uint64(len(data)) > math.MaxUint32 {
+		return ErrInvalidTile
+	}
+	hash := sha256.Sum256(data)
+	offset, ok := w.contents[hash]
+	if !ok {
+		if _, err := w.tmp.Write(data); err != nil {
+			return err
+		}
+		offset = w.size
+		w.contents[hash] = offset
+		w.size += uint64(len(data))
+	}
+	w.entries = append(w.entries, Entry{TileID: id, Offset: offset, Length: uint32(len(data)), RunLength: 1})
+	return nil
+}
+
+// Close writes the archive and removes the temporary file. It does not close
+// the underlying writer.
+func (w *Writer) Close() (err error) {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+	defer func() {
+		name := w.tmp.Name()
+		if cerr := w.tmp.Close(); err == nil {
+			err = cerr
+		}
+		if rerr := os.Remove(name); err == nil {
+			err = rerr
+		}
+	}()
+
+	// sort by tile id, keeping the last write of each tile.
+	sort.SliceStable(w.entries, func(i, j int) bool { return w.entries[i].TileID < w.entries[j].TileID })
+	entries := w.entries[:0]
+	for i, e := range w.entries {
+		if i+1 < len(w.entries) && w.entries[i+1].TileID == e.TileID {
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	// the tile data is written in the order of the tile ids, so the archive
+	// is clustered; offsets maps the offsets and lengths in tmp to the offsets
+	// in the archive.
+	var (
+		offsets  = make(map[[2]uint64]uint64, len(w.contents))
+		order    []Entry
+		dataSize uint64
+		runs     []Entry
+	)
+	for _, e := range entries {
+		key := [2]uint64{e.Offset, uint64(e.Length)}
+		offset, ok := offsets[key]
+		if !ok {
+			offset = dataSize
+			offsets[key] = offset
+			order = append(order, e)
+			dataSize += uint64(e.Length)
+		}
+		if n := len(runs); n > 0 && runs[n-1].Offset == offset &&
+			runs[n-1].TileID+uint64(runs[n-1].RunLength) == e.TileID &&
+			runs[n-1].RunLength < math.MaxUint32 {
+			runs[n-1].RunLength++
+			continue
+		}
+		runs = append(runs, Entry{TileID: e.TileID, Offset: offset, Length: e.Length, RunLength: 1})
+	}
+
+	header, err := w.header(entries)
+	if err != nil {
+		return err
+	}
+	header.NumAddressedTiles = uint64(len(entries))
+	header.NumTileEntries = uint64(len(runs))
+	header.NumTileContents = uint64(len(order))
+
+	root, leaves, err := buildDirectories(runs, w.opts.InternalCompression)
+	if err != nil {
+		return err
+	}
+	var md interface{} = struct{}{}
+	if w.opts.Metadata != nil {
+		md = w.opts.Metadata
+	}
+	metadata, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+	if metadata, err = Compress(w.opts.InternalCompression, metadata); err != nil {
+		return err
+	}
+
+	header.RootOffset, header.RootLength = HeaderSize, uint64(len(root))
+	header.MetadataOffset, header.MetadataLength = header.RootOffset+header.RootLength, uint64(len(metadata))
+	header.LeafDirsOffset, header.LeafDirsLength = header.MetadataOffset+header.MetadataLength, uint64(len(leaves))
+	header.TileDataOffset, header.TileDataLength = header.LeafDirsOffset+header.LeafDirsLength, dataSize
+
+	hdr, err := header.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	for _, b := range [][]byte{hdr, root, metadata, leaves} {
+		if _, err := w.w.Write(b); err != nil {
+			return err
+		}
+	}
+	for _, e := range order {
+		if _, err := io.Copy(w.w, io.NewSectionReader(w.tmp, int64(e.Offset), int64(e.Length))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// header returns the header, without the offsets and counts, for the
+// entries.
+func (w *Writer) header(entries []Entry) (*Header, error) {
+	h := &Header{
+		Clustered:           true,
+		InternalCompression: w.opts.InternalCompression,
+		TileCompression:     w.opts.TileCompression,
+		TileType:            w.opts.TileType,
+	}
+	var bounds *geom.Extent
+	for i, e := range entries {
+		tile, err := TileFromID(e.TileID)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 || tile.Z < h.MinZoom {
+			h.MinZoom = tile.Z
+		}
+		if tile.Z > h.MaxZoom {
+			h.MaxZoom = tile.Z
+		}
+		ext := tileBounds(tile)
+		if bounds == nil {
+			bounds = &ext
+		} else {
+			bounds.Add(&ext)
+		}
+	}
+	if w.opts.Bounds != nil {
+		bounds = w.opts.Bounds
+	}
+	if bounds != nil {
+		h.Bounds = *bounds
+	}
+	if c := w.opts.Center; c != nil {
+		h.Center = [2]float64{c[0], c[1]}
+		h.CenterZoom = slippy.Zoom(c[2])
+	} else {
+		h.Center = [2]float64{(h.Bounds.MinX() + h.Bounds.MaxX()) / 2, (h.Bounds.MinY() + h.Bounds.MaxY()) / 2}
+		h.CenterZoom = h.MinZoom
+	}
+	return h, nil
+}
+
+// tileBounds returns the extent, in longitude and latitude, of a web
+// mercator tile.
+func tileBounds(tile slippy.Tile) geom.Extent {
+	n := math.Exp2(float64(tile.Z))
+	lng := func(x uint) float64 { return float64(x)/n*360 - 180 }
+	lat := func(y uint) float64 {
+		return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi
+	}
+	return geom.Extent{lng(tile.X), lat(tile.Y + 1), lng(tile.X + 1), lat(tile.Y)}
+}
@@ -0,0 +1,325 @@
+package pmtiles
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+)
+
+// writeArchive writes the tiles, in the given order, to an archive.
+func writeArchive(t *testing.T, opts Options, tiles []slippy.Tile, data func(slippy.Tile) []byte) *Reader {
+	t.Helper()
+	opts.TempDir = t.TempDir()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, opts)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	for _, tile := range tiles {
+		if err := w.WriteTile(tile, data(tile)); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if err := w.WriteTile(slippy.Tile{}, nil); err != ErrClosed {
+		t.Errorf("error, expected %v got %v", ErrClosed, err)
+	}
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	return r
+}
+
+func TestWriteRead(t *testing.T) {
+	type tcase struct {
+		opts  Options
+		tiles []slippy.Tile
+		// data returns the data of a tile
+		data func(slippy.Tile) []byte
+		// entries is the expected number of tile entries
+		entries  uint64
+		contents uint64
+		leaves   bool
+	}
+	unique := func(tile slippy.Tile) []byte { return []byte(tile.String()) }
+	// every tile but the first column is the same, like the ocean
+	ocean := func(tile slippy.Tile) []byte {
+		if tile.X == 0 {
+			return []byte(tile.String())
+		}
+		return []byte("ocean")
+	}
+	pyramid := func(maxZoom slippy.Zoom, reverse bool) []slippy.Tile {
+		var tiles []slippy.Tile
+		for z := slippy.Zoom(0); z <= maxZoom; z++ {
+			slippy.Tile{}.FamilyAt(z)(func(tile slippy.Tile) bool {
+				tiles = append(tiles, tile)
+				return true
+			})
+		}
+		if reverse {
+			for i, j := 0, len(tiles)-1; i < j; i, j = i+1, j-1 {
+				tiles[i], tiles[j] = tiles[j], tiles[i]
+			}
+		}
+		return tiles
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			r := writeArchive(t, tc.opts, tc.tiles, tc.data)
+			h := r.Header()
+			if h.NumAddressedTiles != uint64(len(tc.tiles)) {
+				t.Errorf("addressed tiles, expected %v got %v", len(tc.tiles), h.NumAddressedTiles)
+			}
+			if h.NumTileEntries != tc.entries {
+				t.Errorf("tile entries, expected %v got %v", tc.entries, h.NumTileEntries)
+			}
+			if h.NumTileContents != tc.contents {
+				t.Errorf("tile contents, expected %v got %v", tc.contents, h.NumTileContents)
+			}
+			if (h.LeafDirsLength > 0) != tc.leaves {
+				t.Errorf("leaves, expected %v got %v", tc.leaves, h.LeafDirsLength > 0)
+			}
+			if !h.Clustered {
+				t.Errorf("clustered, expected true got false")
+			}
+			if h.RootOffset+h.RootLength > RootSize {
+				t.Errorf("root, expected to end before %v got %v", RootSize, h.RootOffset+h.RootLength)
+			}
+
+			for _, tile := range tc.tiles {
+				data, err := r.Tile(tile)
+				if err != nil {
+					t.Fatalf("%v: error, expected nil got %v", tile, err)
+				}
+				if !bytes.Equal(data, tc.data(tile)) {
+					t.Errorf("%v: data, expected %q got %q", tile, tc.data(tile), data)
+				}
+			}
+			if _, err := r.Tile(slippy.Tile{Z: h.MaxZoom + 1}); err != ErrTileNotFound {
+				t.Errorf("error, expected %v got %v", ErrTileNotFound, err)
+			}
+
+			var (
+				count uint64
+				last  = uint64(0)
+			)
+			err := r.EachTile(func(tile slippy.Tile, data []byte) bool {
+				id, _ := TileID(tile)
+				if count > 0 && id <= last {
+					t.Errorf("tile %v, expected after %v", id, last)
+				}
+				if !bytes.Equal(data, tc.data(tile)) {
+					t.Errorf("%v: data, expected %q got %q", tile, tc.data(tile), data)
+				}
+				last = id
+				count++
+				return true
+			})
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if count != uint64(len(tc.tiles)) {
+				t.Errorf("tiles, expected %v got %v", len(tc.tiles), count)
+			}
+		}
+	}
+	tests := map[string]tcase{
+		"single tile": {
+			tiles:    []slippy.Tile{{Z: 3, X: 1, Y: 2}},
+			data:     unique,
+			entries:  1,
+			contents: 1,
+		},
+		"unique tiles out of order": {
+			tiles:    pyramid(3, true),
+			data:     unique,
+			entries:  85,
+			contents: 85,
+		},
+		"runs": {
+			// the ocean tiles between the tiles of the first column, along
+			// the curve, are single runs; which can cross into the next
+			// zoom.
+			tiles:    pyramid(3, false),
+			data:     ocean,
+			entries:  21,
+			contents: 16,
+		},
+		"uncompressed directories": {
+			opts:     Options{InternalCompression: CompressionNone},
+			tiles:    pyramid(2, false),
+			data:     unique,
+			entries:  21,
+			contents: 21,
+		},
+		"leaf directories": {
+			opts:     Options{InternalCompression: CompressionNone},
+			tiles:    pyramid(7, false),
+			data:     unique,
+			entries:  21845,
+			contents: 21845,
+			leaves:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestWriterHeader(t *testing.T) {
+	tiles := []slippy.Tile{{Z: 2, X: 1, Y: 1}, {Z: 4, X: 7, Y: 6}}
+	data := func(tile slippy.Tile) []byte { return []byte(fmt.Sprint(tile)) }
+
+	r := writeArchive(t, Options{TileType: TileTypeMVT, TileCompression: CompressionGzip}, tiles, data)
+	h := r.Header()
+	if h.TileType != TileTypeMVT || h.TileCompression != CompressionGzip || h.InternalCompression != CompressionGzip {
+		t.Errorf("types, expected mvt, gzip and gzip got %v, %v and %v", h.TileType, h.TileCompression, h.InternalCompression)
+	}
+	if h.MinZoom != 2 || h.MaxZoom != 4 || h.CenterZoom != 2 {
+		t.Errorf("zooms, expected 2, 4 and 2 got %v, %v and %v", h.MinZoom, h.MaxZoom, h.CenterZoom)
+	}
+	// the extent of 2/1/1
+	bounds := geom.Extent{-90, 0, 0, 66.5132604}
+	for i := range bounds {
+		if d := bounds[i] - h.Bounds[i]; d > 1e-7 || d < -1e-7 {
+			t.Errorf("bounds, expected %v got %v", bounds, h.Bounds)
+			break
+		}
+	}
+	if _, err := r.Tile(slippy.Tile{Z: 3, X: 2, Y: 2}); err != ErrTileNotFound {
+		t.Errorf("error, expected %v got %v", ErrTileNotFound, err)
+	}
+
+	r = writeArchive(t, Options{
+		Bounds: &geom.Extent{-10, -20, 30, 40},
+		Center: &[3]float64{1.5, 2.5, 3},
+	}, tiles, data)
+	h = r.Header()
+	if h.Bounds != (geom.Extent{-10, -20, 30, 40}) {
+		t.Errorf("bounds, expected the option got %v", h.Bounds)
+	}
+	if h.Center != [2]float64{1.5, 2.5} || h.CenterZoom != 3 {
+		t.Errorf("center, expected the option got %v at %v", h.Center, h.CenterZoom)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	type metadata struct {
+		Name         string `json:"name"`
+		VectorLayers []struct {
+			ID string `json:"id"`
+		} `json:"vector_layers"`
+	}
+	md := metadata{Name: "test"}
+	md.VectorLayers = append(md.VectorLayers, struct {
+		ID string `json:"id"`
+	}{ID: "roads"})
+
+	for _, c := range []Compression{CompressionNone, CompressionGzip} {
+		r := writeArchive(t, Options{InternalCompression: c, Metadata: md}, nil, nil)
+		var got metadata
+		if err := r.Metadata(&got); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(got, md) {
+			t.Errorf("metadata, expected %+v got %+v", md, got)
+		}
+	}
+
+	r := writeArchive(t, Options{}, nil, nil)
+	var got map[string]interface{}
+	if err := r.Metadata(&got); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("metadata, expected an empty object got %v", got)
+	}
+}
+
+// archive returns an archive, without tile data, of the header and the root
+// directory, with the header's root offset and length set unless they are.
+func archive(t *testing.T, h Header, root []Entry) []byte {
+	t.Helper()
+	dir, err := EncodeDirectory(root, CompressionNone)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if h.RootOffset == 0 && h.RootLength == 0 {
+		h.RootOffset, h.RootLength = HeaderSize, uint64(len(dir))
+	}
+	h.InternalCompression = CompressionNone
+	hdr, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	return append(hdr, dir...)
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("not an archive"))); err != ErrInvalidHeader {
+		t.Errorf("error, expected %v got %v", ErrInvalidHeader, err)
+	}
+
+	for name, h := range map[string]Header{
+		"root too long":    {RootOffset: HeaderSize, RootLength: 1 << 40},
+		"root past buffer": {RootOffset: HeaderSize, RootLength: RootSize - HeaderSize},
+		"root overflows":   {RootOffset: math.MaxUint64 - 10, RootLength: 20},
+	} {
+		if _, err := NewReader(bytes.NewReader(archive(t, h, nil))); err != ErrInvalidHeader {
+			t.Errorf("%v: error, expected %v got %v", name, ErrInvalidHeader, err)
+		}
+	}
+
+	for name, h := range map[string]Header{
+		"metadata too long":  {MetadataOffset: HeaderSize, MetadataLength: 1 << 40},
+		"metadata overflows": {MetadataOffset: math.MaxUint64 - 10, MetadataLength: 20},
+	} {
+		r, err := NewReader(bytes.NewReader(archive(t, h, nil)))
+		if err != nil {
+			t.Fatalf("%v: error, expected nil got %v", name, err)
+		}
+		var md map[string]interface{}
+		if err := r.Metadata(&md); err != ErrInvalidHeader {
+			t.Errorf("%v: error, expected %v got %v", name, ErrInvalidHeader, err)
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		h    Header
+		root []Entry
+	}{
+		"leaf too long": {
+			h:    Header{MaxZoom: 1},
+			root: []Entry{{TileID: 0, Length: math.MaxUint32}},
+		},
+		"leaf overflows": {
+			h:    Header{MaxZoom: 1, LeafDirsOffset: math.MaxUint64 - 10},
+			root: []Entry{{TileID: 0, Offset: 5, Length: 20}},
+		},
+		"tile overflows": {
+			h:    Header{MaxZoom: 1, TileDataOffset: math.MaxUint64 - 10},
+			root: []Entry{{TileID: 0, Offset: 5, Length: 20, RunLength: 1}},
+		},
+	} {
+		r, err := NewReader(bytes.NewReader(archive(t, tc.h, tc.root)))
+		if err != nil {
+			t.Fatalf("%v: error, expected nil got %v", name, err)
+		}
+		if _, err := r.Tile(slippy.Tile{}); err != ErrInvalidDirectory {
+			t.Errorf("%v: error, expected %v got %v", name, ErrInvalidDirectory, err)
+		}
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, Options{InternalCompression: CompressionBrotli}); err != ErrUnsupportedCompression {
+		t.Errorf("error, expected %v got %v", ErrUnsupportedCompression, err)
+	}
+}
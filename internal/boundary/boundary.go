@@ -0,0 +1,34 @@
+// Package boundary provides the boundary of polygonal geometries. It is used
+// by the cover functions of both the slippy and the geohash packages.
+package boundary
+
+import "github.com/go-spatial/geom"
+
+// Polygons returns the closed rings of the polygons of the geometry, or nil
+// if the geometry is not a polygon or multi polygon. A tile or cell that
+// intersects a polygon, but not its boundary, is inside of the polygon.
+func Polygons(g geom.Geometry) geom.MultiLineString {
+	var rings geom.MultiLineString
+	switch gg := g.(type) {
+	case geom.Polygoner:
+		for _, ring := range gg.LinearRings() {
+			rings = append(rings, closeRing(ring))
+		}
+	case geom.MultiPolygoner:
+		for _, plyg := range gg.Polygons() {
+			for _, ring := range plyg {
+				rings = append(rings, closeRing(ring))
+			}
+		}
+	}
+	return rings
+}
+
+// closeRing returns the ring with its first point added to the end, if it
+// is not already there. The ring is not modified.
+func closeRing(ring [][2]float64) [][2]float64 {
+	if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+		return append(ring[:len(ring):len(ring)], ring[0])
+	}
+	return ring
+}
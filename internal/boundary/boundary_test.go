@@ -0,0 +1,61 @@
+package boundary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestPolygons(t *testing.T) {
+	type tcase struct {
+		g        geom.Geometry
+		expected geom.MultiLineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := Polygons(tc.g)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("rings, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			g: geom.Point{1, 1},
+		},
+		"open polygon": {
+			g: geom.Polygon{
+				{{0, 0}, {10, 0}, {10, 10}},
+				{{2, 2}, {4, 2}, {4, 4}, {2, 2}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {10, 0}, {10, 10}, {0, 0}},
+				{{2, 2}, {4, 2}, {4, 4}, {2, 2}},
+			},
+		},
+		"multi polygon": {
+			g: geom.MultiPolygon{
+				{{{0, 0}, {1, 0}, {1, 1}}},
+				{{{5, 5}, {6, 5}, {6, 6}}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+				{{5, 5}, {6, 5}, {6, 6}, {5, 5}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	// the input ring must not be modified
+	ring := make([][2]float64, 3, 4)
+	copy(ring, [][2]float64{{0, 0}, {1, 0}, {1, 1}})
+	Polygons(geom.Polygon{ring})
+	if got := ring[:4][3]; got != ([2]float64{}) {
+		t.Errorf("input ring, expected unmodified got %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package rtreego
+
+import "container/heap"
+
+// distanceItem is an entry in the distance queue. It is either a node, an
+// object with only the lower bound distance of its bounding box, or an object
+// with its exact distance.
+type distanceItem struct {
+	node  *node
+	obj   Spatial
+	exact bool
+	dist  float64
+}
+
+type distanceQueue []distanceItem
+
+// Implements the container/heap interface.
+func (q distanceQueue) Len() int            { return len(q) }
+func (q distanceQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q distanceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(x interface{}) { *q = append(*q, x.(distanceItem)) }
+func (q *distanceQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
+
+// DistanceIterator returns the objects of a tree in order of increasing
+// distance. It is a best-first search; nodes and objects are kept in a single
+// priority queue, using the distance to the bounding box as a lower bound
+// until the exact distance of an object is needed.
+type DistanceIterator struct {
+	rectDist func(*Rect) float64
+	objDist  func(Spatial) float64
+	queue    distanceQueue
+}
+
+// NewDistanceIterator returns an iterator over the objects in the tree, in
+// order of increasing distance. rectDist must return the smallest distance
+// to anything in the rectangle, and objDist the distance to the object; it
+// must not be less than rectDist of the object's bounds. The tree must not be
+// modified while iterating.
+func (tree *Rtree) NewDistanceIterator(rectDist func(*Rect) float64, objDist func(Spatial) float64) *DistanceIterator {
+	it := &DistanceIterator{
+		rectDist: rectDist,
+		objDist:  objDist,
+	}
+	if tree.root != nil && len(tree.root.entries) > 0 {
+		heap.Push(&it.queue, distanceItem{node: tree.root})
+	}
+	return it
+}
+
+// Next returns the next nearest object and its distance. ok is false once
+// all of the objects have been returned.
+func (it *DistanceIterator) Next() (obj Spatial, dist float64, ok bool) {
+	for it.queue.Len() > 0 {
+		item := heap.Pop(&it.queue).(distanceItem)
+		switch {
+		case item.node == nil && item.exact:
+			return item.obj, item.dist, true
+
+		case item.node == nil:
+			// only the bounding box distance is known; queue it again with
+			// the exact distance.
+			item.dist, item.exact = it.objDist(item.obj), true
+			heap.Push(&it.queue, item)
+
+		default:
+			for _, e := range item.node.entries {
+				next := distanceItem{dist: it.rectDist(e.bb)}
+				if item.node.leaf {
+					next.obj = e.obj
+				} else {
+					next.node = e.child
+				}
+				heap.Push(&it.queue, next)
+			}
+		}
+	}
+	return nil, 0, false
+}
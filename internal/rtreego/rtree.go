@@ -824,3 +824,25 @@ func (tree *Rtree) nearestNeighbors(k int, p Point, n *node, dists []float64, ne
 	}
 	return nearest, dists, abort
 }
+
+// Each calls fn for each object in the tree, until fn returns false.
+func (tree *Rtree) Each(fn func(obj Spatial) bool) {
+	if tree.root != nil {
+		tree.each(tree.root, fn)
+	}
+}
+
+func (tree *Rtree) each(n *node, fn func(obj Spatial) bool) bool {
+	for _, e := range n.entries {
+		if !n.leaf {
+			if !tree.each(e.child, fn) {
+				return false
+			}
+			continue
+		}
+		if !fn(e.obj) {
+			return false
+		}
+	}
+	return true
+}
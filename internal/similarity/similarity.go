@@ -0,0 +1,175 @@
+// Package similarity provides measures of how different two geometries are.
+// It is used by both the cmp and the planar packages, which can not import
+// each other.
+package similarity
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+var (
+	// ErrInvalidDensifyFraction is returned when the densify fraction is not
+	// between 0 and 1.
+	ErrInvalidDensifyFraction = errors.New("similarity: densify fraction must be between 0 and 1")
+	// ErrEmptyGeometry is returned when a geometry does not have any points.
+	ErrEmptyGeometry = errors.New("similarity: empty geometry")
+)
+
+// segments returns the segments of the geometry, points of the geometry are
+// returned as zero length segments.
+func segments(g geom.Geometry) ([]geom.Line, error) {
+	lines, err := geom.ExtractLines(g)
+	if err != nil {
+		return nil, err
+	}
+	pts, err := geom.GetCoordinates(g)
+	if err != nil {
+		return nil, err
+	}
+	for _, pt := range pts {
+		lines = append(lines, geom.Line{pt, pt})
+	}
+	if len(lines) == 0 {
+		return nil, ErrEmptyGeometry
+	}
+	return lines, nil
+}
+
+// densify returns the vertices of the geometry, adding points along each
+// segment so no part of a segment is longer then fraction of the segment.
+func densify(g geom.Geometry, fraction float64) ([][2]float64, error) {
+	pts, err := geom.GetCoordinates(g)
+	if err != nil {
+		return nil, err
+	}
+	vertices := make([][2]float64, 0, len(pts))
+	for _, pt := range pts {
+		vertices = append(vertices, [2]float64(pt))
+	}
+	if fraction == 0 || fraction == 1 {
+		return vertices, nil
+	}
+	lines, err := geom.ExtractLines(g)
+	if err != nil {
+		return nil, err
+	}
+	n := int(math.Ceil(1 / fraction))
+	for _, l := range lines {
+		for i := 1; i < n; i++ {
+			t := float64(i) / float64(n)
+			vertices = append(vertices, [2]float64{
+				l[0][0] + t*(l[1][0]-l[0][0]),
+				l[0][1] + t*(l[1][1]-l[0][1]),
+			})
+		}
+	}
+	return vertices, nil
+}
+
+// distanceToSegment returns the distance from pt to the segment.
+func distanceToSegment(seg geom.Line, pt [2]float64) float64 {
+	dx, dy := seg[1][0]-seg[0][0], seg[1][1]-seg[0][1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return math.Hypot(pt[0]-seg[0][0], pt[1]-seg[0][1])
+	}
+	t := ((pt[0]-seg[0][0])*dx + (pt[1]-seg[0][1])*dy) / l2
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(pt[0]-(seg[0][0]+t*dx), pt[1]-(seg[0][1]+t*dy))
+}
+
+// directed returns the largest distance from any of the vertices to the
+// closest segment.
+func directed(vertices [][2]float64, segs []geom.Line) (max float64) {
+	for _, pt := range vertices {
+		min := math.Inf(1)
+		for _, seg := range segs {
+			if d := distanceToSegment(seg, pt); d < min {
+				min = d
+				if min <= max {
+					// can not make max any larger
+					break
+				}
+			}
+		}
+		if min > max {
+			max = min
+		}
+	}
+	return max
+}
+
+// HausdorffDistance returns the Hausdorff distance between the two
+// geometries; the largest distance from a point on either geometry to the
+// closest point on the other geometry. The distance is measured from the
+// vertices of each geometry to the segments of the other. If densifyFraction
+// is between 0 and 1, each segment is split into parts that are that fraction
+// of the segment, adding vertices to measure from. A densifyFraction of 0 does
+// not add any vertices.
+func HausdorffDistance(a, b geom.Geometry, densifyFraction float64) (float64, error) {
+	if !(densifyFraction >= 0 && densifyFraction <= 1) {
+		return 0, ErrInvalidDensifyFraction
+	}
+	segsA, err := segments(a)
+	if err != nil {
+		return 0, err
+	}
+	segsB, err := segments(b)
+	if err != nil {
+		return 0, err
+	}
+	vertA, err := densify(a, densifyFraction)
+	if err != nil {
+		return 0, err
+	}
+	vertB, err := densify(b, densifyFraction)
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(directed(vertA, segsB), directed(vertB, segsA)), nil
+}
+
+// FrechetDistance returns the discrete Fréchet distance between the two
+// geometries. The vertices of each geometry are used in the order they are
+// returned by geom.GetCoordinates, so this is most meaningful for linestrings.
+func FrechetDistance(a, b geom.Geometry) (float64, error) {
+	ptsA, err := geom.GetCoordinates(a)
+	if err != nil {
+		return 0, err
+	}
+	ptsB, err := geom.GetCoordinates(b)
+	if err != nil {
+		return 0, err
+	}
+	if len(ptsA) == 0 || len(ptsB) == 0 {
+		return 0, ErrEmptyGeometry
+	}
+
+	dist := func(i, j int) float64 {
+		return math.Hypot(ptsA[i][0]-ptsB[j][0], ptsA[i][1]-ptsB[j][1])
+	}
+
+	// We only need the previous row of the coupling table.
+	prev := make([]float64, len(ptsB))
+	curr := make([]float64, len(ptsB))
+	for i := range ptsA {
+		for j := range ptsB {
+			d := dist(i, j)
+			switch {
+			case i == 0 && j == 0:
+				curr[j] = d
+			case i == 0:
+				curr[j] = math.Max(curr[j-1], d)
+			case j == 0:
+				curr[j] = math.Max(prev[j], d)
+			default:
+				curr[j] = math.Max(math.Min(math.Min(prev[j], prev[j-1]), curr[j-1]), d)
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(ptsB)-1], nil
+}
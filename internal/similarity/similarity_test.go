@@ -0,0 +1,111 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestHausdorffDistance(t *testing.T) {
+	type tcase struct {
+		a, b     geom.Geometry
+		densify  float64
+		expected float64
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := HausdorffDistance(tc.a, tc.b, tc.densify)
+			if err != tc.err {
+				t.Fatalf("error, expected %v, got %v", tc.err, err)
+			}
+			if math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("distance, expected %v, got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"same": {
+			a:        geom.LineString{{0, 0}, {10, 0}},
+			b:        geom.LineString{{10, 0}, {5, 0}, {0, 0}},
+			expected: 0,
+		},
+		"offset": {
+			a:        geom.LineString{{0, 0}, {10, 0}},
+			b:        geom.LineString{{0, 1}, {10, 1}},
+			expected: 1,
+		},
+		"extra vertex": {
+			a:        geom.LineString{{0, 0}, {5, 3}, {10, 0}},
+			b:        geom.LineString{{0, 0}, {10, 0}},
+			expected: 3,
+		},
+		// without densifying, the vertices of a are all on b; but the
+		// middle of the a's segment is 2 away from b.
+		"densify": {
+			a:        geom.LineString{{0, 0}, {10, 0}},
+			b:        geom.LineString{{0, 0}, {5, 2}, {10, 0}},
+			densify:  0.5,
+			expected: 2,
+		},
+		"polygon point": {
+			a:        geom.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}}},
+			b:        geom.Point{2, 2},
+			expected: math.Sqrt(8),
+		},
+		"bad fraction": {
+			a:       geom.Point{0, 0},
+			b:       geom.Point{0, 0},
+			densify: 2,
+			err:     ErrInvalidDensifyFraction,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestFrechetDistance(t *testing.T) {
+	type tcase struct {
+		a, b     geom.Geometry
+		expected float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := FrechetDistance(tc.a, tc.b)
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("distance, expected %v, got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"same": {
+			a:        geom.LineString{{0, 0}, {5, 0}, {10, 0}},
+			b:        geom.LineString{{0, 0}, {5, 0}, {10, 0}},
+			expected: 0,
+		},
+		"offset": {
+			a:        geom.LineString{{0, 0}, {5, 0}, {10, 0}},
+			b:        geom.LineString{{0, 1}, {5, 1}, {10, 1}},
+			expected: 1,
+		},
+		// The reversed line has a hausdorff distance of 0, but the
+		// direction matters for fréchet.
+		"reversed": {
+			a:        geom.LineString{{0, 0}, {10, 0}},
+			b:        geom.LineString{{10, 0}, {0, 0}},
+			expected: 10,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,568 @@
+// Package transform walks all the geom geometry types, including the Z, M and
+// SRID variants, applying a function to each of their coordinates. It is
+// used to build packages that transform geometries, like affine and reproject.
+package transform
+
+import (
+	"reflect"
+
+	"github.com/go-spatial/geom"
+)
+
+// Func transforms a coordinate. For coordinates without a z value, z will
+// be zero, and the returned z value is ignored. M values are not transformed.
+type Func func(x, y, z float64) (float64, float64, float64, error)
+
+// Mapper applies a Func to the coordinates of a geometry
+type Mapper struct {
+	// Transform is applied to every coordinate
+	Transform Func
+
+	// Densify, if set, returns the number of parts the segment between two
+	// consecutive vertices, of a line or ring, should be split into before
+	// being transformed. Z and M values are interpolated for the added
+	// vertices. Values less than 2 do not add any vertices.
+	Densify func(a, b [2]float64) int
+
+	// SRID, if set, is the SRID to set on the geometries with an SRID.
+	SRID *uint32
+}
+
+// dims is the layout of the coordinates of a geometry
+type dims uint8
+
+const (
+	xy dims = iota
+	xyz
+	xym
+	xyzm
+)
+
+// coord is a coordinate with all four dimensions.
+type coord [4]float64
+
+func (m Mapper) srid(s geom.Srid) geom.Srid {
+	if m.SRID == nil {
+		return s
+	}
+	return geom.Srid(*m.SRID)
+}
+
+func (m Mapper) coord(c coord, d dims) (coord, error) {
+	z := c[2]
+	if d == xy || d == xym {
+		z = 0
+	}
+	x, y, z, err := m.Transform(c[0], c[1], z)
+	if err != nil {
+		return c, err
+	}
+	if d == xy || d == xym {
+		z = c[2]
+	}
+	return coord{x, y, z, c[3]}, nil
+}
+
+// seq transforms a sequence of coordinates, densifying them if needed. If
+// closed is true the last vertex is connected to the first one.
+func (m Mapper) seq(cs []coord, d dims, closed bool) ([]coord, error) {
+	if m.Densify != nil && len(cs) > 1 {
+		dense := make([]coord, 0, len(cs))
+		end := len(cs) - 1
+		if closed {
+			end = len(cs)
+		}
+		for i := 0; i < end; i++ {
+			a, b := cs[i], cs[(i+1)%len(cs)]
+			dense = append(dense, a)
+			n := m.Densify([2]float64{a[0], a[1]}, [2]float64{b[0], b[1]})
+			for j := 1; j < n; j++ {
+				t := float64(j) / float64(n)
+				dense = append(dense, coord{
+					a[0] + t*(b[0]-a[0]),
+					a[1] + t*(b[1]-a[1]),
+					a[2] + t*(b[2]-a[2]),
+					a[3] + t*(b[3]-a[3]),
+				})
+			}
+		}
+		if !closed {
+			dense = append(dense, cs[len(cs)-1])
+		}
+		cs = dense
+	}
+	out := make([]coord, len(cs))
+	for i := range cs {
+		c, err := m.coord(cs[i], d)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = c
+	}
+	return out, nil
+}
+
+func from2(vs [][2]float64) []coord {
+	cs := make([]coord, len(vs))
+	for i, v := range vs {
+		cs[i] = coord{v[0], v[1]}
+	}
+	return cs
+}
+
+func to2(cs []coord) [][2]float64 {
+	vs := make([][2]float64, len(cs))
+	for i, c := range cs {
+		vs[i] = [2]float64{c[0], c[1]}
+	}
+	return vs
+}
+
+// from3 converts xyz or xym vertices to coordinates
+func from3(vs [][3]float64, d dims) []coord {
+	cs := make([]coord, len(vs))
+	for i, v := range vs {
+		if d == xym {
+			cs[i] = coord{v[0], v[1], 0, v[2]}
+			continue
+		}
+		cs[i] = coord{v[0], v[1], v[2]}
+	}
+	return cs
+}
+
+func to3(cs []coord, d dims) [][3]float64 {
+	vs := make([][3]float64, len(cs))
+	for i, c := range cs {
+		if d == xym {
+			vs[i] = [3]float64{c[0], c[1], c[3]}
+			continue
+		}
+		vs[i] = [3]float64{c[0], c[1], c[2]}
+	}
+	return vs
+}
+
+func from4(vs [][4]float64) []coord {
+	cs := make([]coord, len(vs))
+	for i, v := range vs {
+		cs[i] = coord(v)
+	}
+	return cs
+}
+
+func to4(cs []coord) [][4]float64 {
+	vs := make([][4]float64, len(cs))
+	for i, c := range cs {
+		vs[i] = [4]float64(c)
+	}
+	return vs
+}
+
+func (m Mapper) seq2(vs [][2]float64, closed bool) ([][2]float64, error) {
+	cs, err := m.seq(from2(vs), xy, closed)
+	if err != nil {
+		return nil, err
+	}
+	return to2(cs), nil
+}
+
+func (m Mapper) seq3(vs [][3]float64, d dims, closed bool) ([][3]float64, error) {
+	cs, err := m.seq(from3(vs, d), d, closed)
+	if err != nil {
+		return nil, err
+	}
+	return to3(cs, d), nil
+}
+
+func (m Mapper) seq4(vs [][4]float64, closed bool) ([][4]float64, error) {
+	cs, err := m.seq(from4(vs), xyzm, closed)
+	if err != nil {
+		return nil, err
+	}
+	return to4(cs), nil
+}
+
+// points transforms vertices that are not connected to each other
+func (m Mapper) points(cs []coord, d dims) ([]coord, error) {
+	// don't densify points.
+	m.Densify = nil
+	return m.seq(cs, d, false)
+}
+
+func (m Mapper) multi2(lines [][][2]float64, closed bool) ([][][2]float64, error) {
+	out := make([][][2]float64, len(lines))
+	for i := range lines {
+		var err error
+		if out[i], err = m.seq2(lines[i], closed); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (m Mapper) multi3(lines [][][3]float64, d dims, closed bool) ([][][3]float64, error) {
+	out := make([][][3]float64, len(lines))
+	for i := range lines {
+		var err error
+		if out[i], err = m.seq3(lines[i], d, closed); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (m Mapper) multi4(lines [][][4]float64, closed bool) ([][][4]float64, error) {
+	out := make([][][4]float64, len(lines))
+	for i := range lines {
+		var err error
+		if out[i], err = m.seq4(lines[i], closed); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (m Mapper) point(c coord, d dims) (coord, error) { return m.coord(c, d) }
+
+// Extent transforms the extent, returning the extent of the transformed
+// edges of the extent.
+func (m Mapper) Extent(e *geom.Extent) (*geom.Extent, error) {
+	if e == nil {
+		return nil, nil
+	}
+	ring, err := m.seq2(e.Vertices(), true)
+	if err != nil {
+		return nil, err
+	}
+	return geom.NewExtent(ring...), nil
+}
+
+// Geometry returns a new geometry, of the same type, with the coordinates
+// transformed. For a pointer to a geometry, a pointer to the new geometry is
+// returned; nil pointers are returned as is. On error a nil geometry is
+// returned.
+func (m Mapper) Geometry(g geom.Geometry) (geom.Geometry, error) {
+	geo, err := m.geometry(g)
+	if err != nil {
+		return nil, err
+	}
+	return geo, nil
+}
+
+// geometry transforms the geometry; on error the returned geometry may be
+// partly transformed.
+func (m Mapper) geometry(g geom.Geometry) (geom.Geometry, error) {
+	switch geo := g.(type) {
+	default:
+		return m.pointer(g)
+
+	case geom.Extent:
+		e, err := m.Extent(&geo)
+		if err != nil {
+			return nil, err
+		}
+		return *e, nil
+
+	case *geom.Extent:
+		return m.Extent(geo)
+
+	// Points
+	case geom.Point:
+		c, err := m.point(coord{geo[0], geo[1]}, xy)
+		return geom.Point{c[0], c[1]}, err
+
+	case geom.PointZ:
+		c, err := m.point(coord{geo[0], geo[1], geo[2]}, xyz)
+		return geom.PointZ{c[0], c[1], c[2]}, err
+
+	case geom.PointM:
+		c, err := m.point(coord{geo[0], geo[1], 0, geo[2]}, xym)
+		return geom.PointM{c[0], c[1], c[3]}, err
+
+	case geom.PointZM:
+		c, err := m.point(coord(geo), xyzm)
+		return geom.PointZM(c), err
+
+	case geom.PointS:
+		pt, err := m.Geometry(geo.Xy)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PointS{Srid: m.srid(geo.Srid), Xy: pt.(geom.Point)}, nil
+
+	case geom.PointZS:
+		pt, err := m.Geometry(geo.Xyz)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PointZS{Srid: m.srid(geo.Srid), Xyz: pt.(geom.PointZ)}, nil
+
+	case geom.PointMS:
+		pt, err := m.Geometry(geo.Xym)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PointMS{Srid: m.srid(geo.Srid), Xym: pt.(geom.PointM)}, nil
+
+	case geom.PointZMS:
+		pt, err := m.Geometry(geo.Xyzm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PointZMS{Srid: m.srid(geo.Srid), Xyzm: pt.(geom.PointZM)}, nil
+
+	// MultiPoints
+	case geom.MultiPoint:
+		cs, err := m.points(from2(geo), xy)
+		return geom.MultiPoint(to2(cs)), err
+
+	case geom.MultiPointZ:
+		cs, err := m.points(from3(geo, xyz), xyz)
+		return geom.MultiPointZ(to3(cs, xyz)), err
+
+	case geom.MultiPointM:
+		cs, err := m.points(from3(geo, xym), xym)
+		return geom.MultiPointM(to3(cs, xym)), err
+
+	case geom.MultiPointZM:
+		cs, err := m.points(from4(geo), xyzm)
+		return geom.MultiPointZM(to4(cs)), err
+
+	case geom.MultiPointS:
+		mp, err := m.Geometry(geo.Mp)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiPointS{Srid: m.srid(geo.Srid), Mp: mp.(geom.MultiPoint)}, nil
+
+	case geom.MultiPointZS:
+		mp, err := m.Geometry(geo.Mpz)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiPointZS{Srid: m.srid(geo.Srid), Mpz: mp.(geom.MultiPointZ)}, nil
+
+	case geom.MultiPointMS:
+		mp, err := m.Geometry(geo.Mpm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiPointMS{Srid: m.srid(geo.Srid), Mpm: mp.(geom.MultiPointM)}, nil
+
+	case geom.MultiPointZMS:
+		mp, err := m.Geometry(geo.Mpzm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiPointZMS{Srid: m.srid(geo.Srid), Mpzm: mp.(geom.MultiPointZM)}, nil
+
+	// LineStrings
+	case geom.Line:
+		// densifying would change the type, so lines are not densified.
+		cs, err := m.points(from2(geo[:]), xy)
+		if err != nil {
+			return nil, err
+		}
+		vs := to2(cs)
+		return geom.Line{vs[0], vs[1]}, nil
+
+	case geom.LineString:
+		ls, err := m.seq2(geo, false)
+		return geom.LineString(ls), err
+
+	case geom.LineStringZ:
+		ls, err := m.seq3(geo, xyz, false)
+		return geom.LineStringZ(ls), err
+
+	case geom.LineStringM:
+		ls, err := m.seq3(geo, xym, false)
+		return geom.LineStringM(ls), err
+
+	case geom.LineStringZM:
+		ls, err := m.seq4(geo, false)
+		return geom.LineStringZM(ls), err
+
+	case geom.LineStringS:
+		ls, err := m.Geometry(geo.Ls)
+		if err != nil {
+			return nil, err
+		}
+		return geom.LineStringS{Srid: m.srid(geo.Srid), Ls: ls.(geom.LineString)}, nil
+
+	case geom.LineStringZS:
+		ls, err := m.Geometry(geo.Lsz)
+		if err != nil {
+			return nil, err
+		}
+		return geom.LineStringZS{Srid: m.srid(geo.Srid), Lsz: ls.(geom.LineStringZ)}, nil
+
+	case geom.LineStringMS:
+		ls, err := m.Geometry(geo.Lsm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.LineStringMS{Srid: m.srid(geo.Srid), Lsm: ls.(geom.LineStringM)}, nil
+
+	case geom.LineStringZMS:
+		ls, err := m.Geometry(geo.Lszm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.LineStringZMS{Srid: uint32(m.srid(geom.Srid(geo.Srid))), Lszm: ls.(geom.LineStringZM)}, nil
+
+	// MultiLineStrings
+	case geom.MultiLineString:
+		mls, err := m.multi2(geo, false)
+		return geom.MultiLineString(mls), err
+
+	case geom.MultiLineStringZ:
+		mls, err := m.multi3(geo, xyz, false)
+		return geom.MultiLineStringZ(mls), err
+
+	case geom.MultiLineStringM:
+		mls, err := m.multi3(geo, xym, false)
+		return geom.MultiLineStringM(mls), err
+
+	case geom.MultiLineStringZM:
+		mls, err := m.multi4(geo, false)
+		return geom.MultiLineStringZM(mls), err
+
+	case geom.MultiLineStringS:
+		mls, err := m.Geometry(geo.Mls)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiLineStringS{Srid: m.srid(geo.Srid), Mls: mls.(geom.MultiLineString)}, nil
+
+	case geom.MultiLineStringZS:
+		mls, err := m.Geometry(geo.Mlsz)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiLineStringZS{Srid: m.srid(geo.Srid), Mlsz: mls.(geom.MultiLineStringZ)}, nil
+
+	case geom.MultiLineStringMS:
+		mls, err := m.Geometry(geo.Mlsm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiLineStringMS{Srid: m.srid(geo.Srid), Mlsm: mls.(geom.MultiLineStringM)}, nil
+
+	case geom.MultiLineStringZMS:
+		mls, err := m.Geometry(geo.Mlszm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiLineStringZMS{Srid: m.srid(geo.Srid), Mlszm: mls.(geom.MultiLineStringZM)}, nil
+
+	// Polygons
+	case geom.Triangle:
+		cs, err := m.points(from2(geo[:]), xy)
+		if err != nil {
+			return nil, err
+		}
+		vs := to2(cs)
+		return geom.Triangle{vs[0], vs[1], vs[2]}, nil
+
+	case geom.Polygon:
+		p, err := m.multi2(geo, true)
+		return geom.Polygon(p), err
+
+	case geom.PolygonZ:
+		p, err := m.multi3(geo, xyz, true)
+		return geom.PolygonZ(p), err
+
+	case geom.PolygonM:
+		p, err := m.multi3(geo, xym, true)
+		return geom.PolygonM(p), err
+
+	case geom.PolygonZM:
+		p, err := m.multi4(geo, true)
+		return geom.PolygonZM(p), err
+
+	case geom.PolygonS:
+		p, err := m.Geometry(geo.Pol)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PolygonS{Srid: m.srid(geo.Srid), Pol: p.(geom.Polygon)}, nil
+
+	case geom.PolygonZS:
+		p, err := m.Geometry(geo.Polz)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PolygonZS{Srid: m.srid(geo.Srid), Polz: p.(geom.PolygonZ)}, nil
+
+	case geom.PolygonMS:
+		p, err := m.Geometry(geo.Polm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PolygonMS{Srid: m.srid(geo.Srid), Polm: p.(geom.PolygonM)}, nil
+
+	case geom.PolygonZMS:
+		p, err := m.Geometry(geo.Polzm)
+		if err != nil {
+			return nil, err
+		}
+		return geom.PolygonZMS{Srid: m.srid(geo.Srid), Polzm: p.(geom.PolygonZM)}, nil
+
+	// MultiPolygons
+	case geom.MultiPolygon:
+		mp := make(geom.MultiPolygon, len(geo))
+		for i := range geo {
+			p, err := m.multi2(geo[i], true)
+			if err != nil {
+				return nil, err
+			}
+			mp[i] = p
+		}
+		return mp, nil
+
+	case geom.MultiPolygonS:
+		mp, err := m.Geometry(geo.MultiPolygon)
+		if err != nil {
+			return nil, err
+		}
+		return geom.MultiPolygonS{Srid: m.srid(geo.Srid), MultiPolygon: mp.(geom.MultiPolygon)}, nil
+
+	// Collections
+	case geom.Collection:
+		col := make(geom.Collection, len(geo))
+		for i := range geo {
+			var err error
+			if col[i], err = m.Geometry(geo[i]); err != nil {
+				return nil, err
+			}
+		}
+		return col, nil
+
+	case geom.CollectionS:
+		col, err := m.Geometry(geo.Collection)
+		if err != nil {
+			return nil, err
+		}
+		return geom.CollectionS{Srid: m.srid(geo.Srid), Collection: col.(geom.Collection)}, nil
+	}
+}
+
+// pointer transforms the geometry g points to, and returns a pointer to the
+// result.
+func (m Mapper) pointer(g geom.Geometry) (geom.Geometry, error) {
+	v := reflect.ValueOf(g)
+	if v.Kind() != reflect.Ptr {
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+	if v.IsNil() {
+		return g, nil
+	}
+	geo, err := m.Geometry(v.Elem().Interface())
+	if err != nil {
+		return nil, err
+	}
+	ptr := reflect.New(v.Elem().Type())
+	ptr.Elem().Set(reflect.ValueOf(geo))
+	return ptr.Interface(), nil
+}
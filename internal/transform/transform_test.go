@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestMapperGeometry(t *testing.T) {
+	// translate moves x by 10 and y by 20, and scales z by 2.
+	translate := Mapper{Transform: func(x, y, z float64) (float64, float64, float64, error) {
+		return x + 10, y + 20, z * 2, nil
+	}}
+
+	type tcase struct {
+		geom     geom.Geometry
+		expected geom.Geometry
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := translate.Geometry(tc.geom)
+			if !reflect.DeepEqual(err, tc.err) {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("geometry, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	var (
+		n        = 7
+		nilLine  *geom.LineString
+		point    = geom.Point{1, 2}
+		polygon  = geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}
+		mpolygon = geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}}}}
+	)
+	tcases := map[string]tcase{
+		"point pointer": {
+			geom:     &point,
+			expected: &geom.Point{11, 22},
+		},
+		"polygon pointer": {
+			geom:     &polygon,
+			expected: &geom.Polygon{{{10, 20}, {11, 20}, {11, 21}}},
+		},
+		"multi polygon pointer": {
+			geom:     &mpolygon,
+			expected: &geom.MultiPolygon{{{{10, 20}, {11, 20}, {11, 21}}}},
+		},
+		"nil pointer": {
+			geom:     nilLine,
+			expected: nilLine,
+		},
+		"unknown": {
+			geom: n,
+			err:  geom.ErrUnknownGeometry{Geom: n},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+
+	// on error no geometry is returned.
+	errBad := errors.New("bad coordinate")
+	bad := Mapper{Transform: func(x, y, z float64) (float64, float64, float64, error) {
+		if x > 5 {
+			return 0, 0, 0, errBad
+		}
+		return x, y, z, nil
+	}}
+	for _, g := range []geom.Geometry{geom.Point{6, 0}, geom.LineString{{0, 0}, {6, 0}}, geom.Polygon{{{0, 0}, {6, 0}, {6, 6}}}, &geom.Point{6, 0}} {
+		if got, err := bad.Geometry(g); err != errBad || got != nil {
+			t.Errorf("geometry %v, expected nil, %v got %v, %v", g, errBad, got, err)
+		}
+	}
+
+	// the geometry pointed to is not modified.
+	if point != (geom.Point{1, 2}) {
+		t.Errorf("point, expected unchanged got %v", point)
+	}
+}
@@ -0,0 +1,269 @@
+package coord
+
+import (
+	"math"
+	"strings"
+)
+
+// newEllipsoid returns an Ellipsoid for the given semi-major axis, in meters,
+// and inverse flattening.
+func newEllipsoid(name string, radius, inverseFlattening float64, nato bool) Ellipsoid {
+	f := 1 / inverseFlattening
+	return Ellipsoid{
+		Name:           name,
+		Radius:         radius,
+		Eccentricity:   f * (2 - f),
+		NATOCompatible: nato,
+	}
+}
+
+// Common ellipsoids. The Eccentricity of each is the square of the
+// eccentricity, as used by the utm package.
+var (
+	WGS84Ellipsoid     = newEllipsoid("WGS_84", 6378137, 298.257223563, true)
+	GRS80Ellipsoid     = newEllipsoid("GRS_80", 6378137, 298.257222101, true)
+	Clarke1866         = newEllipsoid("CLARKE_1866", 6378206.4, 294.978698214, false)
+	International1924  = newEllipsoid("INTERNATIONAL_1924", 6378388, 297, false)
+	Airy1830           = newEllipsoid("AIRY_1830", 6377563.396, 299.3249646, false)
+	Bessel1841         = newEllipsoid("BESSEL_1841", 6377397.155, 299.1528128, false)
+	AustralianNational = newEllipsoid("AUSTRALIAN_NATIONAL", 6378160, 298.25, false)
+)
+
+// Flattening returns the flattening of the ellipsoid.
+func (e Ellipsoid) Flattening() float64 { return 1 - math.Sqrt(1-e.Eccentricity) }
+
+// SemiMinorAxis returns the polar radius of the ellipsoid.
+func (e Ellipsoid) SemiMinorAxis() float64 { return e.Radius * math.Sqrt(1-e.Eccentricity) }
+
+// ECEF is an Earth-Centered, Earth-Fixed cartesian coordinate, in the units of
+// the ellipsoid's radius; meters for the common ellipsoids.
+type ECEF struct {
+	X, Y, Z float64
+}
+
+// ToECEF returns the cartesian coordinate of the point at the given height
+// above the ellipsoid.
+func (e Ellipsoid) ToECEF(lnglat LngLat, height float64) ECEF {
+	sinLat, cosLat := math.Sincos(lnglat.LatInRadians())
+	sinLng, cosLng := math.Sincos(lnglat.LngInRadians())
+	// radius of curvature in the prime vertical
+	n := e.Radius / math.Sqrt(1-e.Eccentricity*sinLat*sinLat)
+	return ECEF{
+		X: (n + height) * cosLat * cosLng,
+		Y: (n + height) * cosLat * sinLng,
+		Z: (n*(1-e.Eccentricity) + height) * sinLat,
+	}
+}
+
+// FromECEF returns the longitude, latitude and height above the ellipsoid of
+// the cartesian coordinate.
+func (e Ellipsoid) FromECEF(pt ECEF) (LngLat, float64) {
+	p := math.Hypot(pt.X, pt.Y)
+	lat := math.Atan2(pt.Z, p*(1-e.Eccentricity))
+	var n float64
+	for i := 0; i < 10; i++ {
+		sin := math.Sin(lat)
+		n = e.Radius / math.Sqrt(1-e.Eccentricity*sin*sin)
+		next := math.Atan2(pt.Z+e.Eccentricity*n*sin, p)
+		if math.Abs(next-lat) < 1e-15 {
+			lat = next
+			break
+		}
+		lat = next
+	}
+	sin, cos := math.Sincos(lat)
+	n = e.Radius / math.Sqrt(1-e.Eccentricity*sin*sin)
+	// this form of the height is stable near the poles.
+	height := p*cos + pt.Z*sin - e.Radius*e.Radius/n
+	return LngLat{
+		Lng: ToDegree(math.Atan2(pt.Y, pt.X)),
+		Lat: ToDegree(lat),
+	}, height
+}
+
+// Helmert is a 7-parameter (Bursa-Wolf) transformation between cartesian
+// coordinates. The rotations use the position vector convention (EPSG method
+// 9606), the same as the PROJ towgs84 parameter; negate the rotations for
+// parameters given using the coordinate frame convention (EPSG method 9607).
+type Helmert struct {
+	// Translations in meters
+	Tx, Ty, Tz float64
+	// Rotations in arc-seconds
+	Rx, Ry, Rz float64
+	// Scale difference in parts per million
+	S float64
+}
+
+// arcSecond is an arc-second in radians.
+const arcSecond = math.Pi / (180 * 3600)
+
+// Apply transforms the cartesian coordinate.
+func (h Helmert) Apply(pt ECEF) ECEF {
+	rx, ry, rz := h.Rx*arcSecond, h.Ry*arcSecond, h.Rz*arcSecond
+	s := 1 + h.S*1e-6
+	return ECEF{
+		X: h.Tx + s*(pt.X-rz*pt.Y+ry*pt.Z),
+		Y: h.Ty + s*(rz*pt.X+pt.Y-rx*pt.Z),
+		Z: h.Tz + s*(-ry*pt.X+rx*pt.Y+pt.Z),
+	}
+}
+
+// ApplyInverse transforms the cartesian coordinate in the other direction,
+// undoing Apply exactly. Negating the parameters is only accurate to about a
+// centimeter for the larger transformations.
+func (h Helmert) ApplyInverse(pt ECEF) ECEF {
+	rx, ry, rz := h.Rx*arcSecond, h.Ry*arcSecond, h.Rz*arcSecond
+	s := 1 + h.S*1e-6
+	x, y, z := (pt.X-h.Tx)/s, (pt.Y-h.Ty)/s, (pt.Z-h.Tz)/s
+	// The rotation matrix is I + [r]×, which has the inverse
+	// (I - [r]× + r rᵀ) / (1 + r·r)
+	dot := rx*x + ry*y + rz*z
+	d := 1 + rx*rx + ry*ry + rz*rz
+	return ECEF{
+		X: (x - (ry*z - rz*y) + rx*dot) / d,
+		Y: (y - (rz*x - rx*z) + ry*dot) / d,
+		Z: (z - (rx*y - ry*x) + rz*dot) / d,
+	}
+}
+
+// Datum is a geodetic datum; an ellipsoid and how it is placed relative to
+// WGS84.
+type Datum struct {
+	Name      string
+	Ellipsoid Ellipsoid
+	// ToWGS84 transforms the datum's cartesian coordinates to WGS84.
+	ToWGS84 Helmert
+}
+
+// Common datums. The parameters are the ones published by EPSG; for the
+// datums covering a large area they are the average for the area, and are
+// accurate to a few meters.
+var (
+	WGS84  = Datum{Name: "WGS_84", Ellipsoid: WGS84Ellipsoid}
+	NAD83  = Datum{Name: "NAD_83", Ellipsoid: GRS80Ellipsoid}
+	ETRS89 = Datum{Name: "ETRS_89", Ellipsoid: GRS80Ellipsoid}
+	// NAD27 for the contiguous United States; EPSG:1173
+	NAD27 = Datum{
+		Name:      "NAD_27",
+		Ellipsoid: Clarke1866,
+		ToWGS84:   Helmert{Tx: -8, Ty: 160, Tz: 176},
+	}
+	// ED50 for western Europe; EPSG:1133
+	ED50 = Datum{
+		Name:      "ED_50",
+		Ellipsoid: International1924,
+		ToWGS84:   Helmert{Tx: -87, Ty: -98, Tz: -121},
+	}
+	// OSGB36 for Great Britain; EPSG:1314
+	OSGB36 = Datum{
+		Name:      "OSGB_36",
+		Ellipsoid: Airy1830,
+		ToWGS84: Helmert{
+			Tx: 446.448, Ty: -125.157, Tz: 542.06,
+			Rx: 0.15, Ry: 0.247, Rz: 0.842,
+			S: -20.489,
+		},
+	}
+	// DHDN (Potsdam) for Germany; EPSG:1777
+	DHDN = Datum{
+		Name:      "DHDN",
+		Ellipsoid: Bessel1841,
+		ToWGS84: Helmert{
+			Tx: 598.1, Ty: 73.7, Tz: 418.2,
+			Rx: 0.202, Ry: 0.045, Rz: -2.455,
+			S: 6.7,
+		},
+	}
+	// Tokyo for Japan; EPSG:15484
+	Tokyo = Datum{
+		Name:      "TOKYO",
+		Ellipsoid: Bessel1841,
+		ToWGS84:   Helmert{Tx: -146.414, Ty: 507.337, Tz: 680.507},
+	}
+	// AGD66 for Australia; EPSG:1108
+	AGD66 = Datum{
+		Name:      "AGD_66",
+		Ellipsoid: AustralianNational,
+		ToWGS84:   Helmert{Tx: -133, Ty: -48, Tz: 148},
+	}
+)
+
+// datums is the registry used by DatumByName, keyed by the normalized name.
+var datums = map[string]Datum{}
+
+func init() {
+	for _, d := range []Datum{WGS84, NAD83, ETRS89, NAD27, ED50, OSGB36, DHDN, Tokyo, AGD66} {
+		RegisterDatum(d)
+	}
+}
+
+// normalizeDatumName upper cases the name and removes the characters that are
+// commonly left out; so "WGS84", "wgs 84" and "WGS_84" are the same.
+func normalizeDatumName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(name))
+}
+
+// DatumByName returns the registered datum with the given name. The name is
+// matched ignoring case, spaces, dashes and underscores.
+func DatumByName(name string) (Datum, bool) {
+	d, ok := datums[normalizeDatumName(name)]
+	return d, ok
+}
+
+// RegisterDatum adds the datum to the registry used by DatumByName, replacing
+// any datum with the same name. It is not safe to call concurrently with
+// DatumByName; datums should be registered during initialization.
+func RegisterDatum(d Datum) { datums[normalizeDatumName(d.Name)] = d }
+
+// Transform moves the point, at the given height above the ellipsoid, from
+// this datum to the given datum using the Helmert transformations of both
+// datums, through WGS84. The returned height is above the ellipsoid of the
+// given datum.
+func (d Datum) Transform(lnglat LngLat, height float64, to Datum) (LngLat, float64) {
+	pt := d.Ellipsoid.ToECEF(lnglat, height)
+	pt = d.ToWGS84.Apply(pt)
+	pt = to.ToWGS84.ApplyInverse(pt)
+	return to.Ellipsoid.FromECEF(pt)
+}
+
+// Molodensky moves the point, at the given height above the ellipsoid, from
+// this datum to the given datum using the standard Molodensky formulas. Only
+// the translations of the datums are used, so the result is less accurate
+// than Transform for datums with rotations or a scale; but it does not go
+// through cartesian coordinates.
+func (d Datum) Molodensky(lnglat LngLat, height float64, to Datum) (LngLat, float64) {
+	dx := d.ToWGS84.Tx - to.ToWGS84.Tx
+	dy := d.ToWGS84.Ty - to.ToWGS84.Ty
+	dz := d.ToWGS84.Tz - to.ToWGS84.Tz
+
+	a, e2 := d.Ellipsoid.Radius, d.Ellipsoid.Eccentricity
+	f := d.Ellipsoid.Flattening()
+	da := to.Ellipsoid.Radius - a
+	df := to.Ellipsoid.Flattening() - f
+	b := d.Ellipsoid.SemiMinorAxis()
+
+	sinLat, cosLat := math.Sincos(lnglat.LatInRadians())
+	sinLng, cosLng := math.Sincos(lnglat.LngInRadians())
+	w := 1 - e2*sinLat*sinLat
+	// radius of curvature in the meridian, and in the prime vertical
+	rm := a * (1 - e2) / math.Pow(w, 1.5)
+	rn := a / math.Sqrt(w)
+
+	dLat := (-dx*sinLat*cosLng - dy*sinLat*sinLng + dz*cosLat +
+		da*rn*e2*sinLat*cosLat/a +
+		df*(rm*a/b+rn*b/a)*sinLat*cosLat) / (rm + height)
+	dLng := (-dx*sinLng + dy*cosLng) / ((rn + height) * cosLat)
+	dHeight := dx*cosLat*cosLng + dy*cosLat*sinLng + dz*sinLat -
+		da*a/rn + df*b/a*rn*sinLat*sinLat
+
+	return LngLat{
+		Lng: lnglat.Lng + ToDegree(dLng),
+		Lat: lnglat.Lat + ToDegree(dLat),
+	}, height + dHeight
+}
@@ -0,0 +1,147 @@
+package coord
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEllipsoid_ECEF(t *testing.T) {
+	type tcase struct {
+		lnglat   LngLat
+		height   float64
+		expected ECEF
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := WGS84Ellipsoid.ToECEF(tc.lnglat, tc.height)
+			if math.Abs(got.X-tc.expected.X) > 1e-3 || math.Abs(got.Y-tc.expected.Y) > 1e-3 || math.Abs(got.Z-tc.expected.Z) > 1e-3 {
+				t.Errorf("ecef, expected %v got %v", tc.expected, got)
+			}
+			lnglat, height := WGS84Ellipsoid.FromECEF(got)
+			if math.Abs(lnglat.Lat-tc.lnglat.Lat) > 1e-9 || math.Abs(height-tc.height) > 1e-6 {
+				t.Errorf("lnglat, expected %v %v got %v %v", tc.lnglat, tc.height, lnglat, height)
+			}
+			// the longitude is undefined at the poles
+			if math.Abs(tc.lnglat.Lat) != 90 && math.Abs(lnglat.Lng-tc.lnglat.Lng) > 1e-9 {
+				t.Errorf("lnglat, expected %v got %v", tc.lnglat, lnglat)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"origin":     {lnglat: LngLat{0, 0}, expected: ECEF{X: 6378137}},
+		"east":       {lnglat: LngLat{90, 0}, height: 100, expected: ECEF{Y: 6378237}},
+		"north pole": {lnglat: LngLat{0, 90}, expected: ECEF{Z: 6356752.314245}},
+		"south pole": {lnglat: LngLat{0, -90}, height: -10, expected: ECEF{Z: -6356742.314245}},
+		"pittsburgh": {
+			lnglat:   LngLat{-79.982222, 40.446111},
+			height:   300,
+			expected: ECEF{X: 845580.010, Y: -4786836.717, Z: 4116002.385},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDatum_Transform(t *testing.T) {
+	type tcase struct {
+		from, to Datum
+		lnglat   LngLat
+		expected LngLat
+		// tolerance in degrees
+		tolerance float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, height := tc.from.Transform(tc.lnglat, 0, tc.to)
+			if math.Abs(got.Lat-tc.expected.Lat) > tc.tolerance || math.Abs(got.Lng-tc.expected.Lng) > tc.tolerance {
+				t.Errorf("transform, expected %v got %v", tc.expected, got)
+			}
+
+			// going back should give the original point
+			back, height := tc.to.Transform(got, height, tc.from)
+			if math.Abs(back.Lat-tc.lnglat.Lat) > 1e-8 || math.Abs(back.Lng-tc.lnglat.Lng) > 1e-8 {
+				t.Errorf("inverse, expected %v got %v", tc.lnglat, back)
+			}
+			if math.Abs(height) > 0.01 {
+				t.Errorf("inverse height, expected 0 got %v", height)
+			}
+
+			// molodensky should be close for the datums without rotations
+			if tc.from.ToWGS84.Rx != 0 || tc.to.ToWGS84.Rx != 0 {
+				return
+			}
+			mgot, _ := tc.from.Molodensky(tc.lnglat, 0, tc.to)
+			if math.Abs(mgot.Lat-got.Lat) > 1e-6 || math.Abs(mgot.Lng-got.Lng) > 1e-6 {
+				t.Errorf("molodensky, expected %v got %v", got, mgot)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"same datum": {
+			from:      WGS84,
+			to:        WGS84,
+			lnglat:    LngLat{-79.982222, 40.446111},
+			expected:  LngLat{-79.982222, 40.446111},
+			tolerance: 1e-12,
+		},
+		"airy transit circle": {
+			// The IERS reference meridian is about 102 meters east of the
+			// Airy transit circle, which defines the OSGB36 meridian.
+			from:      OSGB36,
+			to:        WGS84,
+			lnglat:    LngLat{0, 51.4772},
+			expected:  LngLat{-0.0015, 51.4777},
+			tolerance: 2e-4,
+		},
+		"nad27 to wgs84": {
+			from:      NAD27,
+			to:        WGS84,
+			lnglat:    LngLat{-79.982222, 40.446111},
+			expected:  LngLat{-79.982222, 40.446111},
+			tolerance: 2e-3,
+		},
+		"ed50 to nad27": {
+			from:      ED50,
+			to:        NAD27,
+			lnglat:    LngLat{2.3522, 48.8566},
+			expected:  LngLat{2.3522, 48.8566},
+			tolerance: 5e-3,
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDatumByName(t *testing.T) {
+	for _, name := range []string{"WGS_84", "wgs84", "WGS 84", "nad-27", "OSGB36"} {
+		if _, ok := DatumByName(name); !ok {
+			t.Errorf("datum %v, expected to be found", name)
+		}
+	}
+	if _, ok := DatumByName("unknown"); ok {
+		t.Errorf("datum unknown, expected not to be found")
+	}
+
+	RegisterDatum(Datum{Name: "Test Datum", Ellipsoid: GRS80Ellipsoid})
+	if d, ok := DatumByName("TEST_DATUM"); !ok || d.Name != "Test Datum" {
+		t.Errorf("datum, expected registered datum got %v", d)
+	}
+}
+
+func TestHelmert_ApplyInverse(t *testing.T) {
+	pt := WGS84Ellipsoid.ToECEF(LngLat{-1.5, 53.8}, 120)
+	for _, d := range []Datum{OSGB36, DHDN, NAD27} {
+		got := d.ToWGS84.ApplyInverse(d.ToWGS84.Apply(pt))
+		if math.Abs(got.X-pt.X) > 1e-6 || math.Abs(got.Y-pt.Y) > 1e-6 || math.Abs(got.Z-pt.Z) > 1e-6 {
+			t.Errorf("%v, expected %v got %v", d.Name, pt, got)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package coord
+
+import "github.com/gdey/errors"
+
+const (
+	// ErrInvalidCoordinate will be returned if a coordinate string could not be parsed
+	ErrInvalidCoordinate = errors.String("invalid coordinate string")
+	// ErrCoordinateOutOfRange will be returned if a parsed latitude or longitude is not in the acceptable range of values
+	ErrCoordinateOutOfRange = errors.String("coordinate out of range")
+)
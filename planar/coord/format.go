@@ -0,0 +1,125 @@
+package coord
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Notation is the way a latitude or longitude value is written.
+type Notation uint8
+
+const (
+	// NotationDecimal is signed decimal degrees: 40.446111, -79.982222
+	NotationDecimal Notation = iota
+	// NotationDecimalHemisphere is decimal degrees with a hemisphere: 40.446111°N 79.982222°W
+	NotationDecimalHemisphere
+	// NotationDDM is degrees and decimal minutes: 40°26.767'N 79°58.933'W
+	NotationDDM
+	// NotationDMS is degrees minutes and seconds: 40°26'46"N 79°58'56"W
+	NotationDMS
+)
+
+// defaultPrecision is the precision used by LngLat.Format for each notation;
+// all are around 0.1m.
+var defaultPrecision = map[Notation]int{
+	NotationDecimal:           6,
+	NotationDecimalHemisphere: 6,
+	NotationDDM:               4,
+	NotationDMS:               2,
+}
+
+// Formatter formats LngLat values as strings. The zero value formats the
+// values as "lat, lng" in decimal degrees with no fractional part.
+type Formatter struct {
+	Notation Notation
+	// Precision is the number of digits after the decimal point of the last
+	// value; the degrees, minutes or seconds depending on the Notation. A
+	// Precision of -1 uses the smallest number of digits needed to represent
+	// the value exactly.
+	Precision int
+	// LngFirst writes the longitude before the latitude.
+	LngFirst bool
+	// Separator is written between the two values. If empty ", " is used for
+	// NotationDecimal, and a space for the others.
+	Separator string
+}
+
+// Format returns the string representation of the value using the formatter.
+// The result can be parsed by ParseLngLat.
+func (f Formatter) Format(l LngLat) string {
+	sep := f.Separator
+	if sep == "" {
+		sep = " "
+		if f.Notation == NotationDecimal {
+			sep = ", "
+		}
+	}
+	lat, lng := f.value(l.Lat, 'N', 'S'), f.value(l.Lng, 'E', 'W')
+	if f.LngFirst {
+		return lng + sep + lat
+	}
+	return lat + sep + lng
+}
+
+// value returns the string representation of a single latitude or longitude value.
+func (f Formatter) value(v float64, pos, neg rune) string {
+	hemisphere := pos
+	if math.Signbit(v) {
+		hemisphere = neg
+	}
+	formatFloat := func(v float64) string { return strconv.FormatFloat(v, 'f', f.Precision, 64) }
+
+	var str strings.Builder
+	switch f.Notation {
+	default:
+		return formatFloat(v)
+
+	case NotationDecimalHemisphere:
+		str.WriteString(formatFloat(math.Abs(v)))
+		str.WriteRune('°')
+
+	case NotationDDM:
+		d, m := splitSexagesimal(math.Abs(v), 1, f.Precision)
+		str.WriteString(strconv.FormatInt(d[0], 10))
+		str.WriteRune('°')
+		str.WriteString(formatFloat(m))
+		str.WriteRune('\'')
+
+	case NotationDMS:
+		d, s := splitSexagesimal(math.Abs(v), 2, f.Precision)
+		str.WriteString(strconv.FormatInt(d[0], 10))
+		str.WriteRune('°')
+		str.WriteString(strconv.FormatInt(d[1], 10))
+		str.WriteRune('\'')
+		str.WriteString(formatFloat(s))
+		str.WriteRune('"')
+	}
+	str.WriteRune(hemisphere)
+	return str.String()
+}
+
+// splitSexagesimal splits the positive value into whole degrees (and minutes
+// if parts is 2), and the remaining minutes or seconds. The value is rounded
+// to the given precision first, so that the minutes or seconds never round
+// up to 60.
+func splitSexagesimal(v float64, parts int, precision int) (whole [2]int64, rest float64) {
+	scale := math.Pow(60, float64(parts))
+	rest = v * scale
+	if precision >= 0 {
+		rest = math.Round(rest*math.Pow10(precision)) / math.Pow10(precision)
+	}
+	for i := 0; i < parts; i++ {
+		n := math.Floor(rest / scale)
+		whole[i] = int64(n)
+		rest -= n * scale
+		scale /= 60
+	}
+	return whole, rest
+}
+
+// Format returns the string representation of the value in the given
+// notation, with the latitude first and a precision of about 0.1 meter.
+func (l LngLat) Format(n Notation) string {
+	return Formatter{Notation: n, Precision: defaultPrecision[n]}.Format(l)
+}
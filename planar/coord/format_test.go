@@ -0,0 +1,91 @@
+package coord
+
+import "testing"
+
+func TestFormatter_Format(t *testing.T) {
+	type tcase struct {
+		formatter Formatter
+		lnglat    LngLat
+		expected  string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := tc.formatter.Format(tc.lnglat)
+			if got != tc.expected {
+				t.Errorf("format, expected %v got %v", tc.expected, got)
+			}
+			if tc.formatter.Notation == NotationDecimal && tc.formatter.LngFirst {
+				// without hemispheres the order can not be detected
+				return
+			}
+			// the formatted string should parse back to the value
+			ll, err := ParseLngLat(got)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if got2 := tc.formatter.Format(ll); got2 != got {
+				t.Errorf("round trip, expected %v got %v", got, got2)
+			}
+		}
+	}
+
+	pittsburgh := LngLat{Lng: -79.982222, Lat: 40.446111}
+	tcases := map[string]tcase{
+		"zero value": {lnglat: pittsburgh, expected: "40, -80"},
+		"decimal": {
+			formatter: Formatter{Precision: 4},
+			lnglat:    pittsburgh,
+			expected:  "40.4461, -79.9822",
+		},
+		"decimal lng first": {
+			formatter: Formatter{Precision: -1, LngFirst: true, Separator: " "},
+			lnglat:    pittsburgh,
+			expected:  "-79.982222 40.446111",
+		},
+		"decimal hemisphere": {
+			formatter: Formatter{Notation: NotationDecimalHemisphere, Precision: 3},
+			lnglat:    pittsburgh,
+			expected:  "40.446°N 79.982°W",
+		},
+		"ddm": {
+			formatter: Formatter{Notation: NotationDDM, Precision: 3},
+			lnglat:    pittsburgh,
+			expected:  "40°26.767'N 79°58.933'W",
+		},
+		"dms": {
+			formatter: Formatter{Notation: NotationDMS},
+			lnglat:    pittsburgh,
+			expected:  `40°26'46"N 79°58'56"W`,
+		},
+		"dms separator": {
+			formatter: Formatter{Notation: NotationDMS, Precision: 1, Separator: ", "},
+			lnglat:    LngLat{Lng: 151.215306, Lat: -33.856806},
+			expected:  `33°51'24.5"S, 151°12'55.1"E`,
+		},
+		"dms rounds up": {
+			formatter: Formatter{Notation: NotationDMS},
+			lnglat:    LngLat{Lng: 10.999999, Lat: 0},
+			expected:  `0°0'0"N 11°0'0"E`,
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLngLat_Format(t *testing.T) {
+	pittsburgh := LngLat{Lng: -79.982222, Lat: 40.446111}
+	tcases := map[Notation]string{
+		NotationDecimal:           "40.446111, -79.982222",
+		NotationDecimalHemisphere: "40.446111°N 79.982222°W",
+		NotationDDM:               "40°26.7667'N 79°58.9333'W",
+		NotationDMS:               `40°26'46.00"N 79°58'56.00"W`,
+	}
+	for n, expected := range tcases {
+		if got := pittsburgh.Format(n); got != expected {
+			t.Errorf("format, expected %v got %v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package coord
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// axis is which of latitude or longitude a parsed value is for.
+type axis uint8
+
+const (
+	axisUnknown axis = iota
+	axisLat
+	axisLng
+)
+
+// unit is the unit of a parsed number; the position of the number in a
+// degree, minute, second triple.
+type unit int8
+
+const (
+	unitNone unit = iota - 1
+	unitDegree
+	unitMinute
+	unitSecond
+)
+
+type tokenKind uint8
+
+const (
+	tokenNumber tokenKind = iota
+	tokenUnit
+	tokenHemisphere
+	tokenSeparator
+)
+
+type token struct {
+	kind tokenKind
+	// value of the number
+	value float64
+	// the number was written with a sign
+	signed bool
+	// the number was written with a minus sign; used for -0
+	negative   bool
+	unit       unit
+	hemisphere rune
+}
+
+// hemispheres are the words that can be used for a hemisphere.
+var hemispheres = map[string]rune{
+	"N": 'N', "NORTH": 'N',
+	"S": 'S', "SOUTH": 'S',
+	"E": 'E', "EAST": 'E',
+	"W": 'W', "WEST": 'W',
+}
+
+// tokenize splits the coordinate string into numbers, unit symbols,
+// hemisphere letters and separators.
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	rs := []rune(s)
+	isNumber := func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' }
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			continue
+
+		case isNumber(r) || ((r == '-' || r == '+' || r == '−') && i+1 < len(rs) && isNumber(rs[i+1])):
+			tok := token{kind: tokenNumber}
+			if !isNumber(r) {
+				tok.signed, tok.negative = true, r != '+'
+				i++
+			}
+			start := i
+			for i < len(rs) && isNumber(rs[i]) {
+				i++
+			}
+			v, err := strconv.ParseFloat(string(rs[start:i]), 64)
+			if err != nil {
+				return nil, ErrInvalidCoordinate
+			}
+			i--
+			tok.value = v
+			if tok.negative {
+				tok.value = -v
+			}
+			tokens = append(tokens, tok)
+
+		case r == '°' || r == 'º' || r == '˚':
+			tokens = append(tokens, token{kind: tokenUnit, unit: unitDegree})
+
+		case r == '\'' || r == '′' || r == '’' || r == '‘':
+			// two single quotes are used for seconds
+			if i+1 < len(rs) && rs[i+1] == r {
+				i++
+				tokens = append(tokens, token{kind: tokenUnit, unit: unitSecond})
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenUnit, unit: unitMinute})
+
+		case r == '"' || r == '″' || r == '”' || r == '“':
+			tokens = append(tokens, token{kind: tokenUnit, unit: unitSecond})
+
+		case r == ',' || r == ';' || r == '/':
+			tokens = append(tokens, token{kind: tokenSeparator})
+
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(rs) && unicode.IsLetter(rs[i]) {
+				i++
+			}
+			h, ok := hemispheres[strings.ToUpper(string(rs[start:i]))]
+			if !ok {
+				return nil, ErrInvalidCoordinate
+			}
+			i--
+			tokens = append(tokens, token{kind: tokenHemisphere, hemisphere: h})
+
+		default:
+			return nil, ErrInvalidCoordinate
+		}
+	}
+	return tokens, nil
+}
+
+// component is one of the latitude or longitude parts of a coordinate string.
+type component struct {
+	numbers    []token
+	hemisphere rune
+}
+
+// value returns the value in decimal degrees, and the axis of the component
+// if it is known from the hemisphere.
+func (c component) value() (float64, axis, error) {
+	if len(c.numbers) == 0 || len(c.numbers) > 3 {
+		return 0, axisUnknown, ErrInvalidCoordinate
+	}
+	var v float64
+	scale := 1.0
+	for i, n := range c.numbers {
+		if n.unit != unitNone && n.unit != unit(i) {
+			return 0, axisUnknown, ErrInvalidCoordinate
+		}
+		// only the last value may have a fraction
+		if i < len(c.numbers)-1 && math.Trunc(n.value) != n.value {
+			return 0, axisUnknown, ErrInvalidCoordinate
+		}
+		if i > 0 && (n.signed || n.value >= 60) {
+			return 0, axisUnknown, ErrInvalidCoordinate
+		}
+		v += math.Abs(n.value) / scale
+		scale *= 60
+	}
+	negative := c.numbers[0].negative
+
+	var ax axis
+	switch c.hemisphere {
+	case 'N':
+		ax = axisLat
+	case 'S':
+		ax, negative = axisLat, !negative
+	case 'E':
+		ax = axisLng
+	case 'W':
+		ax, negative = axisLng, !negative
+	}
+	if c.hemisphere != 0 && c.numbers[0].signed {
+		// a sign and a hemisphere is ambiguous
+		return 0, axisUnknown, ErrInvalidCoordinate
+	}
+	if negative {
+		v = -v
+	}
+	return v, ax, nil
+}
+
+// components groups the tokens into the latitude and longitude parts. A
+// new part is started by a separator, a hemisphere after a part that already
+// has one, a signed number, or a number with a degree symbol.
+func components(tokens []token) ([]component, error) {
+	var (
+		parts []component
+		cur   component
+		// the last part was ended by a hemisphere suffix
+		suffixed bool
+	)
+	finish := func() {
+		parts = append(parts, cur)
+		cur = component{}
+	}
+	for _, tok := range tokens {
+		wasSuffixed := suffixed
+		suffixed = false
+		switch tok.kind {
+		case tokenSeparator:
+			switch {
+			case wasSuffixed:
+				// already ended: "40°N, 79°W"
+			case len(cur.numbers) == 0:
+				return nil, ErrInvalidCoordinate
+			default:
+				finish()
+			}
+
+		case tokenHemisphere:
+			switch {
+			case len(cur.numbers) == 0 && cur.hemisphere == 0:
+				// prefix
+				cur.hemisphere = tok.hemisphere
+			case len(cur.numbers) == 0:
+				return nil, ErrInvalidCoordinate
+			case cur.hemisphere == 0:
+				// suffix
+				cur.hemisphere = tok.hemisphere
+				finish()
+				suffixed = true
+			default:
+				// prefix of the next part
+				finish()
+				cur.hemisphere = tok.hemisphere
+			}
+
+		case tokenNumber:
+			tok.unit = unitNone
+			if tok.signed && len(cur.numbers) > 0 {
+				finish()
+			}
+			cur.numbers = append(cur.numbers, tok)
+
+		case tokenUnit:
+			last := len(cur.numbers) - 1
+			if last < 0 || cur.numbers[last].unit != unitNone {
+				return nil, ErrInvalidCoordinate
+			}
+			if tok.unit == unitDegree && last > 0 {
+				// the number starts the next part
+				n := cur.numbers[last]
+				cur.numbers = cur.numbers[:last]
+				finish()
+				cur.numbers = append(cur.numbers, n)
+				last = 0
+			}
+			cur.numbers[last].unit = tok.unit
+		}
+	}
+	if len(cur.numbers) > 0 || cur.hemisphere != 0 {
+		finish()
+	}
+
+	// Nothing tells us where the parts are split, so split the numbers in
+	// half; "40 26.767 79 58.933"
+	if len(parts) == 1 && parts[0].hemisphere == 0 && len(parts[0].numbers)%2 == 0 {
+		nums := parts[0].numbers
+		half := len(nums) / 2
+		parts = []component{{numbers: nums[:half]}, {numbers: nums[half:]}}
+	}
+	if len(parts) != 2 {
+		return nil, ErrInvalidCoordinate
+	}
+	return parts, nil
+}
+
+// ParseLngLat parses a human readable coordinate string into a LngLat value.
+// The latitude and longitude can each be given as signed decimal degrees,
+// degrees and decimal minutes, or degrees minutes and seconds; and may have
+// a hemisphere letter (N, S, E or W) as a prefix or suffix. All of the
+// following are accepted:
+//
+//	40°26'46"N 79°58'56"W
+//	N40 26.767 W79 58.933
+//	40.446111, -79.982222
+//	40.446111N 79.982222W
+//	79°58'56"W, 40°26'46"N
+//
+// The order is taken from the hemispheres if they are given. Otherwise the
+// latitude is expected to come first, as is common, unless the first value
+// can only be a longitude.
+//
+// Possible errors:
+//	 ErrInvalidCoordinate
+//	 ErrCoordinateOutOfRange
+func ParseLngLat(s string) (LngLat, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return LngLat{}, err
+	}
+	parts, err := components(tokens)
+	if err != nil {
+		return LngLat{}, err
+	}
+	first, firstAxis, err := parts[0].value()
+	if err != nil {
+		return LngLat{}, err
+	}
+	second, secondAxis, err := parts[1].value()
+	if err != nil {
+		return LngLat{}, err
+	}
+
+	switch {
+	case firstAxis != axisUnknown && firstAxis == secondAxis:
+		return LngLat{}, ErrInvalidCoordinate
+	case firstAxis == axisUnknown && secondAxis == axisUnknown:
+		firstAxis = axisLat
+		if math.Abs(first) > 90 && math.Abs(second) <= 90 {
+			firstAxis = axisLng
+		}
+	case firstAxis == axisUnknown && secondAxis == axisLat:
+		firstAxis = axisLng
+	case firstAxis == axisUnknown:
+		firstAxis = axisLat
+	}
+
+	ll := LngLat{Lat: first, Lng: second}
+	if firstAxis == axisLng {
+		ll = LngLat{Lng: first, Lat: second}
+	}
+	if math.Abs(ll.Lat) > 90 || math.Abs(ll.Lng) > 180 {
+		return LngLat{}, ErrCoordinateOutOfRange
+	}
+	return ll, nil
+}
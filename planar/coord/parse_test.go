@@ -0,0 +1,79 @@
+package coord
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestParseLngLat(t *testing.T) {
+	type tcase struct {
+		str      string
+		expected LngLat
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		tol, bitTol := tolerance(nil)
+		return func(t *testing.T) {
+			got, err := ParseLngLat(tc.str)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if !cmp.Float64(got.Lat, tc.expected.Lat, tol, bitTol) {
+				t.Errorf("lat, expected %v got %v", tc.expected.Lat, got.Lat)
+			}
+			if !cmp.Float64(got.Lng, tc.expected.Lng, tol, bitTol) {
+				t.Errorf("lng, expected %v got %v", tc.expected.Lng, got.Lng)
+			}
+		}
+	}
+
+	pittsburgh := LngLat{Lng: -79.982222, Lat: 40.446111}
+	tcases := map[string]tcase{
+		"dms":                       {str: `40°26'46"N 79°58'56"W`, expected: pittsburgh},
+		"dms no spaces":             {str: `40°26'46"N79°58'56"W`, expected: pittsburgh},
+		"dms primes":                {str: `40°26′46″N, 79°58′56″W`, expected: pittsburgh},
+		"dms two single quotes":     {str: `40°26'46''N 79°58'56''W`, expected: pittsburgh},
+		"dms lng first":             {str: `79°58'56"W 40°26'46"N`, expected: pittsburgh},
+		"dms hemisphere prefix":     {str: `N 40°26'46" W 79°58'56"`, expected: pittsburgh},
+		"dms signed":                {str: `40°26'46" -79°58'56"`, expected: pittsburgh},
+		"dms no symbols":            {str: `40 26 46 N 79 58 56 W`, expected: pittsburgh},
+		"dms numbers only":          {str: `40 26 46 -79 58 56`, expected: pittsburgh},
+		"ddm prefix":                {str: `N40 26.767 W79 58.933`, expected: LngLat{Lng: -79.982217, Lat: 40.446117}},
+		"ddm symbols":               {str: `40°26.767'N 79°58.933'W`, expected: LngLat{Lng: -79.982217, Lat: 40.446117}},
+		"ddm numbers only":          {str: `40 26.767 -79 58.933`, expected: LngLat{Lng: -79.982217, Lat: 40.446117}},
+		"ddm lower case":            {str: `n40 26.767 w79 58.933`, expected: LngLat{Lng: -79.982217, Lat: 40.446117}},
+		"decimal":                   {str: "40.446111, -79.982222", expected: pittsburgh},
+		"decimal space":             {str: "40.446111 -79.982222", expected: pittsburgh},
+		"decimal no separator sign": {str: "40.446111 79.982222", expected: LngLat{Lng: 79.982222, Lat: 40.446111}},
+		"decimal hemisphere":        {str: "40.446111°N 79.982222°W", expected: pittsburgh},
+		"decimal hemisphere words":  {str: "40.446111 north, 79.982222 west", expected: pittsburgh},
+		"decimal lng first":         {str: "79.982222W, 40.446111N", expected: pittsburgh},
+		"decimal detect order":      {str: "-120.5, 35.25", expected: LngLat{Lng: -120.5, Lat: 35.25}},
+		"decimal one hemisphere":    {str: "79.982222W, 40.446111", expected: pittsburgh},
+		"southern":                  {str: `33°51'24.5"S 151°12'55.1"E`, expected: LngLat{Lng: 151.215306, Lat: -33.856806}},
+		"negative zero":             {str: "-0 30, 10", expected: LngLat{Lng: 10, Lat: -0.5}},
+
+		"empty":                 {str: "", err: ErrInvalidCoordinate},
+		"one value":             {str: "40.446111", err: ErrInvalidCoordinate},
+		"three values":          {str: "1, 2, 3", err: ErrInvalidCoordinate},
+		"odd numbers":           {str: "40 26 79", err: ErrInvalidCoordinate},
+		"same axis":             {str: "40N 79N", err: ErrInvalidCoordinate},
+		"sign and hemisphere":   {str: "-40N 79W", err: ErrInvalidCoordinate},
+		"minutes over 60":       {str: "40 61 N 79 58 W", err: ErrInvalidCoordinate},
+		"fraction not last":     {str: "40.5 26 N 79 58 W", err: ErrInvalidCoordinate},
+		"units out of order":    {str: `40'26°N 79°58'W`, err: ErrInvalidCoordinate},
+		"unknown word":          {str: "40N 79 westish", err: ErrInvalidCoordinate},
+		"unknown symbol":        {str: "40N # 79W", err: ErrInvalidCoordinate},
+		"latitude out of range": {str: "95N 79W", err: ErrCoordinateOutOfRange},
+		"both out of range":     {str: "100, 200", err: ErrCoordinateOutOfRange},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
@@ -7,4 +7,6 @@ const (
 	ErrInvalidZone = errors.String("zone is invalid")
 	// ErrLatitudeOutOfRange will be returned if the latitude is not in the correct range of acceptable values
 	ErrLatitudeOutOfRange = errors.String("latitude out of range")
+	// ErrInvalidMGRS will be returned if a MGRS string could not be parsed
+	ErrInvalidMGRS = errors.String("invalid mgrs string")
 )
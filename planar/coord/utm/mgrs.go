@@ -0,0 +1,219 @@
+package utm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MGRS 100km square letters. I and O are not used.
+const (
+	// mgrsColumns are the column letters for UTM zones, by zone number mod 3.
+	mgrsColumns1 = "ABCDEFGH"
+	mgrsColumns2 = "JKLMNPQR"
+	mgrsColumns3 = "STUVWXYZ"
+	// mgrsRows are the row letters for UTM zones; even zones start at F.
+	mgrsRows = "ABCDEFGHJKLMNPQRSTUV"
+
+	// upsColumnsWest are the column letters for the A and Y zones, starting
+	// at 800km easting.
+	upsColumnsWest = "JKLPQRSTUXYZ"
+	// upsColumnsEast are the column letters for the B and Z zones, starting
+	// at 2000km easting.
+	upsColumnsEast = "ABCFGHJKLPQR"
+	// upsRows are the row letters for UPS zones, starting at 800km northing
+	// in the south and 1300km northing in the north.
+	upsRows = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+const (
+	x100km  = 100000
+	x2000km = 2000000
+)
+
+// mgrsColumns returns the column letters for the zone.
+func mgrsColumns(zone Zone) string {
+	switch zone.Number % 3 {
+	case 1:
+		return mgrsColumns1
+	case 2:
+		return mgrsColumns2
+	default:
+		return mgrsColumns3
+	}
+}
+
+// upsOrigin returns the column letters, and the easting and northing of the
+// first 100km square, for the UPS zone.
+func upsOrigin(zone Zone) (columns string, easting, northing float64) {
+	columns, easting = upsColumnsEast, 2000000
+	if zone.Letter == ZoneA || zone.Letter == ZoneY {
+		columns, easting = upsColumnsWest, 800000
+	}
+	northing = 800000
+	if zone.IsNorthern() {
+		northing = 1300000
+	}
+	return columns, easting, northing
+}
+
+// SquareID returns the MGRS 100km square identification letters for the
+// coordinate. Unlike the Digraph, which is computed from the longitude and
+// latitude, this is computed from the easting and northing.
+//
+// Possible errors:
+//	 ErrInvalidZone
+func (c Coord) SquareID() (Digraph, error) {
+	if c.Zone.IsUPS() {
+		columns, easting, northing := upsOrigin(c.Zone)
+		col := int(math.Floor((c.Easting - easting) / x100km))
+		row := int(math.Floor((c.Northing - northing) / x100km))
+		if col < 0 || col >= len(columns) || row < 0 || row >= len(upsRows) {
+			return Digraph{}, ErrLatitudeOutOfRange
+		}
+		return Digraph{rune(columns[col]), rune(upsRows[row])}, nil
+	}
+	if !c.Zone.IsValid() {
+		return Digraph{}, ErrInvalidZone
+	}
+	columns := mgrsColumns(c.Zone)
+	col := int(math.Floor(c.Easting/x100km)) - 1
+	if col < 0 || col >= len(columns) {
+		return Digraph{}, ErrInvalidZone
+	}
+	row := int(math.Floor(c.Northing / x100km))
+	if c.Zone.Number%2 == 0 {
+		row += 5
+	}
+	row %= len(mgrsRows)
+	if row < 0 {
+		row += len(mgrsRows)
+	}
+	return Digraph{rune(columns[col]), rune(mgrsRows[row])}, nil
+}
+
+// MGRS returns the Military Grid Reference System string for the coordinate,
+// for example "18SUJ2348306479". Precision is the number of digits used for
+// each of the easting and northing within the 100km square; from 0 (100km) to
+// 5 (1m). Precision values outside of that range are clamped. The digits are
+// truncated, not rounded, as required by MGRS. An empty string is returned if
+// the coordinate is not valid.
+func (c Coord) MGRS(precision int) string {
+	if precision < 0 {
+		precision = 0
+	}
+	if precision > 5 {
+		precision = 5
+	}
+	square, err := c.SquareID()
+	if err != nil {
+		return ""
+	}
+	div := math.Pow10(5 - precision)
+	easting := int(math.Mod(math.Floor(c.Easting), x100km) / div)
+	northing := int(math.Mod(math.Floor(c.Northing), x100km) / div)
+
+	var str strings.Builder
+	if !c.Zone.IsUPS() {
+		str.WriteString(strconv.Itoa(c.Zone.Number))
+	}
+	str.WriteString(c.Zone.Letter.String())
+	str.WriteString(square.String())
+	if precision > 0 {
+		fmt.Fprintf(&str, "%0*d%0*d", precision, easting, precision, northing)
+	}
+	return str.String()
+}
+
+// minNorthing returns a value below the smallest northing of the latitude band
+// of the UTM zone.
+func minNorthing(letter ZoneLetter) float64 {
+	// latitude of the southern edge of the band
+	idx := strings.IndexByte("CDEFGHJKLMNPQRSTUVWX", byte(letter))
+	lat := float64(-80 + 8*idx)
+	if letter.IsNorthern() {
+		// 110.5km is the shortest length of a degree of latitude.
+		return lat*110500 - x100km
+	}
+	// 111.7km is the longest length of a degree of latitude.
+	return 10000000 + lat*111700 - x100km
+}
+
+// ParseMGRS parses a Military Grid Reference System string, like
+// "18SUJ2348306479" or "18S UJ 23483 06479", into a coordinate. The easting
+// and northing are for the south west corner of the grid square the string
+// refers to. Polar (UPS) references, like "ZAH0000000000", return a UPS
+// coordinate. The returned coordinate's Digraph is set to the 100km square
+// identification letters.
+//
+// Possible errors:
+//	 ErrInvalidMGRS
+func ParseMGRS(s string) (Coord, error) {
+	s = strings.ToUpper(strings.Join(strings.Fields(s), ""))
+
+	var (
+		c   Coord
+		pos int
+	)
+	for pos < len(s) && pos < 2 && s[pos] >= '0' && s[pos] <= '9' {
+		pos++
+	}
+	if pos > 0 {
+		c.Zone.Number, _ = strconv.Atoi(s[:pos])
+	}
+	if len(s) < pos+3 {
+		return Coord{}, ErrInvalidMGRS
+	}
+	c.Zone.Letter = ZoneLetter(s[pos])
+	c.Digraph = Digraph{rune(s[pos+1]), rune(s[pos+2])}
+	digits := s[pos+3:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return Coord{}, ErrInvalidMGRS
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return Coord{}, ErrInvalidMGRS
+		}
+	}
+	precision := len(digits) / 2
+	var easting, northing float64
+	if precision > 0 {
+		e, _ := strconv.Atoi(digits[:precision])
+		n, _ := strconv.Atoi(digits[precision:])
+		scale := math.Pow10(5 - precision)
+		easting, northing = float64(e)*scale, float64(n)*scale
+	}
+
+	if c.Zone.IsUPS() {
+		columns, originEasting, originNorthing := upsOrigin(c.Zone)
+		col := strings.IndexRune(columns, c.Digraph[0])
+		row := strings.IndexRune(upsRows, c.Digraph[1])
+		if col < 0 || row < 0 {
+			return Coord{}, ErrInvalidMGRS
+		}
+		c.Easting = originEasting + float64(col)*x100km + easting
+		c.Northing = originNorthing + float64(row)*x100km + northing
+		return c, nil
+	}
+
+	if !c.Zone.IsValid() {
+		return Coord{}, ErrInvalidMGRS
+	}
+	col := strings.IndexRune(mgrsColumns(c.Zone), c.Digraph[0])
+	row := strings.IndexRune(mgrsRows, c.Digraph[1])
+	if col < 0 || row < 0 {
+		return Coord{}, ErrInvalidMGRS
+	}
+	if c.Zone.Number%2 == 0 {
+		row = (row - 5 + len(mgrsRows)) % len(mgrsRows)
+	}
+	c.Easting = float64(col+1)*x100km + easting
+	// The row letters repeat every 2000km, use the latitude band to
+	// figure out which cycle we are in.
+	c.Northing = float64(row)*x100km + northing
+	for min := minNorthing(c.Zone.Letter); c.Northing < min; {
+		c.Northing += x2000km
+	}
+	return c, nil
+}
@@ -0,0 +1,138 @@
+package utm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom/planar/coord"
+)
+
+var washington = coord.LngLat{Lng: -77.0353, Lat: 38.8895}
+
+func TestCoord_MGRS(t *testing.T) {
+	type tcase struct {
+		lnglat    coord.LngLat
+		precision int
+		expected  string
+	}
+
+	wgs84 := getEllipsoidByName("WGS_84")
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			c, err := FromLngLat(tc.lnglat, wgs84)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if got := c.MGRS(tc.precision); got != tc.expected {
+				t.Errorf("mgrs, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"washington":           {lnglat: washington, precision: 5, expected: "18SUJ2347806483"},
+		"washington 1km":       {lnglat: washington, precision: 2, expected: "18SUJ2306"},
+		"washington 100km":     {lnglat: washington, precision: 0, expected: "18SUJ"},
+		"washington clamped":   {lnglat: washington, precision: 9, expected: "18SUJ2347806483"},
+		"north pole":           {lnglat: coord.LngLat{Lng: 0, Lat: 90}, precision: 5, expected: "ZAH0000000000"},
+		"south pole":           {lnglat: coord.LngLat{Lng: 0, Lat: -90}, precision: 5, expected: "BAN0000000000"},
+		"north pole west side": {lnglat: coord.LngLat{Lng: -1, Lat: 89}, precision: 0, expected: "YZF"},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestParseMGRS(t *testing.T) {
+	type tcase struct {
+		mgrs     string
+		expected Coord
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := ParseMGRS(tc.mgrs)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got != tc.expected {
+				t.Errorf("coord, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	dc := Coord{
+		Easting:  323478,
+		Northing: 4306483,
+		Zone:     Zone{Number: 18, Letter: ZoneS},
+		Digraph:  Digraph{'U', 'J'},
+	}
+	tcases := map[string]tcase{
+		"washington":  {mgrs: "18SUJ2347806483", expected: dc},
+		"with spaces": {mgrs: "18S UJ 23478 06483", expected: dc},
+		"lower case":  {mgrs: "18suj2347806483", expected: dc},
+		"1km": {
+			mgrs: "18SUJ2306",
+			expected: Coord{
+				Easting:  323000,
+				Northing: 4306000,
+				Zone:     Zone{Number: 18, Letter: ZoneS},
+				Digraph:  Digraph{'U', 'J'},
+			},
+		},
+		"southern hemisphere": {
+			mgrs: "23LPH6776769581",
+			expected: Coord{
+				Easting:  667767,
+				Northing: 8769581,
+				Zone:     Zone{Number: 23, Letter: ZoneL},
+				Digraph:  Digraph{'P', 'H'},
+			},
+		},
+		"north pole": {
+			mgrs:     "ZAH0000000000",
+			expected: Coord{Easting: 2000000, Northing: 2000000, Zone: Zone{Letter: ZoneZ}, Digraph: Digraph{'A', 'H'}},
+		},
+		"south pole": {
+			mgrs:     "BAN0000000000",
+			expected: Coord{Easting: 2000000, Northing: 2000000, Zone: Zone{Letter: ZoneB}, Digraph: Digraph{'A', 'N'}},
+		},
+		"odd digits":          {mgrs: "18SUJ234780648", err: ErrInvalidMGRS},
+		"too short":           {mgrs: "18S", err: ErrInvalidMGRS},
+		"bad column":          {mgrs: "18SAJ2347806483", err: ErrInvalidMGRS},
+		"bad zone":            {mgrs: "61SUJ2347806483", err: ErrInvalidMGRS},
+		"letters for numbers": {mgrs: "18SUJ23478AAAAA", err: ErrInvalidMGRS},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMGRSRoundTrip(t *testing.T) {
+	wgs84 := getEllipsoidByName("WGS_84")
+	points := []coord.LngLat{
+		{Lng: -77.0365, Lat: 38.8977},
+		{Lng: 151.2153, Lat: -33.8568},
+		{Lng: 10.7522, Lat: 59.9139},
+		{Lng: -68.3, Lat: -54.8},
+		{Lng: 45, Lat: 86},
+		{Lng: -135, Lat: -85},
+	}
+	for _, pt := range points {
+		c, err := FromLngLat(pt, wgs84)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		mgrs := c.MGRS(5)
+		parsed, err := ParseMGRS(mgrs)
+		if err != nil {
+			t.Fatalf("%v: error, expected nil got %v", mgrs, err)
+		}
+		if math.Abs(parsed.Easting-math.Floor(c.Easting)) > 1e-6 || math.Abs(parsed.Northing-math.Floor(c.Northing)) > 1e-6 {
+			t.Errorf("%v: coord, expected %v got %v", mgrs, c, parsed)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package utm
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom/planar/coord"
+)
+
+const (
+	upsK0            = 0.994   // scale factor at the pole for UPS
+	upsFalseEasting  = 2000000 // false easting for UPS
+	upsFalseNorthing = 2000000 // false northing for UPS
+)
+
+// upsC returns the constant used to go from the conformal latitude to the
+// distance from the pole, for the given eccentricity (not squared).
+func upsC(e float64) float64 {
+	return math.Sqrt(math.Pow(1+e, 1+e) * math.Pow(1-e, 1-e))
+}
+
+// FromLngLatUPS returns a new Universal Polar Stereographic coordinate for
+// the provided longitude and latitude values. UPS is used for the polar
+// regions not covered by UTM, north of 84°N and south of 80°S, but any
+// latitude can be converted. The coordinate's zone will have a number of 0,
+// and a letter of A or B for the south pole, and Y or Z for the north pole.
+//
+// Possible errors:
+//	 ErrLatitudeOutOfRange
+func FromLngLatUPS(lnglat coord.LngLat, ellips coord.Ellipsoid) (Coord, error) {
+	if lnglat.Lat < -90 || lnglat.Lat > 90 {
+		return Coord{}, ErrLatitudeOutOfRange
+	}
+	lnglat = lnglat.NormalizeLng()
+	north := lnglat.Lat >= 0
+
+	e := math.Sqrt(ellips.Eccentricity)
+	lat, lng := lnglat.LatInRadians(), lnglat.LngInRadians()
+	if !north {
+		lat = -lat
+	}
+	sin := math.Sin(lat)
+	t := math.Tan(math.Pi/4-lat/2) / math.Pow((1-e*sin)/(1+e*sin), e/2)
+	rho := 2 * ellips.Radius * upsK0 * t / upsC(e)
+
+	dx, dy := rho*math.Sin(lng), rho*math.Cos(lng)
+	var zone Zone
+	switch {
+	case north && lnglat.Lng < 0:
+		zone.Letter = ZoneY
+	case north:
+		zone.Letter = ZoneZ
+	case lnglat.Lng < 0:
+		zone.Letter = ZoneA
+	default:
+		zone.Letter = ZoneB
+	}
+	if north {
+		dy = -dy
+	}
+	return Coord{
+		Easting:  upsFalseEasting + dx,
+		Northing: upsFalseNorthing + dy,
+		Zone:     zone,
+	}, nil
+}
+
+// upsToLngLat transforms the UPS coordinate to it's Lat Lng representation.
+func (c Coord) upsToLngLat(ellips coord.Ellipsoid) coord.LngLat {
+	e := math.Sqrt(ellips.Eccentricity)
+	dx, dy := c.Easting-upsFalseEasting, c.Northing-upsFalseNorthing
+	north := c.Zone.IsNorthern()
+	if north {
+		dy = -dy
+	}
+	rho := math.Hypot(dx, dy)
+	t := rho * upsC(e) / (2 * ellips.Radius * upsK0)
+
+	// iterate to get the geodetic latitude from the conformal latitude.
+	lat := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 20; i++ {
+		sin := math.Sin(lat)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-e*sin)/(1+e*sin), e/2))
+		if math.Abs(next-lat) < 1e-14 {
+			lat = next
+			break
+		}
+		lat = next
+	}
+	lng := math.Atan2(dx, dy)
+	if !north {
+		lat = -lat
+	}
+	return coord.LngLat{
+		Lng: coord.ToDegree(lng),
+		Lat: coord.ToDegree(lat),
+	}
+}
@@ -22,6 +22,10 @@ const (
 
 // UTM Zone Letters
 const (
+	// UPS zone letters for the south pole, west and east of the prime meridian
+	ZoneA ZoneLetter = 'A'
+	ZoneB ZoneLetter = 'B'
+
 	ZoneC ZoneLetter = 'C'
 	ZoneD ZoneLetter = 'D'
 	ZoneE ZoneLetter = 'E'
@@ -43,6 +47,10 @@ const (
 	ZoneV ZoneLetter = 'V'
 	ZoneW ZoneLetter = 'W'
 	ZoneX ZoneLetter = 'X'
+
+	// UPS zone letters for the north pole, west and east of the prime meridian
+	ZoneY ZoneLetter = 'Y'
+	ZoneZ ZoneLetter = 'Z'
 )
 
 // ZoneLetter describes the UTM zone letter
@@ -57,6 +65,10 @@ func (zl ZoneLetter) IsNorthern() bool { return zl >= 'N' }
 // IsValid will run validity check on the zone letter and number
 func (zl ZoneLetter) IsValid() bool { return zl >= 'C' && zl <= 'X' && zl != 'O' }
 
+// IsUPS returns if the zone letter is one of the Universal Polar
+// Stereographic zone letters; A, B, Y or Z.
+func (zl ZoneLetter) IsUPS() bool { return zl == ZoneA || zl == ZoneB || zl == ZoneY || zl == ZoneZ }
+
 // quick lookup table for central meridian for each zone
 // this can be calculated using the formula:
 //
@@ -175,7 +187,12 @@ type Zone struct {
 }
 
 // String implements the stringer interface
-func (z Zone) String() string { return fmt.Sprintf("%v%v", z.Number, z.Letter) }
+func (z Zone) String() string {
+	if z.IsUPS() {
+		return z.Letter.String()
+	}
+	return fmt.Sprintf("%v%v", z.Number, z.Letter)
+}
 
 // IsNorthern returns if the Zone is in the northern hemisphere
 func (z Zone) IsNorthern() bool { return z.Letter.IsNorthern() }
@@ -183,6 +200,10 @@ func (z Zone) IsNorthern() bool { return z.Letter.IsNorthern() }
 // IsValid will run validity check on the zone letter and number
 func (z Zone) IsValid() bool { return z.Letter.IsValid() && z.Number >= 1 && z.Number <= 60 }
 
+// IsUPS returns if the zone is a Universal Polar Stereographic zone, used for
+// the polar regions. UPS zones have a zone number of 0.
+func (z Zone) IsUPS() bool { return z.Letter.IsUPS() && z.Number == 0 }
+
 // ZoneNumberFromLngLat will get the zone number for the given LngLat value.
 //
 // The returned value will be from 1-60.
@@ -194,7 +215,7 @@ func (z Zone) IsValid() bool { return z.Letter.IsValid() && z.Number >= 1 && z.N
 func ZoneNumberFromLngLat(lnglat coord.LngLat) int {
 	lng, lat := lnglat.Lng, lnglat.Lat
 	if (lat > 84.0 && lat < 90.0) || // North Pole
-		(lat > -80.0 && lat < -90.0) { // South Pole
+		(lat < -80.0 && lat > -90.0) { // South Pole
 		return 0
 	}
 
@@ -429,8 +450,13 @@ func fromLngLat(lnglat coord.LngLat, zone Zone, ellips coord.Ellipsoid) Coord {
 }
 
 // FromLngLat returns a new utm coordinate based on the provided longitude and latitude values.
+// For latitudes north of 84°N or south of 80°S, a Universal Polar Stereographic
+// coordinate is returned instead; see FromLngLatUPS.
 func FromLngLat(lnglat coord.LngLat, ellips coord.Ellipsoid) (Coord, error) {
 	zone, err := NewZone(lnglat)
+	if err == ErrLatitudeOutOfRange && lnglat.Lat >= -90 && lnglat.Lat <= 90 {
+		return FromLngLatUPS(lnglat, ellips)
+	}
 	if err != nil {
 		return Coord{}, err
 	}
@@ -440,6 +466,10 @@ func FromLngLat(lnglat coord.LngLat, ellips coord.Ellipsoid) (Coord, error) {
 // ToLngLat transforms the utm Coord to it's Lat Lng representation based on the given datum
 func (c Coord) ToLngLat(ellips coord.Ellipsoid) (coord.LngLat, error) {
 
+	if c.Zone.IsUPS() {
+		return c.upsToLngLat(ellips), nil
+	}
+
 	if !c.Zone.IsValid() {
 		return coord.LngLat{}, fmt.Errorf("invalid zone")
 	}
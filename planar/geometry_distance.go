@@ -0,0 +1,290 @@
+package planar
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/rtreego"
+)
+
+// ErrEmptyGeometry is returned when a geometry has no points to measure a
+// distance to.
+var ErrEmptyGeometry = errors.New("planar: empty geometry")
+
+// components holds the parts of a geometry needed to compute distances.
+// Points are stored as zero length segments.
+type components struct {
+	segs []geom.Line
+	// polygons are used to determine if something is inside of an area.
+	polygons [][][][2]float64
+}
+
+func (c *components) addPoints(pts ...[2]float64) {
+	for _, pt := range pts {
+		c.segs = append(c.segs, geom.Line{pt, pt})
+	}
+}
+
+func (c *components) addLineString(pts [][2]float64, closed bool) {
+	if len(pts) == 1 {
+		c.addPoints(pts[0])
+		return
+	}
+	for i := 1; i < len(pts); i++ {
+		c.segs = append(c.segs, geom.Line{pts[i-1], pts[i]})
+	}
+	if closed && len(pts) > 2 && pts[0] != pts[len(pts)-1] {
+		c.segs = append(c.segs, geom.Line{pts[len(pts)-1], pts[0]})
+	}
+}
+
+func (c *components) add(g geom.Geometry) error {
+	switch gg := g.(type) {
+
+	default:
+		return geom.ErrUnknownGeometry{Geom: g}
+
+	case geom.Extent:
+		return c.add(gg.AsPolygon())
+
+	case *geom.Extent:
+		if gg != nil {
+			return c.add(gg.AsPolygon())
+		}
+
+	case geom.Pointer:
+		c.addPoints(gg.XY())
+
+	case geom.MultiPointer:
+		c.addPoints(gg.Points()...)
+
+	case geom.LineStringer:
+		c.addLineString(gg.Vertices(), false)
+
+	case geom.MultiLineStringer:
+		for _, ls := range gg.LineStrings() {
+			c.addLineString(ls, false)
+		}
+
+	case geom.Polygoner:
+		rings := gg.LinearRings()
+		for _, ring := range rings {
+			c.addLineString(ring, true)
+		}
+		if len(rings) > 0 && len(rings[0]) > 2 {
+			c.polygons = append(c.polygons, rings)
+		}
+
+	case geom.MultiPolygoner:
+		for _, p := range gg.Polygons() {
+			if err := c.add(geom.Polygon(p)); err != nil {
+				return err
+			}
+		}
+
+	case geom.Collectioner:
+		for _, child := range gg.Geometries() {
+			if err := c.add(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ringContainsPoint uses the crossing number to determine if the point is
+// inside of the ring. Points on the ring may be reported as either. This is
+// a lighter version of intersect.Ring.ContainsPoint, which can not be used
+// here as the intersect package imports planar.
+func ringContainsPoint(ring [][2]float64, pt [2]float64) (inside bool) {
+	j := len(ring) - 1
+	for i := range ring {
+		a, b := ring[i], ring[j]
+		if (a[1] > pt[1]) != (b[1] > pt[1]) &&
+			pt[0] < (b[0]-a[0])*(pt[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
+
+// polygonContainsPoint returns if the point is inside the polygon, and not
+// inside one of its holes.
+func polygonContainsPoint(plyg [][][2]float64, pt [2]float64) bool {
+	if len(plyg) == 0 || !ringContainsPoint(plyg[0], pt) {
+		return false
+	}
+	for _, hole := range plyg[1:] {
+		if ringContainsPoint(hole, pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// closestPointOnSegment returns the point on the segment closest to pt.
+func closestPointOnSegment(seg geom.Line, pt [2]float64) [2]float64 {
+	dx, dy := seg[1][0]-seg[0][0], seg[1][1]-seg[0][1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return seg[0]
+	}
+	t := ((pt[0]-seg[0][0])*dx + (pt[1]-seg[0][1])*dy) / l2
+	t = math.Max(0, math.Min(1, t))
+	return [2]float64{seg[0][0] + t*dx, seg[0][1] + t*dy}
+}
+
+func dist2(a, b [2]float64) float64 {
+	dx, dy := a[0]-b[0], a[1]-b[1]
+	return dx*dx + dy*dy
+}
+
+// closestPointsSegments returns the closest points between the two segments,
+// and the distance squared between them.
+func closestPointsSegments(s1, s2 geom.Line) (p1, p2 [2]float64, d2 float64) {
+	if s1[0] != s1[1] && s2[0] != s2[1] {
+		if pt, ok := SegmentIntersect(s1, s2); ok {
+			return pt, pt, 0
+		}
+	}
+	d2 = math.Inf(1)
+	for _, pt := range s1 {
+		cpt := closestPointOnSegment(s2, pt)
+		if d := dist2(pt, cpt); d < d2 {
+			p1, p2, d2 = pt, cpt, d
+		}
+	}
+	for _, pt := range s2 {
+		cpt := closestPointOnSegment(s1, pt)
+		if d := dist2(pt, cpt); d < d2 {
+			p1, p2, d2 = cpt, pt, d
+		}
+	}
+	return p1, p2, d2
+}
+
+// containedPoint returns a point of b that is inside one of the polygons of a.
+func containedPoint(a, b *components) ([2]float64, bool) {
+	for _, plyg := range a.polygons {
+		ext := geom.NewExtent(plyg[0]...)
+		for _, seg := range b.segs {
+			if ext.ContainsPoint(seg[0]) && polygonContainsPoint(plyg, seg[0]) {
+				return seg[0], true
+			}
+		}
+	}
+	return [2]float64{}, false
+}
+
+type distSegRect struct {
+	seg  geom.Line
+	rect *rtreego.Rect
+}
+
+func (sr *distSegRect) Bounds() *rtreego.Rect { return sr.rect }
+
+// segRect returns a rect for the segment expanded by the given amount.
+func segRect(seg geom.Line, by float64) *rtreego.Rect {
+	minx, maxx := math.Min(seg[0][0], seg[1][0])-by, math.Max(seg[0][0], seg[1][0])+by
+	miny, maxy := math.Min(seg[0][1], seg[1][1])-by, math.Max(seg[0][1], seg[1][1])+by
+	// lengths have to be positive
+	const smallep = 1e-9
+	rect, err := rtreego.NewRect(rtreego.Point{minx, miny}, []float64{maxx - minx + smallep, maxy - miny + smallep})
+	if err != nil {
+		panic("Assumption broken:" + err.Error())
+	}
+	return rect
+}
+
+// bruteForceCutoff is the number of segment pairs below which we don't
+// bother building an index.
+const bruteForceCutoff = 1024
+
+// nearestPoints returns the nearest points between the components of a and
+// b, and the distance between them.
+func nearestPoints(a, b *components) (pa, pb [2]float64, distance float64) {
+	if pt, ok := containedPoint(a, b); ok {
+		return pt, pt, 0
+	}
+	if pt, ok := containedPoint(b, a); ok {
+		return pt, pt, 0
+	}
+
+	best := math.Inf(1)
+	check := func(s1, s2 geom.Line) {
+		p1, p2, d2 := closestPointsSegments(s1, s2)
+		if d2 < best {
+			pa, pb, best = p1, p2, d2
+		}
+	}
+
+	if len(a.segs)*len(b.segs) <= bruteForceCutoff {
+		for _, s1 := range a.segs {
+			for _, s2 := range b.segs {
+				check(s1, s2)
+			}
+		}
+		return pa, pb, math.Sqrt(best)
+	}
+
+	// Index the segments of b and only look at the segments that are within
+	// the current best distance of a segment in a.
+	rects := make([]rtreego.Spatial, len(b.segs))
+	for i := range b.segs {
+		rects[i] = &distSegRect{seg: b.segs[i], rect: segRect(b.segs[i], 0)}
+	}
+	tree := rtreego.NewTree(2, 25, 50, rects...)
+	// seed the best distance.
+	check(a.segs[0], b.segs[0])
+	for _, s1 := range a.segs {
+		if best == 0 {
+			break
+		}
+		for _, r := range tree.SearchIntersect(segRect(s1, math.Sqrt(best))) {
+			if sr, ok := r.(*distSegRect); ok {
+				check(s1, sr.seg)
+			}
+		}
+	}
+	return pa, pb, math.Sqrt(best)
+}
+
+func componentsFor(a, b geom.Geometry) (ca, cb *components, err error) {
+	ca, cb = new(components), new(components)
+	if err = ca.add(a); err != nil {
+		return nil, nil, err
+	}
+	if err = cb.add(b); err != nil {
+		return nil, nil, err
+	}
+	if len(ca.segs) == 0 || len(cb.segs) == 0 {
+		return nil, nil, ErrEmptyGeometry
+	}
+	return ca, cb, nil
+}
+
+// Distance returns the minimum euclidean distance between the two geometries.
+// Points inside of a polygon (and not in one of its holes) are at a distance
+// of zero from the polygon. Extents are treated as polygons.
+func Distance(a, b geom.Geometry) (float64, error) {
+	ca, cb, err := componentsFor(a, b)
+	if err != nil {
+		return 0, err
+	}
+	_, _, distance := nearestPoints(ca, cb)
+	return distance, nil
+}
+
+// NearestPoints returns the point on a that is closest to b, and the point on b
+// that is closest to a. If the geometries intersect, the two points will be the
+// same point, at one of the places they intersect.
+func NearestPoints(a, b geom.Geometry) (pa, pb geom.Point, err error) {
+	ca, cb, err := componentsFor(a, b)
+	if err != nil {
+		return pa, pb, err
+	}
+	p1, p2, _ := nearestPoints(ca, cb)
+	return geom.Point(p1), geom.Point(p2), nil
+}
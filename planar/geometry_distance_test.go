@@ -0,0 +1,105 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestDistance(t *testing.T) {
+	type tcase struct {
+		a, b     geom.Geometry
+		distance float64
+		pa, pb   geom.Point
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			distance, err := Distance(tc.a, tc.b)
+			if err != tc.err {
+				t.Fatalf("error, expected %v, got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if math.Abs(distance-tc.distance) > 1e-9 {
+				t.Errorf("distance, expected %v, got %v", tc.distance, distance)
+			}
+			pa, pb, err := NearestPoints(tc.a, tc.b)
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if pa != tc.pa || pb != tc.pb {
+				t.Errorf("nearest points, expected %v %v, got %v %v", tc.pa, tc.pb, pa, pb)
+			}
+		}
+	}
+
+	square := geom.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		{{4, 4}, {6, 4}, {6, 6}, {4, 6}},
+	}
+
+	// a long line made of many segments, to exercise the index.
+	var long geom.LineString
+	for i := 0; i <= 300; i++ {
+		long = append(long, [2]float64{float64(i), 20})
+	}
+
+	tests := map[string]tcase{
+		"point point": {
+			a: geom.Point{0, 0}, b: geom.Point{3, 4},
+			distance: 5, pa: geom.Point{0, 0}, pb: geom.Point{3, 4},
+		},
+		"point line": {
+			a: geom.Point{5, 5}, b: geom.LineString{{0, 0}, {10, 0}},
+			distance: 5, pa: geom.Point{5, 5}, pb: geom.Point{5, 0},
+		},
+		"crossing lines": {
+			a: geom.LineString{{0, 0}, {10, 10}}, b: geom.Line{{0, 10}, {10, 0}},
+			distance: 0, pa: geom.Point{5, 5}, pb: geom.Point{5, 5},
+		},
+		"point in polygon": {
+			a: geom.Point{2, 2}, b: square,
+			distance: 0, pa: geom.Point{2, 2}, pb: geom.Point{2, 2},
+		},
+		"point in hole": {
+			a: geom.Point{5, 5.5}, b: square,
+			distance: 0.5, pa: geom.Point{5, 5.5}, pb: geom.Point{5, 6},
+		},
+		"polygon multipoint": {
+			a: square, b: geom.MultiPoint{{13, 14}, {12, 5}},
+			distance: 2, pa: geom.Point{10, 5}, pb: geom.Point{12, 5},
+		},
+		"collection": {
+			a: geom.Collection{geom.Point{100, 100}, geom.LineString{{0, 12}, {5, 12}}},
+			b: square,
+			distance: 2, pa: geom.Point{0, 12}, pb: geom.Point{0, 10},
+		},
+		"indexed": {
+			a: long, b: geom.LineString{{50.5, 0}, {50.5, 15}, {60, 15}, {60, 0}, {70, 0}, {70, 18}},
+			distance: 2, pa: geom.Point{70, 20}, pb: geom.Point{70, 18},
+		},
+		"point in extent": {
+			a: geom.Point{2, 3}, b: geom.Extent{0, 0, 10, 10},
+			distance: 0, pa: geom.Point{2, 3}, pb: geom.Point{2, 3},
+		},
+		"extent pointer line": {
+			a: geom.NewExtent([2]float64{0, 0}, [2]float64{10, 10}), b: geom.LineString{{13, 4}, {13, 8}},
+			distance: 3, pa: geom.Point{10, 4}, pb: geom.Point{13, 4},
+		},
+		"nil extent": {
+			a: (*geom.Extent)(nil), b: square,
+			err: ErrEmptyGeometry,
+		},
+		"empty": {
+			a: geom.MultiPoint{}, b: square,
+			err: ErrEmptyGeometry,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
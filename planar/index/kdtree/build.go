@@ -0,0 +1,85 @@
+package kdtree
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// ErrPayloadCount is returned by BuildWithPayloads if the number of payloads does not match the
+// number of points.
+var ErrPayloadCount = errors.New("number of payloads does not match the number of points")
+
+/*
+Build creates a balanced kd-tree from the points. At each level the points are split at the
+median along the dimension for that level, so the depth of the tree is about log2(n); unlike
+Insert, where the depth depends on the order the points are inserted.
+
+Duplicate points are stored on a single node. As with Insert, the nodes have no payloads.
+Building a tree of n points takes O(n log²(n)) time.
+*/
+func Build(points []geom.Pointer) *KdTree {
+	return &KdTree{root: build(mergeDuplicates(points, nil), 0)}
+}
+
+/*
+BuildWithPayloads creates a balanced kd-tree from the points, as Build does. The payload at the
+same index as a point is added to the point's node.
+*/
+func BuildWithPayloads(points []geom.Pointer, payloads []interface{}) (*KdTree, error) {
+	if len(points) != len(payloads) {
+		return nil, ErrPayloadCount
+	}
+	return &KdTree{root: build(mergeDuplicates(points, payloads), 0)}, nil
+}
+
+// mergeDuplicates returns a node for each distinct point, with the payloads of the point, in the
+// order they were given, added to it. If payloads is nil the nodes have no payloads.
+func mergeDuplicates(points []geom.Pointer, payloads []interface{}) []*KdNode {
+	idx := make([]int, len(points))
+	for i := range idx {
+		idx[i] = i
+	}
+	// merge the duplicate points, keeping the payloads in the order they were given.
+	sort.SliceStable(idx, func(i, j int) bool {
+		a, b := points[idx[i]].XY(), points[idx[j]].XY()
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		return a[1] < b[1]
+	})
+	var nodes []*KdNode
+	for _, i := range idx {
+		n := len(nodes)
+		if n == 0 || nodes[n-1].p.XY() != points[i].XY() {
+			nodes = append(nodes, NewKdNode(points[i]))
+			n++
+		}
+		if payloads != nil {
+			nodes[n-1].payloads = append(nodes[n-1].payloads, payloads[i])
+		}
+	}
+	return nodes
+}
+
+// build links the nodes into a balanced tree, split on the given dimension, and returns the root.
+func build(nodes []*KdNode, d int) *KdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].p.XY()[d] < nodes[j].p.XY()[d] })
+
+	// Points with the same value as the median have to go to the right, so move the median to
+	// the first of them.
+	m := len(nodes) / 2
+	for m > 0 && nodes[m-1].p.XY()[d] == nodes[m].p.XY()[d] {
+		m--
+	}
+
+	node := nodes[m]
+	node.left = build(nodes[:m], d^1)
+	node.right = build(nodes[m+1:], d^1)
+	node.updateBBox()
+	return node
+}
@@ -0,0 +1,153 @@
+package kdtree
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+// depth returns the depth of the subtree.
+func depth(node *KdNode) int {
+	if node == nil {
+		return 0
+	}
+	l, r := depth(node.left), depth(node.right)
+	if l > r {
+		return l + 1
+	}
+	return r + 1
+}
+
+// checkNode verifies the kd-tree invariants and the bboxes of the subtree, and returns the number
+// of nodes in it.
+func checkNode(t *testing.T, node *KdNode, d int) int {
+	t.Helper()
+	if node == nil {
+		return 0
+	}
+	bbox := geom.NewExtent(node.p.XY())
+	var walk func(n *KdNode, left bool)
+	walk = func(n *KdNode, left bool) {
+		if n == nil {
+			return
+		}
+		bbox.AddPoints(n.p.XY())
+		if left && n.p.XY()[d] >= node.p.XY()[d] {
+			t.Errorf("left point %v, expected < %v in dimension %v", n.p, node.p, d)
+		}
+		if !left && n.p.XY()[d] < node.p.XY()[d] {
+			t.Errorf("right point %v, expected >= %v in dimension %v", n.p, node.p, d)
+		}
+		walk(n.left, left)
+		walk(n.right, left)
+	}
+	walk(node.left, true)
+	walk(node.right, false)
+	if *bbox != node.bbox {
+		t.Errorf("bbox, expected %v got %v", *bbox, node.bbox)
+	}
+	return 1 + checkNode(t, node.left, d^1) + checkNode(t, node.right, d^1)
+}
+
+func TestBuild(t *testing.T) {
+	type tcase struct {
+		points   []geom.Pointer
+		payloads []interface{}
+		eJSON    string
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		kdt, err := BuildWithPayloads(tc.points, tc.payloads)
+		if err != tc.err {
+			t.Fatalf("error, expected %v got %v", tc.err, err)
+		}
+		if err != nil {
+			return
+		}
+		checkNode(t, kdt.root, 0)
+
+		gJSON, err := json.Marshal(kdt.root)
+		if err != nil {
+			t.Fatalf("converting to json error, expected nil, got %v", err)
+		}
+		if tc.eJSON != string(gJSON) {
+			t.Errorf("build, expected %v got %v", tc.eJSON, string(gJSON))
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {eJSON: "null"},
+		"balanced": {
+			points:   []geom.Pointer{geom.Point{0, 0}, geom.Point{1, 0}, geom.Point{2, 0}, geom.Point{3, 0}, geom.Point{4, 0}},
+			payloads: []interface{}{"a", "b", "c", "d", "e"},
+			eJSON:    `{"P":[2,0],"Payloads":["c"],"Left":{"P":[0,0],"Payloads":["a"],"Right":{"P":[1,0],"Payloads":["b"]}},"Right":{"P":[3,0],"Payloads":["d"],"Right":{"P":[4,0],"Payloads":["e"]}}}`,
+		},
+		"duplicates": {
+			points:   []geom.Pointer{geom.Point{1, 1}, geom.Point{0, 0}, geom.Point{1, 1}, geom.Point{2, 2}, geom.Point{1, 1}},
+			payloads: []interface{}{1, 2, 3, 4, 5},
+			eJSON:    `{"P":[1,1],"Payloads":[1,3,5],"Left":{"P":[0,0],"Payloads":[2]},"Right":{"P":[2,2],"Payloads":[4]}}`,
+		},
+		"same x": {
+			points:   []geom.Pointer{geom.Point{1, 0}, geom.Point{1, 1}, geom.Point{1, 2}, geom.Point{0, 0}},
+			payloads: []interface{}{1, 2, 3, 4},
+			eJSON:    `{"P":[1,0],"Payloads":[1],"Left":{"P":[0,0],"Payloads":[4]},"Right":{"P":[1,2],"Payloads":[3],"Left":{"P":[1,1],"Payloads":[2]}}}`,
+		},
+		"payload count": {
+			points:   []geom.Pointer{geom.Point{1, 0}},
+			payloads: []interface{}{1, 2},
+			err:      ErrPayloadCount,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestBuildRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+
+	// sorted input is the worst case for Insert
+	pointCount := 4096
+	points := make([]geom.Pointer, pointCount)
+	for i := range points {
+		points[i] = geom.Point{float64(i), float64(rng.Intn(100))}
+	}
+	kdt := Build(points)
+	if n := checkNode(t, kdt.root, 0); n != pointCount {
+		t.Errorf("nodes, expected %v got %v", pointCount, n)
+	}
+	// as with Insert, Build does not add payloads.
+	if got := kdt.root.Payloads(); len(got) != 0 {
+		t.Errorf("payloads, expected none got %v", got)
+	}
+	// a perfectly balanced tree would have a depth of 13; the equal y values push some points to
+	// the right.
+	if d := depth(kdt.root); d > 16 {
+		t.Errorf("depth, expected <= 16 got %v", d)
+	}
+
+	// the tree can still be added to
+	kdt.InsertPayload(geom.Point{-1, -1}, "new")
+	nodes := kdt.NearestNeighbors(geom.Point{-2, -2}, 2)
+	if len(nodes) != 2 || nodes[0].Payloads()[0] != "new" {
+		t.Errorf("nearest, expected new got %v", nodes)
+	}
+	checkNode(t, kdt.root, 0)
+}
+
+func TestInsertPayload(t *testing.T) {
+	kdt := new(KdTree)
+	a := kdt.InsertPayload(geom.Point{0, 0}, "a")
+	kdt.InsertPayload(geom.Point{1, 0}, "b")
+	if b := kdt.InsertPayload(geom.Point{0, 0}, "c"); a != b {
+		t.Errorf("node, expected the existing node")
+	}
+	if got := a.Payloads(); len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("payloads, expected [a c] got %v", got)
+	}
+}
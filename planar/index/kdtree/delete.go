@@ -0,0 +1,115 @@
+package kdtree
+
+import (
+	"github.com/go-spatial/geom"
+)
+
+/*
+Delete removes the point, and all of its payloads, from the kd-tree. Returns false if the point
+was not found.
+*/
+func (kdt *KdTree) Delete(p geom.Pointer) bool {
+	var found bool
+	kdt.root, found = deleteNode(kdt.root, p.XY(), 0)
+	return found
+}
+
+/*
+DeletePayload removes the first payload, of the point's node, that match returns true for. The
+point is removed from the kd-tree when its last payload is removed. Returns false if the point
+or payload was not found.
+
+match is used, rather than comparing the payloads, as payloads such as slices and maps can not
+be compared.
+*/
+func (kdt *KdTree) DeletePayload(p geom.Pointer, match func(payload interface{}) bool) bool {
+	node := kdt.find(p.XY())
+	if node == nil {
+		return false
+	}
+	for i := range node.payloads {
+		if !match(node.payloads[i]) {
+			continue
+		}
+		node.payloads = append(node.payloads[:i], node.payloads[i+1:]...)
+		if len(node.payloads) == 0 {
+			kdt.Delete(p)
+		}
+		return true
+	}
+	return false
+}
+
+// find returns the node for the point, or nil if it is not in the tree.
+func (kdt *KdTree) find(xy [2]float64) *KdNode {
+	node := kdt.root
+	for d := 0; node != nil; d = d ^ 1 {
+		nxy := node.p.XY()
+		switch {
+		case nxy == xy:
+			return node
+		case xy[d] < nxy[d]:
+			node = node.left
+		default:
+			node = node.right
+		}
+	}
+	return nil
+}
+
+// deleteNode removes the point from the subtree rooted at node, split on dimension d. It returns
+// the new root of the subtree, and whether the point was found.
+func deleteNode(node *KdNode, xy [2]float64, d int) (*KdNode, bool) {
+	if node == nil {
+		return nil, false
+	}
+
+	var found bool
+	nxy := node.p.XY()
+	switch {
+	case nxy != xy && xy[d] < nxy[d]:
+		node.left, found = deleteNode(node.left, xy, d^1)
+
+	case nxy != xy:
+		node.right, found = deleteNode(node.right, xy, d^1)
+
+	case node.left == nil && node.right == nil:
+		return nil, true
+
+	default:
+		// Replace the node's point with the smallest point, along d, of the right subtree; so
+		// everything left of the node is still less than it. If there is only a left subtree,
+		// it is moved to the right first.
+		found = true
+		if node.right == nil {
+			node.right, node.left = node.left, nil
+		}
+		min := minNode(node.right, d, d^1)
+		node.p, node.payloads = min.p, min.payloads
+		node.right, _ = deleteNode(node.right, min.p.XY(), d^1)
+	}
+
+	if found {
+		node.updateBBox()
+	}
+	return node, found
+}
+
+// minNode returns the node with the smallest value along dimension d in the subtree rooted at
+// node, which is split on dimension nd.
+func minNode(node *KdNode, d, nd int) *KdNode {
+	if node == nil {
+		return nil
+	}
+	min := minNode(node.left, d, nd^1)
+	if nd != d {
+		// the smallest value could be on either side.
+		if r := minNode(node.right, d, nd^1); r != nil && (min == nil || r.p.XY()[d] < min.p.XY()[d]) {
+			min = r
+		}
+	}
+	if min == nil || node.p.XY()[d] <= min.p.XY()[d] {
+		min = node
+	}
+	return min
+}
@@ -0,0 +1,109 @@
+package kdtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestDelete(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+
+	pointCount := 500
+	points := make([]geom.Pointer, pointCount)
+	payloads := make([]interface{}, pointCount)
+	for i := range points {
+		// a small grid so there are duplicates and many equal coordinates
+		points[i] = geom.Point{float64(rng.Intn(30)), float64(rng.Intn(30))}
+		payloads[i] = i
+	}
+	kdt, err := BuildWithPayloads(points, payloads)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	remaining := make(map[[2]float64]int)
+	for _, pt := range points {
+		remaining[pt.XY()]++
+	}
+
+	for i, pt := range points {
+		if i%3 == 0 {
+			continue
+		}
+		i := i
+		if !kdt.DeletePayload(pt, func(payload interface{}) bool { return payload == i }) {
+			t.Fatalf("delete payload %v, expected true got false", i)
+		}
+		remaining[pt.XY()]--
+		if remaining[pt.XY()] == 0 {
+			delete(remaining, pt.XY())
+		}
+	}
+	if kdt.DeletePayload(points[1], func(payload interface{}) bool { return payload == 1 }) {
+		t.Errorf("delete payload again, expected false got true")
+	}
+
+	n := checkNode(t, kdt.root, 0)
+	if n != len(remaining) {
+		t.Errorf("nodes, expected %v got %v", len(remaining), n)
+	}
+	for xy, count := range remaining {
+		node := kdt.find(xy)
+		if node == nil {
+			t.Errorf("point %v, expected to be found", xy)
+			continue
+		}
+		if len(node.Payloads()) != count {
+			t.Errorf("payloads for %v, expected %v got %v", xy, count, len(node.Payloads()))
+		}
+	}
+
+	// delete the rest of the points
+	for xy := range remaining {
+		if !kdt.Delete(geom.Point(xy)) {
+			t.Errorf("delete %v, expected true got false", xy)
+		}
+		checkNode(t, kdt.root, 0)
+	}
+	if kdt.root != nil {
+		t.Errorf("root, expected nil got %v", kdt.root)
+	}
+	if kdt.Delete(geom.Point{0, 0}) {
+		t.Errorf("delete from empty tree, expected false got true")
+	}
+}
+
+func TestDeletePayloadSlice(t *testing.T) {
+	// slices can not be compared with ==, so have to be matched on their content.
+	kdt := new(KdTree)
+	pt := geom.Point{1, 1}
+	kdt.InsertPayload(pt, []int{1, 2})
+	kdt.InsertPayload(pt, []int{3})
+	kdt.InsertPayload(geom.Point{2, 2}, []int{4})
+
+	first := func(v int) func(interface{}) bool {
+		return func(payload interface{}) bool { return payload.([]int)[0] == v }
+	}
+	if kdt.DeletePayload(pt, first(4)) {
+		t.Errorf("delete payload on another point, expected false got true")
+	}
+	if !kdt.DeletePayload(pt, first(1)) {
+		t.Fatalf("delete payload, expected true got false")
+	}
+	node := kdt.find(pt.XY())
+	if node == nil {
+		t.Fatalf("point, expected to be found")
+	}
+	if got := node.Payloads(); len(got) != 1 || got[0].([]int)[0] != 3 {
+		t.Errorf("payloads, expected [[3]] got %v", got)
+	}
+	if !kdt.DeletePayload(pt, first(3)) {
+		t.Fatalf("delete last payload, expected true got false")
+	}
+	if kdt.find(pt.XY()) != nil {
+		t.Errorf("point, expected to be removed with its last payload")
+	}
+	checkNode(t, kdt.root, 0)
+}
@@ -18,6 +18,8 @@ type KdNode struct {
 	right *KdNode
 	// bbox is the extent of this node and all its children.
 	bbox geom.Extent
+	// payloads are the payloads added for the point.
+	payloads []interface{}
 }
 
 // NewKdNode creates a new node with a properly initialized bbox.
@@ -43,11 +45,13 @@ func (node *KdNode) Right() *KdNode {
 // MarshalJSON is the marshalling function for JSON.
 func (node *KdNode) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		P     geom.Pointer
-		Left  *KdNode `json:",omitempty"`
-		Right *KdNode `json:",omitempty"`
+		P        geom.Pointer
+		Payloads []interface{} `json:",omitempty"`
+		Left     *KdNode       `json:",omitempty"`
+		Right    *KdNode       `json:",omitempty"`
 	}{
 		node.p,
+		node.payloads,
 		node.left,
 		node.right,
 	})
@@ -58,6 +62,23 @@ func (node *KdNode) P() geom.Pointer {
 	return node.p
 }
 
+// Payloads returns the payloads of the point; one for each time the point was added with
+// InsertPayload or BuildWithPayloads.
+func (node *KdNode) Payloads() []interface{} {
+	return node.payloads
+}
+
+// updateBBox recomputes the bbox from the point and the children's bboxes.
+func (node *KdNode) updateBBox() {
+	node.bbox = *geom.NewExtent(node.p.XY())
+	if node.left != nil {
+		node.bbox.Add(&node.left.bbox)
+	}
+	if node.right != nil {
+		node.bbox.Add(&node.right.bbox)
+	}
+}
+
 // SetLeft sets the left child.
 func (node *KdNode) SetLeft(left *KdNode) {
 	node.left = left
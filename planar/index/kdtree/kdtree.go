@@ -10,13 +10,15 @@ import (
 /*
 KdTree is an index for 2 dimensional point data.
 
-Limitations:
+Points can be added one at a time with Insert or InsertPayload; or all at once with Build,
+which gives a balanced tree. The shape of a tree built with Insert depends on the order of
+insertion. If you have a large amount of data to insert it is best to use Build, or to randomize
+the data before inserting.
 
-* Bulk inserts and balancing are not supported. If you have a large amount of data to insert it is
-  best to randomize the data before inserting.
-* Duplicate points are not supported and will return an error.
+Insert does not support duplicate points and will return an error. InsertPayload and Build
+support duplicate points by storing all of the payloads for a point on the same node.
 
-See the *_iterator.go files for how to query data out of the kd-tree.
+See the *_iterator.go files and range.go for how to query data out of the kd-tree.
 
 */
 type KdTree struct {
@@ -31,11 +33,32 @@ Insert the specified geometry into the kd-tree.
 If a duplicate point is inserted, the currently indexed point will be returned along with an error.
 */
 func (kdt *KdTree) Insert(p geom.Pointer) (*KdNode, error) {
+	node, isNew := kdt.insert(p)
+	if !isNew {
+		return node, ErrDuplicateNode
+	}
+	return node, nil
+}
+
+/*
+InsertPayload inserts the specified geometry into the kd-tree along with the payload.
+
+If the point is already in the tree, the payload is added to the existing node. The node for the
+point is returned.
+*/
+func (kdt *KdTree) InsertPayload(p geom.Pointer, payload interface{}) *KdNode {
+	node, _ := kdt.insert(p)
+	node.payloads = append(node.payloads, payload)
+	return node
+}
+
+// insert returns the node for the point, and whether it was added.
+func (kdt *KdTree) insert(p geom.Pointer) (*KdNode, bool) {
 	node := NewKdNode(p)
 
 	if kdt.root == nil {
 		kdt.root = node
-		return node, nil
+		return node, true
 	}
 
 	currentNode := kdt.root
@@ -48,14 +71,14 @@ func (kdt *KdTree) Insert(p geom.Pointer) (*KdNode, error) {
 		switch {
 		// if the new point is a duplicate
 		case p.XY()[0] == cxy[0] && p.XY()[1] == cxy[1]:
-			return currentNode, ErrDuplicateNode
+			return currentNode, false
 
 		// if the new point is on the left
 		case p.XY()[d] < currentNode.p.XY()[d]:
 			if currentNode.Left() == nil {
 				// if there is no left node, populate it
 				currentNode.SetLeft(node)
-				return node, nil
+				return node, true
 			}
 			// if there already is a left node, traverse into it
 			currentNode = currentNode.Left()
@@ -65,13 +88,11 @@ func (kdt *KdTree) Insert(p geom.Pointer) (*KdNode, error) {
 			if currentNode.Right() == nil {
 				// if there is no right node, populate it
 				currentNode.SetRight(node)
-				return node, nil
+				return node, true
 			}
 
 			// traverse into the right node
 			currentNode = currentNode.Right()
 		}
 	}
-
-	return node, nil
 }
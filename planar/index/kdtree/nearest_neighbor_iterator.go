@@ -187,3 +187,13 @@ func (nni *NearestNeighborIterator) pushNode(n *KdNode) {
 func (nni *NearestNeighborIterator) Value() (geom.Pointer, float64) {
 	return nni.currentIt.node.P(), nni.currentIt.d
 }
+
+// NearestNeighbors returns up to k nodes nearest to p, nearest first, using the Euclidean distance.
+func (kdt *KdTree) NearestNeighbors(p geom.Pointer, k int) []*KdNode {
+	var nodes []*KdNode
+	nnit := NewNearestNeighborIterator(p, kdt, EuclideanDistance)
+	for len(nodes) < k && nnit.Next() {
+		nodes = append(nodes, nnit.currentIt.node)
+	}
+	return nodes
+}
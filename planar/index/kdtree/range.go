@@ -0,0 +1,66 @@
+package kdtree
+
+import (
+	"github.com/go-spatial/geom"
+)
+
+/*
+SearchExtent calls fn for each node whose point is inside the extent, including on its boundary,
+until fn returns false. The nodes are visited in no particular order.
+*/
+func (kdt *KdTree) SearchExtent(e *geom.Extent, fn func(node *KdNode) bool) {
+	searchExtent(kdt.root, e, fn)
+}
+
+func searchExtent(node *KdNode, e *geom.Extent, fn func(node *KdNode) bool) bool {
+	if node == nil {
+		return true
+	}
+	if node.bbox.MinX() > e.MaxX() || node.bbox.MaxX() < e.MinX() ||
+		node.bbox.MinY() > e.MaxY() || node.bbox.MaxY() < e.MinY() {
+		return true
+	}
+	if e.ContainsPoint(node.p.XY()) && !fn(node) {
+		return false
+	}
+	return searchExtent(node.left, e, fn) && searchExtent(node.right, e, fn)
+}
+
+/*
+SearchRadius calls fn for each node whose point is within the radius of the center point, including
+on the circle, until fn returns false. The nodes are visited in no particular order. Use a
+NearestNeighborIterator to get the nodes in order of distance.
+*/
+func (kdt *KdTree) SearchRadius(center geom.Pointer, radius float64, fn func(node *KdNode, d float64) bool) {
+	searchRadius(kdt.root, center, radius, fn)
+}
+
+func searchRadius(node *KdNode, center geom.Pointer, radius float64, fn func(node *KdNode, d float64) bool) bool {
+	if node == nil || EuclideanDistance(center, &node.bbox) > radius {
+		return true
+	}
+	if d := distance(center.XY(), node.p.XY()); d <= radius && !fn(node, d) {
+		return false
+	}
+	return searchRadius(node.left, center, radius, fn) && searchRadius(node.right, center, radius, fn)
+}
+
+// RangeQuery returns the nodes whose points are inside the extent, including on its boundary.
+func (kdt *KdTree) RangeQuery(e *geom.Extent) []*KdNode {
+	var nodes []*KdNode
+	kdt.SearchExtent(e, func(node *KdNode) bool {
+		nodes = append(nodes, node)
+		return true
+	})
+	return nodes
+}
+
+// RadiusQuery returns the nodes whose points are within the radius of the center point.
+func (kdt *KdTree) RadiusQuery(center geom.Pointer, radius float64) []*KdNode {
+	var nodes []*KdNode
+	kdt.SearchRadius(center, radius, func(node *KdNode, _ float64) bool {
+		nodes = append(nodes, node)
+		return true
+	})
+	return nodes
+}
@@ -0,0 +1,90 @@
+package kdtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func sortedPoints(nodes []*KdNode) [][2]float64 {
+	pts := make([][2]float64, len(nodes))
+	for i := range nodes {
+		pts[i] = nodes[i].p.XY()
+	}
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i][0] != pts[j][0] {
+			return pts[i][0] < pts[j][0]
+		}
+		return pts[i][1] < pts[j][1]
+	})
+	return pts
+}
+
+func TestRangeQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+
+	var points []geom.Pointer
+	for i := 0; i < 1000; i++ {
+		points = append(points, geom.Point{float64(rng.Intn(1000)), float64(rng.Intn(1000))})
+	}
+	built := Build(points)
+	inserted := new(KdTree)
+	for _, pt := range points {
+		inserted.Insert(pt)
+	}
+
+	for i := 0; i < 20; i++ {
+		x, y := rng.Float64()*1000, rng.Float64()*1000
+		e := geom.NewExtent([2]float64{x, y}, [2]float64{x + rng.Float64()*300, y + rng.Float64()*300})
+		center := geom.Point{x, y}
+		radius := rng.Float64() * 200
+
+		var eExtent, eRadius []*KdNode
+		seen := make(map[[2]float64]bool)
+		for _, pt := range points {
+			if seen[pt.XY()] {
+				continue
+			}
+			seen[pt.XY()] = true
+			if e.ContainsPoint(pt.XY()) {
+				eExtent = append(eExtent, NewKdNode(pt))
+			}
+			if distance(center.XY(), pt.XY()) <= radius {
+				eRadius = append(eRadius, NewKdNode(pt))
+			}
+		}
+
+		for name, kdt := range map[string]*KdTree{"built": built, "inserted": inserted} {
+			if got, expected := sortedPoints(kdt.RangeQuery(e)), sortedPoints(eExtent); !equalPoints(got, expected) {
+				t.Errorf("%v range query, expected %v got %v", name, expected, got)
+			}
+			if got, expected := sortedPoints(kdt.RadiusQuery(center, radius)), sortedPoints(eRadius); !equalPoints(got, expected) {
+				t.Errorf("%v radius query, expected %v got %v", name, expected, got)
+			}
+		}
+	}
+
+	// stop early
+	var count int
+	built.SearchExtent(geom.NewExtent([2]float64{0, 0}, [2]float64{1000, 1000}), func(*KdNode) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Errorf("count, expected 10 got %v", count)
+	}
+}
+
+func equalPoints(a, b [][2]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package packedrtree
+
+import (
+	"encoding/binary"
+)
+
+// magic is the start of a serialized tree.
+var magic = [4]byte{'P', 'H', 'R', 'T'}
+
+const (
+	// version of the serialization format.
+	version = 1
+	// headerSize is the size, in bytes, of the header written by MarshalBinary.
+	headerSize = 16
+)
+
+// MarshalBinary returns the tree serialized with a header, so it can be opened with Open. The
+// header is 16 bytes; the magic "PHRT", a version byte, an unused byte, the node size as a
+// uint16 and the number of items as a uint64; followed by the nodes. All values are
+// little-endian.
+func (t *Tree) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, headerSize+len(t.nodes))
+	copy(buf, magic[:])
+	buf[4] = version
+	binary.LittleEndian.PutUint16(buf[6:], t.nodeSize)
+	binary.LittleEndian.PutUint64(buf[8:], t.numItems)
+	copy(buf[headerSize:], t.nodes)
+	return buf, nil
+}
+
+// Open returns the tree serialized in the buffer by MarshalBinary. The buffer is not copied, so
+// it must not be modified while the tree is in use; this allows a tree to be queried straight
+// from a memory-mapped file.
+//
+// Possible errors:
+//	 ErrInvalidBuffer
+//	 ErrNoItems
+//	 ErrInvalidNodeSize
+func Open(buf []byte) (*Tree, error) {
+	if len(buf) < headerSize || [4]byte{buf[0], buf[1], buf[2], buf[3]} != magic || buf[4] != version {
+		return nil, ErrInvalidBuffer
+	}
+	nodeSize := binary.LittleEndian.Uint16(buf[6:])
+	numItems := binary.LittleEndian.Uint64(buf[8:])
+	return FromNodes(buf[headerSize:], numItems, nodeSize)
+}
@@ -0,0 +1,358 @@
+/*
+Package packedrtree is a static, packed Hilbert R-tree; in the style of flatbush.
+
+The tree is built once from a set of extents, which are sorted along a Hilbert curve and packed
+into full nodes, so it is compact and can not be changed afterwards. The tree is stored as a flat
+array of nodes, which can be written out and queried straight from the bytes without decoding
+them first; for example from a memory-mapped file.
+
+The node layout is the same as the one used by the FlatGeobuf index; each node is 40 bytes, the
+min x, min y, max x and max y as float64s followed by a uint64 offset, all little-endian. The
+levels of the tree are stored from the root to the leaves. The offset of a leaf is the value given
+for the item, and the offset of a node is the index of its first child.
+*/
+package packedrtree
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+const (
+	// NodeItemSize is the size, in bytes, of a node.
+	NodeItemSize = 40
+	// DefaultNodeSize is the default number of children of a node.
+	DefaultNodeSize = 16
+
+	// hilbertMax is the largest coordinate on the Hilbert curve.
+	hilbertMax = (1 << 16) - 1
+)
+
+var (
+	// ErrNoItems is returned when building a tree without any items.
+	ErrNoItems = errors.New("packedrtree: no items")
+	// ErrInvalidNodeSize is returned when the node size is less than 2.
+	ErrInvalidNodeSize = errors.New("packedrtree: node size must be at least 2")
+	// ErrInvalidBuffer is returned when a buffer is too small, or has the wrong header, for the tree.
+	ErrInvalidBuffer = errors.New("packedrtree: invalid buffer")
+)
+
+// Item is an entry of the tree; the offset is the value returned by the queries for the item.
+type Item struct {
+	Extent geom.Extent
+	Offset uint64
+}
+
+// hilbert returns the position of the point along a Hilbert curve of order 16; x and y must be
+// less than 2^16. From https://github.com/rawrunprotected/hilbert_curves (public domain).
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
+
+// itemsExtent returns the extent of all the items.
+func itemsExtent(items []Item) geom.Extent {
+	e := items[0].Extent
+	for i := range items[1:] {
+		e.Add(&items[i+1].Extent)
+	}
+	return e
+}
+
+// SortItems sorts the items, in place, by the position of the center of their extents along a
+// Hilbert curve covering the extent of all the items. New sorts the items the same way; this can
+// be used to write out the data for the items in the same order as the tree.
+func SortItems(items []Item) {
+	if len(items) == 0 {
+		return
+	}
+	e := itemsExtent(items)
+	width, height := e.XSpan(), e.YSpan()
+	values := make([]uint32, len(items))
+	for i := range items {
+		var x, y uint32
+		if width > 0 {
+			x = uint32(math.Floor(hilbertMax * ((items[i].Extent.MinX()+items[i].Extent.MaxX())/2 - e.MinX()) / width))
+		}
+		if height > 0 {
+			y = uint32(math.Floor(hilbertMax * ((items[i].Extent.MinY()+items[i].Extent.MaxY())/2 - e.MinY()) / height))
+		}
+		values[i] = hilbert(x, y)
+	}
+	sort.Stable(byHilbert{items: items, values: values})
+}
+
+type byHilbert struct {
+	items  []Item
+	values []uint32
+}
+
+func (s byHilbert) Len() int           { return len(s.items) }
+func (s byHilbert) Less(i, j int) bool { return s.values[i] < s.values[j] }
+func (s byHilbert) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+// levelBounds returns the [start, end) node indexes of each level of a tree; the leaves first.
+func levelBounds(numItems uint64, nodeSize uint16) [][2]uint64 {
+	n := numItems
+	numNodes := n
+	levelNumNodes := []uint64{n}
+	for n != 1 {
+		n = (n + uint64(nodeSize) - 1) / uint64(nodeSize)
+		numNodes += n
+		levelNumNodes = append(levelNumNodes, n)
+	}
+	bounds := make([][2]uint64, len(levelNumNodes))
+	end := numNodes
+	for i, n := range levelNumNodes {
+		bounds[i] = [2]uint64{end - n, end}
+		end -= n
+	}
+	return bounds
+}
+
+// maxItems is the largest number of items whose nodes' size fits in a uint64; a tree has fewer
+// than twice as many nodes as items.
+const maxItems = math.MaxUint64 / (2 * NodeItemSize)
+
+// Size returns the size, in bytes, of the nodes of a tree with the given number of items and
+// node size; or 0 if there can not be such a tree.
+func Size(numItems uint64, nodeSize uint16) uint64 {
+	if numItems == 0 || numItems > maxItems || nodeSize < 2 {
+		return 0
+	}
+	bounds := levelBounds(numItems, nodeSize)
+	return bounds[0][1] * NodeItemSize
+}
+
+// Tree is a static packed Hilbert R-tree.
+type Tree struct {
+	numItems uint64
+	nodeSize uint16
+	bounds   [][2]uint64
+	nodes    []byte
+}
+
+// New builds a tree from the items, which are sorted by SortItems first. The given slice is
+// sorted in place.
+//
+// Possible errors:
+//	 ErrNoItems
+//	 ErrInvalidNodeSize
+func New(items []Item, nodeSize uint16) (*Tree, error) {
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	if nodeSize < 2 {
+		return nil, ErrInvalidNodeSize
+	}
+	SortItems(items)
+
+	t := &Tree{
+		numItems: uint64(len(items)),
+		nodeSize: nodeSize,
+		bounds:   levelBounds(uint64(len(items)), nodeSize),
+	}
+	t.nodes = make([]byte, t.bounds[0][1]*NodeItemSize)
+
+	for i := range items {
+		t.setNode(t.bounds[0][0]+uint64(i), items[i].Extent, items[i].Offset)
+	}
+	// each node of a level is the parent of nodeSize nodes of the level below it.
+	for level := 0; level < len(t.bounds)-1; level++ {
+		parent := t.bounds[level+1][0]
+		for child := t.bounds[level][0]; child < t.bounds[level][1]; parent++ {
+			first := child
+			e := t.nodeExtent(child)
+			for end := minUint64(child+uint64(nodeSize), t.bounds[level][1]); child < end; child++ {
+				ce := t.nodeExtent(child)
+				e.Add(&ce)
+			}
+			t.setNode(parent, e, first)
+		}
+	}
+	return t, nil
+}
+
+// FromExtents builds a tree from the extents. The offset of each item is its index in the slice.
+//
+// Possible errors:
+//	 ErrNoItems
+//	 ErrInvalidNodeSize
+func FromExtents(extents []geom.Extent, nodeSize uint16) (*Tree, error) {
+	items := make([]Item, len(extents))
+	for i := range extents {
+		items[i] = Item{Extent: extents[i], Offset: uint64(i)}
+	}
+	return New(items, nodeSize)
+}
+
+// minUint64 returns the smaller of the two values.
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// FromNodes returns a tree using the node bytes, as written by Nodes, without copying them. The
+// number of items and node size have to be the ones the tree was built with. The offsets of the
+// nodes above the leaves are checked to point at the level below them, so a corrupt buffer is
+// an error instead of failing the queries.
+//
+// Possible errors:
+//	 ErrNoItems
+//	 ErrInvalidNodeSize
+//	 ErrInvalidBuffer
+func FromNodes(nodes []byte, numItems uint64, nodeSize uint16) (*Tree, error) {
+	if numItems == 0 {
+		return nil, ErrNoItems
+	}
+	if nodeSize < 2 {
+		return nil, ErrInvalidNodeSize
+	}
+	size := Size(numItems, nodeSize)
+	if size == 0 || uint64(len(nodes)) < size {
+		return nil, ErrInvalidBuffer
+	}
+	t := &Tree{
+		numItems: numItems,
+		nodeSize: nodeSize,
+		bounds:   levelBounds(numItems, nodeSize),
+		nodes:    nodes[:size],
+	}
+	for level := 1; level < len(t.bounds); level++ {
+		children := t.bounds[level-1]
+		for i := t.bounds[level][0]; i < t.bounds[level][1]; i++ {
+			if child := t.nodeOffset(i); child < children[0] || child >= children[1] {
+				return nil, ErrInvalidBuffer
+			}
+		}
+	}
+	return t, nil
+}
+
+// Nodes returns the bytes of the nodes of the tree. The bytes are not copied, and must not be
+// modified.
+func (t *Tree) Nodes() []byte { return t.nodes }
+
+// Len returns the number of items in the tree.
+func (t *Tree) Len() uint64 { return t.numItems }
+
+// NodeSize returns the number of children of each node.
+func (t *Tree) NodeSize() uint16 { return t.nodeSize }
+
+// Extent returns the extent of all the items.
+func (t *Tree) Extent() geom.Extent { return t.nodeExtent(0) }
+
+func (t *Tree) setNode(i uint64, e geom.Extent, offset uint64) {
+	b := t.nodes[i*NodeItemSize:]
+	for j := 0; j < 4; j++ {
+		binary.LittleEndian.PutUint64(b[j*8:], math.Float64bits(e[j]))
+	}
+	binary.LittleEndian.PutUint64(b[32:], offset)
+}
+
+func (t *Tree) nodeExtent(i uint64) geom.Extent {
+	b := t.nodes[i*NodeItemSize:]
+	var e geom.Extent
+	for j := 0; j < 4; j++ {
+		e[j] = math.Float64frombits(binary.LittleEndian.Uint64(b[j*8:]))
+	}
+	return e
+}
+
+func (t *Tree) nodeOffset(i uint64) uint64 {
+	return binary.LittleEndian.Uint64(t.nodes[i*NodeItemSize+32:])
+}
+
+// Search calls fn with the offset of each item whose extent intersects the given extent,
+// including touching it, until fn returns false. The items are visited in the order of the tree.
+func (t *Tree) Search(e *geom.Extent, fn func(offset uint64) bool) {
+	type entry struct {
+		node  uint64
+		level int
+	}
+	stack := []entry{{node: 0, level: len(t.bounds) - 1}}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		end := minUint64(cur.node+uint64(t.nodeSize), t.bounds[cur.level][1])
+		// children are pushed in reverse, so they are visited in order.
+		var children []entry
+		for i := cur.node; i < end; i++ {
+			ne := t.nodeExtent(i)
+			if ne.MinX() > e.MaxX() || ne.MaxX() < e.MinX() || ne.MinY() > e.MaxY() || ne.MaxY() < e.MinY() {
+				continue
+			}
+			if cur.level == 0 {
+				if !fn(t.nodeOffset(i)) {
+					return
+				}
+				continue
+			}
+			children = append(children, entry{node: t.nodeOffset(i), level: cur.level - 1})
+		}
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+}
+
+// SearchOffsets returns the offsets of the items whose extents intersect the given extent.
+func (t *Tree) SearchOffsets(e *geom.Extent) []uint64 {
+	var offsets []uint64
+	t.Search(e, func(offset uint64) bool {
+		offsets = append(offsets, offset)
+		return true
+	})
+	return offsets
+}
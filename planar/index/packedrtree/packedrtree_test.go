@@ -0,0 +1,218 @@
+package packedrtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestHilbert(t *testing.T) {
+	// walking a coarse grid in Hilbert order should only ever move to a neighboring cell.
+	const n, step = 16, 1 << 12
+	type cell struct {
+		x, y int
+		h    uint32
+	}
+	var cells []cell
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			cells = append(cells, cell{x: x, y: y, h: hilbert(uint32(x*step+step/2), uint32(y*step+step/2))})
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i].h < cells[j].h })
+	for i := 1; i < len(cells); i++ {
+		a, b := cells[i-1], cells[i]
+		if a.h == b.h {
+			t.Fatalf("hilbert, expected distinct values for %v and %v", a, b)
+		}
+		if d := abs(a.x-b.x) + abs(a.y-b.y); d != 1 {
+			t.Errorf("cells %v and %v, expected to be neighbors", a, b)
+		}
+	}
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func TestLevelBounds(t *testing.T) {
+	type tcase struct {
+		numItems uint64
+		nodeSize uint16
+		expected [][2]uint64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := levelBounds(tc.numItems, tc.nodeSize)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("levels, expected %v got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("levels, expected %v got %v", tc.expected, got)
+				}
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"one item":  {numItems: 1, nodeSize: 16, expected: [][2]uint64{{0, 1}}},
+		"one node":  {numItems: 16, nodeSize: 16, expected: [][2]uint64{{1, 17}, {0, 1}}},
+		"two nodes": {numItems: 17, nodeSize: 16, expected: [][2]uint64{{3, 20}, {1, 3}, {0, 1}}},
+		"three levels": {
+			numItems: 300, nodeSize: 16,
+			expected: [][2]uint64{{22, 322}, {3, 22}, {1, 3}, {0, 1}},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func randomExtents(rng *rand.Rand, n int) []geom.Extent {
+	extents := make([]geom.Extent, n)
+	for i := range extents {
+		x, y := rng.Float64()*1000, rng.Float64()*1000
+		extents[i] = geom.Extent{x, y, x + rng.Float64()*20, y + rng.Float64()*20}
+	}
+	return extents
+}
+
+func bruteForce(extents []geom.Extent, q *geom.Extent) []uint64 {
+	var offsets []uint64
+	for i, e := range extents {
+		if e.MinX() <= q.MaxX() && e.MaxX() >= q.MinX() && e.MinY() <= q.MaxY() && e.MaxY() >= q.MinY() {
+			offsets = append(offsets, uint64(i))
+		}
+	}
+	return offsets
+}
+
+func sortedOffsets(offsets []uint64) []uint64 {
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets
+}
+
+func equalOffsets(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+
+	for _, n := range []int{1, 2, 16, 17, 1000} {
+		extents := randomExtents(rng, n)
+		for _, nodeSize := range []uint16{2, 4, DefaultNodeSize} {
+			tree, err := FromExtents(extents, nodeSize)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if tree.Len() != uint64(n) {
+				t.Errorf("len, expected %v got %v", n, tree.Len())
+			}
+			all := geom.Extent{-1, -1, 2000, 2000}
+			if got := tree.SearchOffsets(&all); len(got) != n {
+				t.Errorf("all items, expected %v got %v", n, len(got))
+			}
+
+			for i := 0; i < 20; i++ {
+				x, y := rng.Float64()*1000, rng.Float64()*1000
+				q := geom.Extent{x, y, x + rng.Float64()*200, y + rng.Float64()*200}
+				expected := bruteForce(extents, &q)
+				got := sortedOffsets(tree.SearchOffsets(&q))
+				if !equalOffsets(got, expected) {
+					t.Errorf("%v items, node size %v: search, expected %v got %v", n, nodeSize, expected, got)
+				}
+			}
+		}
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	extents := randomExtents(rng, 500)
+	tree, err := FromExtents(extents, DefaultNodeSize)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	buf, err := tree.MarshalBinary()
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if expected := headerSize + Size(500, DefaultNodeSize); uint64(len(buf)) != expected {
+		t.Errorf("size, expected %v got %v", expected, len(buf))
+	}
+	opened, err := Open(buf)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if opened.Extent() != tree.Extent() {
+		t.Errorf("extent, expected %v got %v", tree.Extent(), opened.Extent())
+	}
+
+	q := geom.Extent{100, 100, 300, 300}
+	if got, expected := opened.SearchOffsets(&q), tree.SearchOffsets(&q); !equalOffsets(got, expected) {
+		t.Errorf("search, expected %v got %v", expected, got)
+	}
+
+	raw, err := FromNodes(tree.Nodes(), tree.Len(), tree.NodeSize())
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if got, expected := raw.SearchOffsets(&q), tree.SearchOffsets(&q); !equalOffsets(got, expected) {
+		t.Errorf("search, expected %v got %v", expected, got)
+	}
+
+	type tcase struct {
+		buf []byte
+		err error
+	}
+	// the offset of the root's first child pointing past the nodes
+	corrupt := append([]byte{}, buf...)
+	for i := 0; i < 8; i++ {
+		corrupt[headerSize+32+i] = 0xff
+	}
+	// a number of items whose size overflows
+	overflow := append([]byte{}, buf...)
+	for i := 8; i < 16; i++ {
+		overflow[i] = 0xff
+	}
+	tcases := map[string]tcase{
+		"corrupt offset": {buf: corrupt, err: ErrInvalidBuffer},
+		"too many items": {buf: overflow, err: ErrInvalidBuffer},
+		"empty":          {buf: nil, err: ErrInvalidBuffer},
+		"bad magic":      {buf: append([]byte("NOPE"), buf[4:]...), err: ErrInvalidBuffer},
+		"truncated":      {buf: buf[:len(buf)-1], err: ErrInvalidBuffer},
+		"header only":    {buf: append([]byte{}, buf[:headerSize]...), err: ErrInvalidBuffer},
+	}
+	for name, tc := range tcases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Open(tc.buf); err != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+		})
+	}
+
+	if _, err := New(nil, DefaultNodeSize); err != ErrNoItems {
+		t.Errorf("error, expected %v got %v", ErrNoItems, err)
+	}
+	if _, err := FromExtents(extents, 1); err != ErrInvalidNodeSize {
+		t.Errorf("error, expected %v got %v", ErrInvalidNodeSize, err)
+	}
+}
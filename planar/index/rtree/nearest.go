@@ -0,0 +1,149 @@
+package rtree
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/rtreego"
+	"github.com/go-spatial/geom/planar"
+)
+
+// extentDistance returns the distance between the extent and the rectangle;
+// zero if they intersect.
+func extentDistance(e *geom.Extent, r *rtreego.Rect) float64 {
+	dx := math.Max(0, math.Max(r.PointCoord(0)-e.MaxX(), e.MinX()-(r.PointCoord(0)+r.LengthsCoord(0))))
+	dy := math.Max(0, math.Max(r.PointCoord(1)-e.MaxY(), e.MinY()-(r.PointCoord(1)+r.LengthsCoord(1))))
+	return math.Hypot(dx, dy)
+}
+
+/*
+NearestIterator returns the items of a tree in order of increasing distance
+from a geometry. The distances are the exact distances between the
+geometries, as computed by planar.Distance; the extents of the items are
+only used to decide which items to compute the distance for next, so
+retrieving the nearest few items is efficient.
+
+The iterator only holds a read lock on the tree during the calls to Next, so
+the tree can be queried and modified between them. If the tree is modified
+the iteration stops, and Err returns ErrTreeModified.
+
+To use this iterator:
+
+	it := tree.NewNearestIterator(geom.Point{0, 0})
+	defer it.Close()
+
+	for it.Next() {
+		item, d := it.Value()
+		// do stuff
+	}
+	if err := it.Err(); err != nil {
+		// handle the error
+	}
+
+*/
+type NearestIterator struct {
+	tree    *Tree
+	version uint64
+	it      *rtreego.DistanceIterator
+	item    *Item
+	dist    float64
+	err     error
+	done    bool
+}
+
+// NewNearestIterator returns an iterator over the items in the tree, in order
+// of increasing distance from the geometry.
+func (t *Tree) NewNearestIterator(g geom.Geometry) *NearestIterator {
+	nit := &NearestIterator{tree: t}
+	extent, err := geom.NewExtentFromGeometry(g)
+	if err == nil && extent == nil {
+		err = planar.ErrEmptyGeometry
+	}
+	if err != nil {
+		nit.err, nit.done = err, true
+		return nit
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	nit.version = t.version
+	nit.it = t.tree.NewDistanceIterator(
+		func(r *rtreego.Rect) float64 { return extentDistance(extent, r) },
+		func(obj rtreego.Spatial) float64 {
+			if nit.err != nil {
+				return 0
+			}
+			d, err := planar.Distance(g, obj.(*leaf).item.Geometry)
+			if err != nil {
+				nit.err = err
+			}
+			return d
+		},
+	)
+	return nit
+}
+
+// Next moves to the next nearest item. It returns false when there are no
+// more items, or an error occurred.
+//
+// Possible errors:
+//	 ErrTreeModified
+//	 geom.ErrUnknownGeometry
+func (nit *NearestIterator) Next() bool {
+	if nit.done {
+		return false
+	}
+	nit.tree.mu.RLock()
+	defer nit.tree.mu.RUnlock()
+	if nit.tree.version != nit.version {
+		nit.err = ErrTreeModified
+		nit.Close()
+		return false
+	}
+	obj, d, ok := nit.it.Next()
+	if !ok || nit.err != nil {
+		nit.Close()
+		return false
+	}
+	nit.item, nit.dist = obj.(*leaf).item, d
+	return true
+}
+
+// Value returns the current item and its distance.
+func (nit *NearestIterator) Value() (*Item, float64) { return nit.item, nit.dist }
+
+// Err returns the error, if any, that stopped the iteration.
+func (nit *NearestIterator) Err() error { return nit.err }
+
+// Close stops the iteration, and releases the state kept by the iterator. It
+// is safe to call more than once.
+func (nit *NearestIterator) Close() {
+	nit.done, nit.it, nit.item = true, nil, nil
+}
+
+// Neighbor is an item returned by Nearest, with its distance.
+type Neighbor struct {
+	Item     *Item
+	Distance float64
+}
+
+// Nearest returns the k items nearest to the geometry, nearest first. Fewer
+// items are returned if there are fewer than k in the tree.
+//
+// Possible errors:
+//	 geom.ErrUnknownGeometry
+//	 planar.ErrEmptyGeometry
+func (t *Tree) Nearest(g geom.Geometry, k int) ([]Neighbor, error) {
+	nit := t.NewNearestIterator(g)
+	defer nit.Close()
+
+	var neighbors []Neighbor
+	for len(neighbors) < k && nit.Next() {
+		item, d := nit.Value()
+		neighbors = append(neighbors, Neighbor{Item: item, Distance: d})
+	}
+	if err := nit.Err(); err != nil {
+		return nil, err
+	}
+	return neighbors, nil
+}
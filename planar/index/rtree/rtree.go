@@ -0,0 +1,246 @@
+// Package rtree provides an R-tree index of geometries.
+//
+// Each geometry is indexed by its extent, and is stored with a user supplied
+// payload. The extent is used to find the candidates for a query; the
+// queries that are about the geometries themselves (Intersects,
+// ContainsPoint, and the nearest neighbor queries) then check the candidates
+// using the geometries.
+//
+// A Tree is safe for concurrent use; any number of queries can run at the
+// same time, while Insert and Delete wait for them to finish.
+package rtree
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/rtreego"
+	"github.com/go-spatial/geom/planar"
+)
+
+const (
+	// minChildren and maxChildren are the branching factors of the tree.
+	minChildren = 8
+	maxChildren = 16
+	// smallep is added to the lengths of the rectangles, which have to be
+	// positive; so points and horizontal or vertical lines can be indexed.
+	smallep = 1e-9
+)
+
+// ErrTreeModified is returned by NearestIterator.Err when the tree was
+// modified while iterating.
+var ErrTreeModified = errors.New("rtree: tree modified during iteration")
+
+// Item is a geometry stored in the tree, along with its payload.
+type Item struct {
+	Geometry geom.Geometry
+	Payload  interface{}
+
+	extent *geom.Extent
+	leaf   *leaf
+}
+
+// leaf is what is stored in the rtreego tree for an item.
+type leaf struct {
+	item *Item
+	rect *rtreego.Rect
+}
+
+// Bounds implements the rtreego.Spatial interface.
+func (l *leaf) Bounds() *rtreego.Rect { return l.rect }
+
+// NewItem returns an item for the geometry and payload, ready to be added to
+// a tree. The item is used to Delete the geometry from the tree.
+//
+// Possible errors:
+//	 geom.ErrUnknownGeometry
+//	 planar.ErrEmptyGeometry
+func NewItem(g geom.Geometry, payload interface{}) (*Item, error) {
+	extent, err := geom.NewExtentFromGeometry(g)
+	if err != nil {
+		return nil, err
+	}
+	if extent == nil {
+		return nil, planar.ErrEmptyGeometry
+	}
+	item := &Item{
+		Geometry: g,
+		Payload:  payload,
+		extent:   extent,
+	}
+	item.leaf = &leaf{item: item, rect: rectFor(extent)}
+	return item, nil
+}
+
+// Extent returns the extent of the item's geometry.
+func (item *Item) Extent() *geom.Extent { return item.extent }
+
+// rectFor returns the rectangle for the extent.
+func rectFor(e *geom.Extent) *rtreego.Rect {
+	rect, err := rtreego.NewRect(
+		rtreego.Point{e.MinX(), e.MinY()},
+		[]float64{e.XSpan() + smallep, e.YSpan() + smallep},
+	)
+	if err != nil {
+		panic("Assumption broken:" + err.Error())
+	}
+	return rect
+}
+
+// Tree is an R-tree index of geometries. The zero value is not usable; use New.
+type Tree struct {
+	mu   sync.RWMutex
+	tree *rtreego.Rtree
+	// version is incremented each time the tree is modified, so iterators
+	// can tell that the tree changed between calls.
+	version uint64
+}
+
+// New returns a tree containing the given items. When there are many items,
+// the tree is bulk loaded using the Overlap Minimizing Top-down algorithm,
+// which gives a better tree than inserting them one at a time.
+func New(items ...*Item) *Tree {
+	objs := make([]rtreego.Spatial, len(items))
+	for i := range items {
+		objs[i] = items[i].leaf
+	}
+	return &Tree{
+		tree: rtreego.NewTree(2, minChildren, maxChildren, objs...),
+	}
+}
+
+// Len returns the number of items in the tree.
+func (t *Tree) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Size()
+}
+
+// Insert adds the geometry and payload to the tree, and returns the item
+// that was added.
+//
+// Possible errors:
+//	 geom.ErrUnknownGeometry
+//	 planar.ErrEmptyGeometry
+func (t *Tree) Insert(g geom.Geometry, payload interface{}) (*Item, error) {
+	item, err := NewItem(g, payload)
+	if err != nil {
+		return nil, err
+	}
+	t.InsertItem(item)
+	return item, nil
+}
+
+// InsertItem adds the item to the tree.
+func (t *Tree) InsertItem(item *Item) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tree.Insert(item.leaf)
+	t.version++
+}
+
+// Delete removes the item from the tree. Items are compared by identity, so
+// the item must be the one returned by Insert or given to New or InsertItem.
+// Returns false if the item was not in the tree.
+func (t *Tree) Delete(item *Item) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.tree.Delete(item.leaf) {
+		return false
+	}
+	t.version++
+	return true
+}
+
+// extentsIntersect returns whether the extents intersect, including touching.
+func extentsIntersect(a, b *geom.Extent) bool {
+	return a.MinX() <= b.MaxX() && b.MinX() <= a.MaxX() &&
+		a.MinY() <= b.MaxY() && b.MinY() <= a.MaxY()
+}
+
+// Search calls fn for each item whose extent intersects the given extent,
+// until fn returns false. Calling Insert or Delete from fn will deadlock.
+func (t *Tree) Search(extent *geom.Extent, fn func(item *Item) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	t.tree.SearchIntersect(rectFor(extent), func(_ []rtreego.Spatial, obj rtreego.Spatial) (refuse, abort bool) {
+		item := obj.(*leaf).item
+		// the rectangles are slightly larger than the extents
+		if !extentsIntersect(extent, item.extent) {
+			return true, false
+		}
+		return true, !fn(item)
+	})
+}
+
+// Each calls fn for each item in the tree, until fn returns false. Calling
+// Insert or Delete from fn will deadlock.
+func (t *Tree) Each(fn func(item *Item) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	t.tree.Each(func(obj rtreego.Spatial) bool { return fn(obj.(*leaf).item) })
+}
+
+// SearchExtent returns the items whose extents intersect the given extent.
+func (t *Tree) SearchExtent(extent *geom.Extent) []*Item {
+	var items []*Item
+	t.Search(extent, func(item *Item) bool {
+		items = append(items, item)
+		return true
+	})
+	return items
+}
+
+// Within returns the items whose geometries are entirely inside the extent;
+// including on its boundary.
+func (t *Tree) Within(extent *geom.Extent) []*Item {
+	var items []*Item
+	t.Search(extent, func(item *Item) bool {
+		if extent.Contains(item.extent) {
+			items = append(items, item)
+		}
+		return true
+	})
+	return items
+}
+
+// Intersects returns the items whose geometries intersect the given
+// geometry. Polygons are taken to include their interior, and a geometry
+// touching the boundary of another intersects it.
+//
+// Possible errors:
+//	 geom.ErrUnknownGeometry
+//	 planar.ErrEmptyGeometry
+func (t *Tree) Intersects(g geom.Geometry) ([]*Item, error) {
+	extent, err := geom.NewExtentFromGeometry(g)
+	if err != nil {
+		return nil, err
+	}
+	if extent == nil {
+		return nil, planar.ErrEmptyGeometry
+	}
+	var items []*Item
+	t.Search(extent, func(item *Item) bool {
+		d, derr := planar.Distance(g, item.Geometry)
+		if derr != nil {
+			err = derr
+			return false
+		}
+		if d == 0 {
+			items = append(items, item)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ContainsPoint returns the items whose geometries contain the point; for
+// polygons that is the point being inside of the polygon or on its boundary,
+// for the other geometries it is the point being on them.
+func (t *Tree) ContainsPoint(pt [2]float64) ([]*Item, error) {
+	return t.Intersects(geom.Point(pt))
+}
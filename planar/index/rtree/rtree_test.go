@@ -0,0 +1,298 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+// payloads returns the sorted payloads of the items.
+func payloads(items []*Item) []string {
+	ps := make([]string, len(items))
+	for i := range items {
+		ps[i] = items[i].Payload.(string)
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testTree(t *testing.T) *Tree {
+	geoms := map[string]geom.Geometry{
+		"square":  geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+		"donut":   geom.Polygon{{{20, 0}, {30, 0}, {30, 10}, {20, 10}}, {{22, 2}, {28, 2}, {28, 8}, {22, 8}}},
+		"point":   geom.Point{15, 5},
+		"line":    geom.LineString{{0, 20}, {30, 20}},
+		"vline":   geom.LineString{{40, 0}, {40, 30}},
+		"multipt": geom.MultiPoint{{50, 50}, {60, 60}},
+	}
+	var items []*Item
+	for name, g := range geoms {
+		item, err := NewItem(g, name)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		items = append(items, item)
+	}
+	return New(items...)
+}
+
+func TestTree_Queries(t *testing.T) {
+	tree := testTree(t)
+	if tree.Len() != 6 {
+		t.Fatalf("len, expected 6 got %v", tree.Len())
+	}
+
+	t.Run("search extent", func(t *testing.T) {
+		got := payloads(tree.SearchExtent(geom.NewExtent([2]float64{5, 5}, [2]float64{25, 5})))
+		expected := []string{"donut", "point", "square"}
+		if !equalStrings(got, expected) {
+			t.Errorf("items, expected %v got %v", expected, got)
+		}
+	})
+
+	t.Run("within", func(t *testing.T) {
+		got := payloads(tree.Within(geom.NewExtent([2]float64{-1, -1}, [2]float64{40, 20})))
+		expected := []string{"donut", "line", "point", "square"}
+		if !equalStrings(got, expected) {
+			t.Errorf("items, expected %v got %v", expected, got)
+		}
+	})
+
+	t.Run("intersects", func(t *testing.T) {
+		type tcase struct {
+			geom     geom.Geometry
+			expected []string
+		}
+		tcases := map[string]tcase{
+			"line through":   {geom: geom.LineString{{5, 5}, {45, 15}}, expected: []string{"donut", "square", "vline"}},
+			"in the hole":    {geom: geom.Point{25, 5}},
+			"on the edge":    {geom: geom.Point{10, 5}, expected: []string{"square"}},
+			"on the point":   {geom: geom.Point{15, 5}, expected: []string{"point"}},
+			"polygon around": {geom: geom.Polygon{{{14, 4}, {16, 4}, {16, 6}, {14, 6}}}, expected: []string{"point"}},
+			"extent only":    {geom: geom.LineString{{51, 59}, {59, 51}}},
+		}
+		for name, tc := range tcases {
+			t.Run(name, func(t *testing.T) {
+				items, err := tree.Intersects(tc.geom)
+				if err != nil {
+					t.Fatalf("error, expected nil got %v", err)
+				}
+				if got := payloads(items); !equalStrings(got, tc.expected) {
+					t.Errorf("items, expected %v got %v", tc.expected, got)
+				}
+			})
+		}
+	})
+
+	t.Run("contains point", func(t *testing.T) {
+		items, err := tree.ContainsPoint([2]float64{5, 5})
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if got := payloads(items); !equalStrings(got, []string{"square"}) {
+			t.Errorf("items, expected [square] got %v", got)
+		}
+	})
+
+	t.Run("each", func(t *testing.T) {
+		var n int
+		tree.Each(func(*Item) bool {
+			n++
+			return n < 4
+		})
+		if n != 4 {
+			t.Errorf("count, expected 4 got %v", n)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := tree.Intersects(geom.LineString{}); err != planar.ErrEmptyGeometry {
+			t.Errorf("error, expected %v got %v", planar.ErrEmptyGeometry, err)
+		}
+		if _, err := NewItem(nil, "nil"); err == nil {
+			t.Errorf("error, expected error got nil")
+		}
+	})
+}
+
+func TestTree_InsertDelete(t *testing.T) {
+	tree := New()
+	var items []*Item
+	for i := 0; i < 100; i++ {
+		item, err := tree.Insert(geom.Point{float64(i), float64(i)}, i)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		items = append(items, item)
+	}
+	if tree.Len() != 100 {
+		t.Fatalf("len, expected 100 got %v", tree.Len())
+	}
+	for _, item := range items[:50] {
+		if !tree.Delete(item) {
+			t.Errorf("delete %v, expected true got false", item.Payload)
+		}
+	}
+	if tree.Delete(items[0]) {
+		t.Errorf("delete again, expected false got true")
+	}
+	if tree.Len() != 50 {
+		t.Fatalf("len, expected 50 got %v", tree.Len())
+	}
+	got := tree.SearchExtent(geom.NewExtent([2]float64{0, 0}, [2]float64{100, 100}))
+	if len(got) != 50 {
+		t.Errorf("items, expected 50 got %v", len(got))
+	}
+	for _, item := range got {
+		if item.Payload.(int) < 50 {
+			t.Errorf("item %v, expected to be deleted", item.Payload)
+		}
+	}
+}
+
+func TestTree_Nearest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var items []*Item
+	for i := 0; i < 500; i++ {
+		x, y := rnd.Float64()*1000, rnd.Float64()*1000
+		var g geom.Geometry = geom.LineString{{x, y}, {x + rnd.Float64()*50, y + rnd.Float64()*50}}
+		if i%2 == 0 {
+			g = geom.Point{x, y}
+		}
+		item, err := NewItem(g, i)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		items = append(items, item)
+	}
+	tree := New(items...)
+
+	query := geom.LineString{{400, 400}, {450, 420}}
+	expected := make([]float64, len(items))
+	for i, item := range items {
+		expected[i], _ = planar.Distance(query, item.Geometry)
+	}
+	sort.Float64s(expected)
+
+	got, err := tree.Nearest(query, 20)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(got) != 20 {
+		t.Fatalf("len, expected 20 got %v", len(got))
+	}
+	for i, n := range got {
+		if n.Distance != expected[i] {
+			t.Errorf("distance %v, expected %v got %v", i, expected[i], n.Distance)
+		}
+	}
+
+	// the iterator returns everything in order.
+	nit := tree.NewNearestIterator(query)
+	var count int
+	last := -1.0
+	for nit.Next() {
+		_, d := nit.Value()
+		if d < last {
+			t.Errorf("distance, expected >= %v got %v", last, d)
+		}
+		last = d
+		count++
+	}
+	if err := nit.Err(); err != nil {
+		t.Errorf("error, expected nil got %v", err)
+	}
+	if count != len(items) {
+		t.Errorf("count, expected %v got %v", len(items), count)
+	}
+
+	// the iterator does not hold the lock between calls; this would
+	// deadlock otherwise.
+	tree.Insert(geom.Point{0, 0}, "new")
+	nit = tree.NewNearestIterator(query)
+	nit.Next()
+	tree.Insert(geom.Point{0, 0}, "new")
+	nit.Close()
+	nit.Close()
+	tree.Insert(geom.Point{0, 0}, "new")
+	if nit.Next() {
+		t.Errorf("next after close, expected false got true")
+	}
+
+	// queries can be made while iterating, even with a writer waiting.
+	nit = tree.NewNearestIterator(query)
+	var wg sync.WaitGroup
+	for i := 0; nit.Next() && i < 10; i++ {
+		item, _ := nit.Value()
+		if i == 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tree.Insert(geom.Point{1, 1}, "writer")
+			}()
+			time.Sleep(10 * time.Millisecond)
+		}
+		tree.SearchExtent(item.Extent())
+	}
+	wg.Wait()
+	if err := nit.Err(); err != ErrTreeModified {
+		t.Errorf("error, expected %v got %v", ErrTreeModified, err)
+	}
+	nit.Close()
+
+	// modifying the tree stops the iteration.
+	nit = tree.NewNearestIterator(query)
+	if !nit.Next() {
+		t.Fatalf("next, expected true got false")
+	}
+	item, _ := nit.Value()
+	tree.Delete(item)
+	if nit.Next() {
+		t.Errorf("next after delete, expected false got true")
+	}
+	if err := nit.Err(); err != ErrTreeModified {
+		t.Errorf("error, expected %v got %v", ErrTreeModified, err)
+	}
+}
+
+func TestTree_Concurrent(t *testing.T) {
+	tree := New()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				tree.Insert(geom.Point{float64(w), float64(i)}, i)
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				tree.SearchExtent(geom.NewExtent([2]float64{0, 0}, [2]float64{10, 100}))
+				tree.Nearest(geom.Point{2, 50}, 5)
+			}
+		}()
+	}
+	wg.Wait()
+	if tree.Len() != 400 {
+		t.Errorf("len, expected 400 got %v", tree.Len())
+	}
+}
@@ -0,0 +1,170 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+// LineMerger joins linestrings that share end points into longer linestrings.
+type LineMerger struct {
+	// CMP is used to determine if two end points are the same point. End
+	// points that are equal are snapped together. If not set,
+	// cmp.DefaultCompare() is used.
+	CMP cmp.Compare
+
+	// Directed will only join linestrings where the end of one linestring is
+	// the start of the other; the direction of the linestrings is kept.
+	Directed bool
+}
+
+// LineMerge joins the linestrings of the multilinestring at the nodes
+// where exactly two linestrings meet, returning the maximal linestrings.
+// The direction of the linestrings is not kept.
+func LineMerge(mls geom.MultiLineString) geom.MultiLineString {
+	return LineMerger{}.Merge(mls)
+}
+
+// mergeEdge is a linestring being merged, and the nodes at either end.
+type mergeEdge struct {
+	start, end int
+	ls         [][2]float64
+	used       bool
+}
+
+// endpointSnapper maps end points to node ids, end points that are equal
+// get the same node.
+type endpointSnapper struct {
+	cmp   cmp.Compare
+	cell  float64
+	grid  map[[2]int64][]int
+	nodes [][2]float64
+}
+
+func (s *endpointSnapper) node(pt [2]float64) int {
+	k := [2]int64{int64(math.Floor(pt[0] / s.cell)), int64(math.Floor(pt[1] / s.cell))}
+	for x := k[0] - 1; x <= k[0]+1; x++ {
+		for y := k[1] - 1; y <= k[1]+1; y++ {
+			for _, n := range s.grid[[2]int64{x, y}] {
+				if s.cmp.PointEqual(s.nodes[n], pt) {
+					return n
+				}
+			}
+		}
+	}
+	s.nodes = append(s.nodes, pt)
+	s.grid[k] = append(s.grid[k], len(s.nodes)-1)
+	return len(s.nodes) - 1
+}
+
+// Merge joins the linestrings of the multilinestring at the nodes where
+// exactly two linestrings meet, returning the maximal linestrings. If
+// Directed is set, only nodes with one incoming and one outgoing
+// linestring are joined. Linestrings with fewer then two points are dropped.
+func (lm LineMerger) Merge(mls geom.MultiLineString) geom.MultiLineString {
+	compare := lm.CMP
+	if compare == (cmp.Compare{}) {
+		compare = cmp.DefaultCompare()
+	}
+	snapper := endpointSnapper{
+		cmp:  compare,
+		cell: math.Max(compare.Tolerance, 1e-12) * 10,
+		grid: make(map[[2]int64][]int),
+	}
+
+	var edges []*mergeEdge
+	for _, ls := range mls {
+		if len(ls) < 2 {
+			continue
+		}
+		start, end := snapper.node(ls[0]), snapper.node(ls[len(ls)-1])
+		vertices := make([][2]float64, len(ls))
+		copy(vertices, ls)
+		vertices[0], vertices[len(vertices)-1] = snapper.nodes[start], snapper.nodes[end]
+		edges = append(edges, &mergeEdge{start: start, end: end, ls: vertices})
+	}
+
+	// in and out are the edges going into and out of each node.
+	in := make([][]*mergeEdge, len(snapper.nodes))
+	out := make([][]*mergeEdge, len(snapper.nodes))
+	for _, e := range edges {
+		out[e.start] = append(out[e.start], e)
+		in[e.end] = append(in[e.end], e)
+	}
+
+	// passThrough returns whether two linestrings should be joined at the node.
+	passThrough := func(n int) bool {
+		if lm.Directed {
+			return len(in[n]) == 1 && len(out[n]) == 1
+		}
+		return len(in[n])+len(out[n]) == 2
+	}
+
+	// nextEdge returns the next unused edge at the node, and whether it
+	// needs to be reversed to continue the line.
+	nextEdge := func(n int) (*mergeEdge, bool) {
+		for _, e := range out[n] {
+			if !e.used {
+				return e, false
+			}
+		}
+		if lm.Directed {
+			return nil, false
+		}
+		for _, e := range in[n] {
+			if !e.used {
+				return e, true
+			}
+		}
+		return nil, false
+	}
+
+	walk := func(e *mergeEdge, reversed bool) geom.LineString {
+		var ls geom.LineString
+		for e != nil {
+			e.used = true
+			vertices, end := e.ls, e.end
+			if reversed {
+				vertices, end = reverseVertices(e.ls), e.start
+			}
+			if len(ls) == 0 {
+				ls = append(ls, vertices...)
+			} else {
+				ls = append(ls, vertices[1:]...)
+			}
+			if !passThrough(end) {
+				break
+			}
+			e, reversed = nextEdge(end)
+		}
+		return ls
+	}
+
+	var merged geom.MultiLineString
+	// Start at the nodes where the lines can not be joined.
+	for n := range snapper.nodes {
+		if passThrough(n) {
+			continue
+		}
+		for e, reversed := nextEdge(n); e != nil; e, reversed = nextEdge(n) {
+			merged = append(merged, walk(e, reversed))
+		}
+	}
+	// What is left are rings where every node joins two lines.
+	for _, e := range edges {
+		if e.used {
+			continue
+		}
+		merged = append(merged, walk(e, false))
+	}
+	return merged
+}
+
+func reverseVertices(vertices [][2]float64) [][2]float64 {
+	rev := make([][2]float64, len(vertices))
+	for i := range vertices {
+		rev[len(vertices)-1-i] = vertices[i]
+	}
+	return rev
+}
@@ -0,0 +1,98 @@
+package planar
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestLineMerge(t *testing.T) {
+	type tcase struct {
+		lines    geom.MultiLineString
+		directed bool
+		expected geom.MultiLineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got := LineMerger{Directed: tc.directed, CMP: cmp.New(0.001)}.Merge(tc.lines)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("number of linestrings, expected %v, got %v: %v", len(tc.expected), len(got), got)
+			}
+			for _, expected := range tc.expected {
+				found := false
+				for _, ls := range got {
+					if cmp.LineStringEqual(expected, ls) || (!tc.directed && cmp.LineStringEqual(expected, reverseVertices(ls))) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("linestrings, expected to find %v in %v", expected, got)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"chain": {
+			lines: geom.MultiLineString{
+				{{2, 0}, {3, 0}},
+				{{0, 0}, {1, 0}},
+				{{2, 0}, {1, 0}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
+			},
+		},
+		"snapped end points": {
+			lines: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1.0000001, 0}, {2, 1}, {3, 1}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {1, 0}, {2, 1}, {3, 1}},
+			},
+		},
+		"junction": {
+			lines: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1, 0}, {2, 0}},
+				{{1, 0}, {1, 1}},
+				{{1, 1}, {1, 2}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1, 0}, {2, 0}},
+				{{1, 0}, {1, 1}, {1, 2}},
+			},
+		},
+		"ring": {
+			lines: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1, 1}, {1, 0}},
+				{{1, 1}, {0, 0}},
+			},
+			expected: geom.MultiLineString{
+				{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+			},
+		},
+		"directed": {
+			lines: geom.MultiLineString{
+				{{0, 0}, {1, 0}},
+				{{1, 0}, {2, 0}},
+				{{3, 0}, {2, 0}},
+			},
+			directed: true,
+			expected: geom.MultiLineString{
+				{{0, 0}, {1, 0}, {2, 0}},
+				{{3, 0}, {2, 0}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,186 @@
+// Package linref provides linear referencing functions, to find and extract
+// locations along a linestring using the fraction of its length or the
+// measure (M) values of its vertices.
+package linref
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+var (
+	// ErrInvalidLineString is returned when the linestring has fewer than two points.
+	ErrInvalidLineString = errors.New("linref: linestring must have at least two points")
+	// ErrInvalidFraction is returned when the fraction is not between 0 and 1.
+	ErrInvalidFraction = errors.New("linref: fraction must be between 0 and 1")
+	// ErrMeasureOutOfRange is returned when no location on the linestring has
+	// the measure.
+	ErrMeasureOutOfRange = errors.New("linref: measure is not within the range of the linestring")
+)
+
+// cumulative returns the distance along the linestring to each vertex
+func cumulative(pts [][2]float64) []float64 {
+	dists := make([]float64, len(pts))
+	for i := 1; i < len(pts); i++ {
+		dists[i] = dists[i-1] + math.Hypot(pts[i][0]-pts[i-1][0], pts[i][1]-pts[i-1][1])
+	}
+	return dists
+}
+
+// lerp linearly interpolates between a and b.
+func lerp(a, b, t float64) float64 { return a + t*(b-a) }
+
+// position is a location on a linestring, given as the segment it is on and
+// how far along the segment (0 to 1) it is.
+type position struct {
+	seg int
+	t   float64
+}
+
+// positionAt returns the position that is the given distance along the line.
+func positionAt(dists []float64, distance float64) position {
+	last := len(dists) - 1
+	if distance <= 0 {
+		return position{seg: 0, t: 0}
+	}
+	if distance >= dists[last] {
+		return position{seg: last - 1, t: 1}
+	}
+	for i := 1; i <= last; i++ {
+		if distance > dists[i] {
+			continue
+		}
+		seglen := dists[i] - dists[i-1]
+		if seglen == 0 {
+			return position{seg: i - 1, t: 0}
+		}
+		return position{seg: i - 1, t: (distance - dists[i-1]) / seglen}
+	}
+	return position{seg: last - 1, t: 1}
+}
+
+func (p position) point(pts [][2]float64) [2]float64 {
+	a, b := pts[p.seg], pts[p.seg+1]
+	return [2]float64{lerp(a[0], b[0], p.t), lerp(a[1], b[1], p.t)}
+}
+
+// closest returns the distance along the line, of the point on the line
+// closest to pt; and the distance from pt to that point.
+func closest(pts [][2]float64, dists []float64, pt [2]float64) (along, distance float64) {
+	distance = math.Inf(1)
+	for i := 0; i < len(pts)-1; i++ {
+		a, b := pts[i], pts[i+1]
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		l2 := dx*dx + dy*dy
+		t := 0.0
+		if l2 != 0 {
+			t = math.Max(0, math.Min(1, ((pt[0]-a[0])*dx+(pt[1]-a[1])*dy)/l2))
+		}
+		d := math.Hypot(pt[0]-(a[0]+t*dx), pt[1]-(a[1]+t*dy))
+		if d < distance {
+			distance = d
+			along = dists[i] + t*(dists[i+1]-dists[i])
+		}
+	}
+	return along, distance
+}
+
+// substring returns the positions along the line between the from and to
+// distances.
+func substring(dists []float64, from, to float64) (start, end position, inner []int) {
+	start, end = positionAt(dists, from), positionAt(dists, to)
+	for i := start.seg + 1; i <= end.seg; i++ {
+		inner = append(inner, i)
+	}
+	return start, end, inner
+}
+
+func checkFraction(fraction float64) error {
+	if !(fraction >= 0 && fraction <= 1) {
+		return ErrInvalidFraction
+	}
+	return nil
+}
+
+// Length returns the length of the linestring
+func Length(ls geom.LineString) float64 {
+	if len(ls) < 2 {
+		return 0
+	}
+	dists := cumulative(ls)
+	return dists[len(dists)-1]
+}
+
+// InterpolatePoint returns the point that is the given fraction (0 to 1) of
+// the length of the linestring from its start.
+func InterpolatePoint(ls geom.LineString, fraction float64) (geom.Point, error) {
+	if len(ls) < 2 {
+		return geom.Point{}, ErrInvalidLineString
+	}
+	if err := checkFraction(fraction); err != nil {
+		return geom.Point{}, err
+	}
+	dists := cumulative(ls)
+	return geom.Point(positionAt(dists, fraction*dists[len(dists)-1]).point(ls)), nil
+}
+
+// LocatePoint returns the location of the point on the linestring closest to
+// pt as a fraction (0 to 1) of the length of the linestring, and the distance
+// from pt to the linestring.
+func LocatePoint(ls geom.LineString, pt geom.Point) (fraction, distance float64, err error) {
+	if len(ls) < 2 {
+		return 0, 0, ErrInvalidLineString
+	}
+	dists := cumulative(ls)
+	along, distance := closest(ls, dists, pt)
+	total := dists[len(dists)-1]
+	if total == 0 {
+		return 0, distance, nil
+	}
+	return along / total, distance, nil
+}
+
+// Substring returns the part of the linestring between the from and to
+// fractions (0 to 1) of its length. If from is larger than to, the returned
+// linestring is reversed.
+func Substring(ls geom.LineString, from, to float64) (geom.LineString, error) {
+	if len(ls) < 2 {
+		return nil, ErrInvalidLineString
+	}
+	if err := checkFraction(from); err != nil {
+		return nil, err
+	}
+	if err := checkFraction(to); err != nil {
+		return nil, err
+	}
+	reverse := from > to
+	if reverse {
+		from, to = to, from
+	}
+
+	dists := cumulative(ls)
+	total := dists[len(dists)-1]
+	start, end, inner := substring(dists, from*total, to*total)
+
+	sub := geom.LineString{start.point(ls)}
+	for _, i := range inner {
+		if ls[i] != sub[len(sub)-1] {
+			sub = append(sub, ls[i])
+		}
+	}
+	if endPt := end.point(ls); len(sub) == 1 || endPt != sub[len(sub)-1] {
+		sub = append(sub, endPt)
+	}
+	if reverse {
+		reverseLineString(sub)
+	}
+	return sub, nil
+}
+
+func reverseLineString[T any](pts []T) {
+	for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+		pts[i], pts[j] = pts[j], pts[i]
+	}
+}
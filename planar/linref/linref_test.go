@@ -0,0 +1,148 @@
+package linref
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+// lshape is 20 units long, 10 along x and then 10 along y.
+var lshape = geom.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+func TestInterpolatePoint(t *testing.T) {
+	type tcase struct {
+		fraction float64
+		expected geom.Point
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := InterpolatePoint(lshape, tc.fraction)
+			if err != tc.err {
+				t.Fatalf("error, expected %v, got %v", tc.err, err)
+			}
+			if got != tc.expected {
+				t.Errorf("point, expected %v, got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"start":   {fraction: 0, expected: geom.Point{0, 0}},
+		"quarter": {fraction: 0.25, expected: geom.Point{5, 0}},
+		"vertex":  {fraction: 0.5, expected: geom.Point{10, 0}},
+		"three q": {fraction: 0.75, expected: geom.Point{10, 5}},
+		"end":     {fraction: 1, expected: geom.Point{10, 10}},
+		"invalid": {fraction: 1.5, err: ErrInvalidFraction},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLocatePoint(t *testing.T) {
+	type tcase struct {
+		pt       geom.Point
+		fraction float64
+		distance float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			fraction, distance, err := LocatePoint(lshape, tc.pt)
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if math.Abs(fraction-tc.fraction) > 1e-9 {
+				t.Errorf("fraction, expected %v, got %v", tc.fraction, fraction)
+			}
+			if math.Abs(distance-tc.distance) > 1e-9 {
+				t.Errorf("distance, expected %v, got %v", tc.distance, distance)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"on line":     {pt: geom.Point{5, 0}, fraction: 0.25},
+		"off line":    {pt: geom.Point{12, 5}, fraction: 0.75, distance: 2},
+		"before":      {pt: geom.Point{-3, -4}, fraction: 0, distance: 5},
+		"inside bend": {pt: geom.Point{8, 1}, fraction: 0.4, distance: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	type tcase struct {
+		from, to float64
+		expected geom.LineString
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Substring(lshape, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("substring, expected %v, got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"whole":    {from: 0, to: 1, expected: lshape},
+		"across":   {from: 0.25, to: 0.75, expected: geom.LineString{{5, 0}, {10, 0}, {10, 5}}},
+		"from vtx": {from: 0.5, to: 0.75, expected: geom.LineString{{10, 0}, {10, 5}}},
+		"reversed": {from: 0.75, to: 0.25, expected: geom.LineString{{10, 5}, {10, 0}, {5, 0}}},
+		"point":    {from: 0.25, to: 0.25, expected: geom.LineString{{5, 0}, {5, 0}}},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	// mileposts 100 to 120 along the l shape.
+	ls := geom.LineStringM{{0, 0, 100}, {10, 0, 110}, {10, 10, 120}}
+
+	pt, err := PointAtMeasure(ls, 115)
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if expected := (geom.PointM{10, 5, 115}); pt != expected {
+		t.Errorf("point at measure, expected %v, got %v", expected, pt)
+	}
+
+	if _, err = PointAtMeasure(ls, 130); err != ErrMeasureOutOfRange {
+		t.Errorf("error, expected %v, got %v", ErrMeasureOutOfRange, err)
+	}
+
+	m, distance, err := LocatePointM(ls, geom.Point{3, 2})
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if m != 103 || distance != 2 {
+		t.Errorf("locate, expected 103, 2, got %v, %v", m, distance)
+	}
+
+	ipt, err := InterpolatePointM(ls, 0.25)
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if expected := (geom.PointM{5, 0, 105}); ipt != expected {
+		t.Errorf("interpolate, expected %v, got %v", expected, ipt)
+	}
+
+	sub, err := SubstringM(ls, 115, 105)
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if expected := (geom.LineStringM{{10, 5, 115}, {10, 0, 110}, {5, 0, 105}}); !reflect.DeepEqual(sub, expected) {
+		t.Errorf("substring, expected %v, got %v", expected, sub)
+	}
+}
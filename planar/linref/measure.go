@@ -0,0 +1,129 @@
+package linref
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// splitM returns the xy values and the measures of the linestring.
+func splitM(ls geom.LineStringM) (pts [][2]float64, ms []float64) {
+	pts = make([][2]float64, len(ls))
+	ms = make([]float64, len(ls))
+	for i := range ls {
+		pts[i] = [2]float64{ls[i][0], ls[i][1]}
+		ms[i] = ls[i][2]
+	}
+	return pts, ms
+}
+
+func (p position) pointM(ls geom.LineStringM) geom.PointM {
+	a, b := ls[p.seg], ls[p.seg+1]
+	return geom.PointM{lerp(a[0], b[0], p.t), lerp(a[1], b[1], p.t), lerp(a[2], b[2], p.t)}
+}
+
+// distanceAtMeasure returns the distance along the line of the first location
+// that has the given measure. The measures are interpolated linearly along
+// each segment.
+func distanceAtMeasure(ms, dists []float64, m float64) (float64, bool) {
+	for i := 0; i < len(ms)-1; i++ {
+		m0, m1 := ms[i], ms[i+1]
+		if m < math.Min(m0, m1) || m > math.Max(m0, m1) {
+			continue
+		}
+		if m0 == m1 {
+			return dists[i], true
+		}
+		return lerp(dists[i], dists[i+1], (m-m0)/(m1-m0)), true
+	}
+	return 0, false
+}
+
+// InterpolatePointM returns the point that is the given fraction (0 to 1) of
+// the 2D length of the linestring from its start. The measure of the point is
+// interpolated from the measures of the vertices on either side.
+func InterpolatePointM(ls geom.LineStringM, fraction float64) (geom.PointM, error) {
+	if len(ls) < 2 {
+		return geom.PointM{}, ErrInvalidLineString
+	}
+	if err := checkFraction(fraction); err != nil {
+		return geom.PointM{}, err
+	}
+	pts, _ := splitM(ls)
+	dists := cumulative(pts)
+	return positionAt(dists, fraction*dists[len(dists)-1]).pointM(ls), nil
+}
+
+// LocatePointM returns the measure of the point on the linestring closest to
+// pt, and the distance from pt to the linestring.
+func LocatePointM(ls geom.LineStringM, pt geom.Point) (m, distance float64, err error) {
+	if len(ls) < 2 {
+		return 0, 0, ErrInvalidLineString
+	}
+	pts, _ := splitM(ls)
+	dists := cumulative(pts)
+	along, distance := closest(pts, dists, pt)
+	return positionAt(dists, along).pointM(ls)[2], distance, nil
+}
+
+// PointAtMeasure returns the first point along the linestring that has the
+// given measure. If the measures of the linestring do not include m,
+// ErrMeasureOutOfRange is returned.
+func PointAtMeasure(ls geom.LineStringM, m float64) (geom.PointM, error) {
+	if len(ls) < 2 {
+		return geom.PointM{}, ErrInvalidLineString
+	}
+	pts, ms := splitM(ls)
+	dists := cumulative(pts)
+	along, ok := distanceAtMeasure(ms, dists, m)
+	if !ok {
+		return geom.PointM{}, ErrMeasureOutOfRange
+	}
+	pt := positionAt(dists, along).pointM(ls)
+	// use the exact measure asked for, rather then the one interpolated
+	// from the distance.
+	pt[2] = m
+	return pt, nil
+}
+
+// SubstringM returns the part of the linestring between the first locations
+// with the from and to measures. If the location of from is after the
+// location of to, the returned linestring is reversed.
+func SubstringM(ls geom.LineStringM, from, to float64) (geom.LineStringM, error) {
+	if len(ls) < 2 {
+		return nil, ErrInvalidLineString
+	}
+	pts, ms := splitM(ls)
+	dists := cumulative(pts)
+	fromDist, ok := distanceAtMeasure(ms, dists, from)
+	if !ok {
+		return nil, ErrMeasureOutOfRange
+	}
+	toDist, ok := distanceAtMeasure(ms, dists, to)
+	if !ok {
+		return nil, ErrMeasureOutOfRange
+	}
+	reverse := fromDist > toDist
+	if reverse {
+		fromDist, toDist = toDist, fromDist
+		from, to = to, from
+	}
+
+	start, end, inner := substring(dists, fromDist, toDist)
+	first, last := start.pointM(ls), end.pointM(ls)
+	first[2], last[2] = from, to
+
+	sub := geom.LineStringM{first}
+	for _, i := range inner {
+		if ls[i] != sub[len(sub)-1] {
+			sub = append(sub, ls[i])
+		}
+	}
+	if len(sub) == 1 || last != sub[len(sub)-1] {
+		sub = append(sub, last)
+	}
+	if reverse {
+		reverseLineString(sub)
+	}
+	return sub, nil
+}
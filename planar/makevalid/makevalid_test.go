@@ -11,6 +11,7 @@ import (
 	"github.com/go-spatial/geom/internal/test/must"
 	"github.com/go-spatial/geom/slippy"
 	"github.com/go-spatial/geom/winding"
+	"github.com/go-spatial/proj"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/planar/makevalid/hitmap"
@@ -152,15 +153,11 @@ func checkMakeValid(tb testing.TB) {
 }
 
 func webMercatorTileExtent(z, x, y uint) *geom.Extent {
-	grid, err := slippy.NewGrid(3857)
+	grid := slippy.NewGrid(proj.WebMercator, 0)
+	ext, err := slippy.Extent(grid, slippy.Tile{Z: slippy.Zoom(z), X: x, Y: y})
 	if err != nil {
 		panic(err)
 	}
 
-	ext, ok := slippy.Extent(grid, slippy.NewTile(z, x, y))
-	if !ok {
-		panic("no tile")
-	}
-
 	return ext
 }
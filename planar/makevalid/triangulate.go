@@ -7,6 +7,7 @@ import (
 	"github.com/go-spatial/geom/encoding/wkt"
 
 	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+	"github.com/go-spatial/geom/winding"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/planar"
@@ -82,3 +83,34 @@ func InsideTrianglesForMultiPolygon(ctx context.Context, clipbox *geom.Extent, m
 	}
 	return triangles, nil
 }
+
+// QualityInsideTrianglesForMultiPolygon is like InsideTrianglesForMultiPolygon, but
+// will refine the triangulation, by adding Steiner points, till the inside
+// triangles meet the given quality constraints. The edges of the multipolygon
+// are kept as edges of the triangulation. If the refinement stops early, the
+// triangles are returned along with delaunay.ErrRefinementIncomplete.
+func QualityInsideTrianglesForMultiPolygon(ctx context.Context, clipbox *geom.Extent, multipolygon *geom.MultiPolygon, hm planar.HitMapper, quality delaunay.Quality) ([]geom.Triangle, error) {
+	segs, err := Destructure(ctx, cmp, clipbox, multipolygon)
+	if err != nil {
+		if debug {
+			log.Printf("Destructure returned err %v", err)
+		}
+		return nil, err
+	}
+	if len(segs) == 0 {
+		return nil, nil
+	}
+	if debug {
+		log.Printf("Step   3 : generate refined triangles")
+	}
+	triangles, err := delaunay.Refine(ctx, winding.Order{}, nil, segs, quality, func(pt [2]float64) bool {
+		return hm.LabelFor(pt) != planar.Outside
+	})
+	if err != nil && err != delaunay.ErrRefinementIncomplete {
+		return nil, err
+	}
+	if len(triangles) == 0 {
+		return nil, err
+	}
+	return triangles, err
+}
@@ -0,0 +1,72 @@
+package makevalid
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/makevalid/hitmap"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+)
+
+func triangleArea(tri geom.Triangle) float64 {
+	return math.Abs((tri[1][0]-tri[0][0])*(tri[2][1]-tri[0][1])-(tri[2][0]-tri[0][0])*(tri[1][1]-tri[0][1])) / 2
+}
+
+func TestQualityInsideTrianglesForMultiPolygon(t *testing.T) {
+	// a square with a square hole; 100 - 16 in area.
+	mp := geom.MultiPolygon{{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		{{3, 3}, {3, 7}, {7, 7}, {7, 3}},
+	}}
+	hm, err := hitmap.NewFromPolygons(nil, mp...)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	ctx := context.Background()
+
+	plain, err := InsideTrianglesForMultiPolygon(ctx, nil, &mp, hm)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	quality := delaunay.Quality{MinAngle: 20, MaxArea: 2}
+	triangles, err := QualityInsideTrianglesForMultiPolygon(ctx, nil, &mp, hm, quality)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(triangles) <= len(plain) {
+		t.Errorf("triangles, expected more than %v got %v", len(plain), len(triangles))
+	}
+
+	var area float64
+	for _, tri := range triangles {
+		a := triangleArea(tri)
+		area += a
+		if a > quality.MaxArea+1e-9 {
+			t.Errorf("triangle %v, expected area at most %v got %v", tri, quality.MaxArea, a)
+		}
+		c := [2]float64{(tri[0][0] + tri[1][0] + tri[2][0]) / 3, (tri[0][1] + tri[1][1] + tri[2][1]) / 3}
+		if c[0] < 0 || c[0] > 10 || c[1] < 0 || c[1] > 10 || (c[0] > 3 && c[0] < 7 && c[1] > 3 && c[1] < 7) {
+			t.Errorf("triangle %v, expected to be inside the polygon", tri)
+		}
+	}
+	if math.Abs(area-84) > 1e-6 {
+		t.Errorf("area, expected 84 got %v", area)
+	}
+
+	empty := geom.MultiPolygon{}
+	if triangles, err := QualityInsideTrianglesForMultiPolygon(ctx, nil, &empty, hm, quality); err != nil || triangles != nil {
+		t.Errorf("empty, expected nil, nil got %v, %v", triangles, err)
+	}
+
+	// the refinement stops after adding a few points.
+	quality.MaxSteinerPoints = 3
+	triangles, err = QualityInsideTrianglesForMultiPolygon(ctx, nil, &mp, hm, quality)
+	if err != delaunay.ErrRefinementIncomplete {
+		t.Errorf("error, expected %v got %v", delaunay.ErrRefinementIncomplete, err)
+	}
+	if len(triangles) == 0 {
+		t.Errorf("triangles, expected the partly refined triangles got none")
+	}
+}
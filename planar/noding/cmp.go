@@ -0,0 +1,7 @@
+package noding
+
+import (
+	pkg "github.com/go-spatial/geom/cmp"
+)
+
+var cmp = pkg.HiCMP
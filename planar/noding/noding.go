@@ -0,0 +1,179 @@
+// Package noding provides functions to split a set of line segments at the
+// points where they intersect, or touch, each other.
+package noding
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+	"github.com/go-spatial/geom/planar/intersect"
+)
+
+// snapper is used to make sure points that are within the tolerance of each
+// other are represented by the same value.
+type snapper struct {
+	cell float64
+	grid map[[2]int64][][2]float64
+}
+
+func newSnapper() *snapper {
+	return &snapper{
+		cell: math.Max(cmp.Tolerance, 1e-12) * 10,
+		grid: make(map[[2]int64][][2]float64),
+	}
+}
+
+func (s *snapper) key(pt [2]float64) [2]int64 {
+	return [2]int64{int64(math.Floor(pt[0] / s.cell)), int64(math.Floor(pt[1] / s.cell))}
+}
+
+// Snap returns the first point seen that is equal to pt, if there isn't one
+// pt is recorded and returned.
+func (s *snapper) Snap(pt [2]float64) [2]float64 {
+	k := s.key(pt)
+	for x := k[0] - 1; x <= k[0]+1; x++ {
+		for y := k[1] - 1; y <= k[1]+1; y++ {
+			for _, spt := range s.grid[[2]int64{x, y}] {
+				if cmp.PointEqual(spt, pt) {
+					return spt
+				}
+			}
+		}
+	}
+	s.grid[k] = append(s.grid[k], pt)
+	return pt
+}
+
+// param returns the position of pt along the segment, where 0 is the start
+// of the segment and 1 is the end.
+func param(seg geom.Line, pt [2]float64) float64 {
+	dx, dy := seg[1][0]-seg[0][0], seg[1][1]-seg[0][1]
+	if math.Abs(dx) > math.Abs(dy) {
+		return (pt[0] - seg[0][0]) / dx
+	}
+	if dy == 0 {
+		return 0
+	}
+	return (pt[1] - seg[0][1]) / dy
+}
+
+// normalize returns the line with the points ordered by x then y.
+func normalize(l geom.Line) geom.Line {
+	if cmp.PointLess(l[1], l[0]) {
+		return geom.Line{l[1], l[0]}
+	}
+	return l
+}
+
+// byXYLine sorts lines by there first point and then by there second point.
+type byXYLine []geom.Line
+
+func (a byXYLine) Len() int      { return len(a) }
+func (a byXYLine) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byXYLine) Less(i, j int) bool {
+	if a[i][0] != a[j][0] {
+		return cmp.PointLess(a[i][0], a[j][0])
+	}
+	return cmp.PointLess(a[i][1], a[j][1])
+}
+
+// Node will split the given segments at every point where they intersect or
+// touch another segment; including where a segment's end point lies on another
+// segment, and where segments overlap. The returned segments only touch
+// each other at their end points. The segments are normalized so the first
+// point is the left most (then bottom most) point, zero length segments are
+// dropped, and duplicate segments are removed. The returned segments are
+// sorted by their points.
+func Node(ctx context.Context, segments []geom.Line) ([]geom.Line, error) {
+	snap := newSnapper()
+	segs := make([]geom.Line, 0, len(segments))
+	for _, seg := range segments {
+		seg = geom.Line{snap.Snap(seg[0]), snap.Snap(seg[1])}
+		if seg[0] == seg[1] {
+			continue
+		}
+		segs = append(segs, seg)
+	}
+	if len(segs) == 0 {
+		return nil, nil
+	}
+
+	// splits holds the points each segment needs to be split at.
+	splits := make([][][2]float64, len(segs))
+	addSplit := func(idx int, pt [2]float64) {
+		seg := segs[idx]
+		if cmp.PointEqual(pt, seg[0]) || cmp.PointEqual(pt, seg[1]) {
+			return
+		}
+		splits[idx] = append(splits[idx], snap.Snap(pt))
+	}
+
+	index := intersect.NewSearchSegmentIdxs(segs)
+	for i := range segs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, j := range index.SearchIntersectIdxs(segs[i]) {
+			if j <= i {
+				continue
+			}
+			// End points that are on the other segment; this deals with
+			// segments that touch and segments that overlap.
+			touched := false
+			for _, pt := range segs[j] {
+				if planar.IsPointOnLineSegment(cmp, geom.Point(pt), segs[i]) {
+					addSplit(i, pt)
+					touched = true
+				}
+			}
+			for _, pt := range segs[i] {
+				if planar.IsPointOnLineSegment(cmp, geom.Point(pt), segs[j]) {
+					addSplit(j, pt)
+					touched = true
+				}
+			}
+			if touched {
+				continue
+			}
+			if pt, ok := planar.SegmentIntersect(segs[i], segs[j]); ok {
+				addSplit(i, pt)
+				addSplit(j, pt)
+			}
+		}
+	}
+
+	noded := make([]geom.Line, 0, len(segs))
+	for i, seg := range segs {
+		pts := append([][2]float64{seg[0], seg[1]}, splits[i]...)
+		sort.Slice(pts, func(a, b int) bool { return param(seg, pts[a]) < param(seg, pts[b]) })
+		for j := 1; j < len(pts); j++ {
+			if pts[j-1] == pts[j] {
+				continue
+			}
+			noded = append(noded, normalize(geom.Line{pts[j-1], pts[j]}))
+		}
+	}
+
+	sort.Sort(byXYLine(noded))
+	uniqued := noded[:0]
+	for i := range noded {
+		if i != 0 && noded[i] == noded[i-1] {
+			continue
+		}
+		uniqued = append(uniqued, noded[i])
+	}
+	return uniqued, nil
+}
+
+// NodeGeometry will node all the line segments that make up the given geometry.
+// See Node for details.
+func NodeGeometry(ctx context.Context, geo geom.Geometry) ([]geom.Line, error) {
+	lines, err := geom.ExtractLines(geo)
+	if err != nil {
+		return nil, err
+	}
+	return Node(ctx, lines)
+}
@@ -0,0 +1,81 @@
+package noding
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestNode(t *testing.T) {
+	type tcase struct {
+		segments []geom.Line
+		expected []geom.Line
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Node(context.Background(), tc.segments)
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("noded segments,\n\texpected %v\n\tgot      %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"crossing": {
+			segments: []geom.Line{
+				{{0, 0}, {10, 10}},
+				{{0, 10}, {10, 0}},
+			},
+			expected: []geom.Line{
+				{{0, 0}, {5, 5}},
+				{{0, 10}, {5, 5}},
+				{{5, 5}, {10, 0}},
+				{{5, 5}, {10, 10}},
+			},
+		},
+		"t junction": {
+			segments: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{5, 5}, {5, 0}},
+			},
+			expected: []geom.Line{
+				{{0, 0}, {5, 0}},
+				{{5, 0}, {5, 5}},
+				{{5, 0}, {10, 0}},
+			},
+		},
+		"overlapping and duplicates": {
+			segments: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{15, 0}, {5, 0}},
+				{{10, 0}, {0, 0}},
+				{{3, 3}, {3, 3}},
+			},
+			expected: []geom.Line{
+				{{0, 0}, {5, 0}},
+				{{5, 0}, {10, 0}},
+				{{10, 0}, {15, 0}},
+			},
+		},
+		"disjoint": {
+			segments: []geom.Line{
+				{{0, 0}, {1, 1}},
+				{{5, 5}, {6, 5}},
+			},
+			expected: []geom.Line{
+				{{0, 0}, {1, 1}},
+				{{5, 5}, {6, 5}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,313 @@
+// Package polygonize builds polygons from a set of lines, where the lines
+// form the edges of the polygons.
+package polygonize
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/intersect"
+	"github.com/go-spatial/geom/planar/noding"
+	"github.com/go-spatial/geom/winding"
+)
+
+// Result is the result of polygonizing a set of lines.
+type Result struct {
+	// Polygons are the faces formed by the lines. The exterior ring of each
+	// polygon is clockwise, and the interior rings are counter clockwise.
+	Polygons []geom.Polygon
+	// Dangles are the segments that have an end point that is not
+	// connected to any other segment, these can not form part of a polygon.
+	Dangles []geom.Line
+	// CutEdges are the segments that are connected at both ends, but have
+	// the same face on both sides; for example a segment connecting two
+	// otherwise separate polygons.
+	CutEdges []geom.Line
+	// InvalidRings are closed rings that could not be turned into a polygon,
+	// because they do not enclose any area.
+	InvalidRings []geom.LineString
+}
+
+// edge is a directed edge in the planar graph.
+type edge struct {
+	from, to int
+	// angle of the edge from the from node.
+	angle float64
+	// sym is the index of the edge going in the opposite direction.
+	sym int
+	// ring is the index of the ring the edge belongs to, -1 if not yet
+	// assigned.
+	ring    int
+	removed bool
+}
+
+type graph struct {
+	nodes [][2]float64
+	edges []edge
+	// out are the indexes of the outgoing edges for each node, sorted
+	// counter clockwise by angle.
+	out [][]int
+}
+
+func newGraph(segs []geom.Line) *graph {
+	g := graph{}
+	nodeIdx := make(map[[2]float64]int, len(segs))
+	node := func(pt [2]float64) int {
+		if idx, ok := nodeIdx[pt]; ok {
+			return idx
+		}
+		nodeIdx[pt] = len(g.nodes)
+		g.nodes = append(g.nodes, pt)
+		g.out = append(g.out, nil)
+		return len(g.nodes) - 1
+	}
+	for _, seg := range segs {
+		a, b := node(seg[0]), node(seg[1])
+		idx := len(g.edges)
+		g.edges = append(g.edges,
+			edge{from: a, to: b, sym: idx + 1, ring: -1, angle: math.Atan2(seg[1][1]-seg[0][1], seg[1][0]-seg[0][0])},
+			edge{from: b, to: a, sym: idx, ring: -1, angle: math.Atan2(seg[0][1]-seg[1][1], seg[0][0]-seg[1][0])},
+		)
+		g.out[a] = append(g.out[a], idx)
+		g.out[b] = append(g.out[b], idx+1)
+	}
+	for i := range g.out {
+		out := g.out[i]
+		sort.Slice(out, func(a, b int) bool { return g.edges[out[a]].angle < g.edges[out[b]].angle })
+	}
+	return &g
+}
+
+// degree returns the number of edges, that have not been removed, at the node.
+func (g *graph) degree(node int) (count int) {
+	for _, e := range g.out[node] {
+		if !g.edges[e].removed {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *graph) line(e int) geom.Line {
+	return geom.Line{g.nodes[g.edges[e].from], g.nodes[g.edges[e].to]}
+}
+
+// removeEdge removes the edge and its sym edge from the graph.
+func (g *graph) removeEdge(e int) {
+	g.edges[e].removed = true
+	g.edges[g.edges[e].sym].removed = true
+}
+
+// removeDangles removes edges that have an end that is not connected to
+// any other edges, returning the removed edges.
+func (g *graph) removeDangles() (dangles []geom.Line) {
+	var stack []int
+	for n := range g.nodes {
+		if g.degree(n) == 1 {
+			stack = append(stack, n)
+		}
+	}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range g.out[n] {
+			if g.edges[e].removed {
+				continue
+			}
+			dangles = append(dangles, g.line(e))
+			g.removeEdge(e)
+			if to := g.edges[e].to; g.degree(to) == 1 {
+				stack = append(stack, to)
+			}
+		}
+	}
+	return dangles
+}
+
+// next returns the edge that follows e in a face. The face is on the
+// left of the edges.
+func (g *graph) next(e int) int {
+	sym := g.edges[e].sym
+	out := g.out[g.edges[e].to]
+	pos := 0
+	for i := range out {
+		if out[i] == sym {
+			pos = i
+			break
+		}
+	}
+	// Look for the first edge, that has not been removed, going clockwise
+	// from the sym edge.
+	for i := 1; i <= len(out); i++ {
+		cand := out[(pos-i+len(out))%len(out)]
+		if !g.edges[cand].removed {
+			return cand
+		}
+	}
+	return sym
+}
+
+// rings traces all the rings in the graph, labeling each edge with the ring
+// it belongs to.
+func (g *graph) rings() (rings [][]int) {
+	for i := range g.edges {
+		g.edges[i].ring = -1
+	}
+	for i := range g.edges {
+		if g.edges[i].removed || g.edges[i].ring != -1 {
+			continue
+		}
+		var ring []int
+		for e := i; g.edges[e].ring == -1; e = g.next(e) {
+			g.edges[e].ring = len(rings)
+			ring = append(ring, e)
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// removeCutEdges removes edges that have the same ring on both sides.
+func (g *graph) removeCutEdges() (cutEdges []geom.Line) {
+	for i := range g.edges {
+		e := g.edges[i]
+		if e.removed || i > e.sym {
+			continue
+		}
+		if e.ring == g.edges[e.sym].ring {
+			cutEdges = append(cutEdges, g.line(i))
+			g.removeEdge(i)
+		}
+	}
+	return cutEdges
+}
+
+func (g *graph) ringPoints(ring []int) [][2]float64 {
+	pts := make([][2]float64, len(ring))
+	for i, e := range ring {
+		pts[i] = g.nodes[g.edges[e].from]
+	}
+	return pts
+}
+
+// signedArea returns the area of the ring, positive if the ring is
+// counter clockwise.
+func signedArea(pts [][2]float64) (area float64) {
+	for i := range pts {
+		j := (i + 1) % len(pts)
+		area += pts[i][0]*pts[j][1] - pts[j][0]*pts[i][1]
+	}
+	return area / 2
+}
+
+type shell struct {
+	pts    [][2]float64
+	area   float64
+	extent *geom.Extent
+	ring   *intersect.Ring
+	holes  [][][2]float64
+}
+
+// contains returns whether the shell contains the hole, pts.
+func (s *shell) contains(pts [][2]float64, extent *geom.Extent) bool {
+	if !s.extent.Contains(extent) {
+		return false
+	}
+	vertices := make(map[[2]float64]bool, len(s.pts))
+	for _, pt := range s.pts {
+		vertices[pt] = true
+	}
+	for _, pt := range pts {
+		if vertices[pt] {
+			continue
+		}
+		return s.ring.ContainsPoint(pt)
+	}
+	// All the points are the same as the shell.
+	return false
+}
+
+// Polygonize will node the given lines, and build all the polygons formed by
+// the lines. Lines that could not be used to form a polygon are returned as
+// dangles, cut edges or invalid rings. Use geom.ExtractLines to get the lines
+// of a geometry.
+func Polygonize(ctx context.Context, lines []geom.Line) (*Result, error) {
+	segs, err := noding.Node(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+	var result Result
+	if len(segs) == 0 {
+		return &result, nil
+	}
+
+	g := newGraph(segs)
+	result.Dangles = g.removeDangles()
+
+	var rings [][]int
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rings = g.rings()
+		cut := g.removeCutEdges()
+		if len(cut) == 0 {
+			break
+		}
+		result.CutEdges = append(result.CutEdges, cut...)
+		// removing cut edges can create new dangles.
+		result.Dangles = append(result.Dangles, g.removeDangles()...)
+	}
+
+	// Rings with the face on the left (counter clockwise) are the shells,
+	// clockwise rings are the outer boundary of a connected set of
+	// edges, and are holes in the shell that contains them.
+	var (
+		shells []*shell
+		holes  [][][2]float64
+	)
+	for _, ring := range rings {
+		pts := g.ringPoints(ring)
+		area := signedArea(pts)
+		switch {
+		case len(pts) < 3 || area == 0:
+			result.InvalidRings = append(result.InvalidRings, append(geom.LineString(pts), pts[0]))
+		case area > 0:
+			shells = append(shells, &shell{
+				pts:    pts,
+				area:   area,
+				extent: geom.NewExtent(pts...),
+				ring:   intersect.NewRingFromPoints(pts...),
+			})
+		default:
+			holes = append(holes, pts)
+		}
+	}
+
+	// holes go into the smallest shell that contains them.
+	sort.Slice(shells, func(i, j int) bool { return shells[i].area < shells[j].area })
+	for _, hole := range holes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		extent := geom.NewExtent(hole...)
+		for _, s := range shells {
+			if s.contains(hole, extent) {
+				s.holes = append(s.holes, hole)
+				break
+			}
+		}
+	}
+
+	order := winding.Order{}
+	for _, s := range shells {
+		plyg := order.RectifyPolygon(append([][][2]float64{s.pts}, s.holes...))
+		if len(plyg) == 0 {
+			continue
+		}
+		result.Polygons = append(result.Polygons, geom.Polygon(plyg))
+	}
+	return &result, nil
+}
@@ -0,0 +1,108 @@
+package polygonize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+func TestPolygonize(t *testing.T) {
+	type tcase struct {
+		lines    []geom.Line
+		polygons []geom.Polygon
+		dangles  int
+		cutEdges int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Polygonize(context.Background(), tc.lines)
+			if err != nil {
+				t.Fatalf("error, expected nil, got %v", err)
+			}
+			if len(got.Polygons) != len(tc.polygons) {
+				t.Fatalf("number of polygons, expected %v, got %v: %v", len(tc.polygons), len(got.Polygons), got.Polygons)
+			}
+			for _, expected := range tc.polygons {
+				found := false
+				for _, plyg := range got.Polygons {
+					if cmp.PolygonEqual(expected, plyg) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("polygons, expected to find %v in %v", expected, got.Polygons)
+				}
+			}
+			if len(got.Dangles) != tc.dangles {
+				t.Errorf("dangles, expected %v, got %v: %v", tc.dangles, len(got.Dangles), got.Dangles)
+			}
+			if len(got.CutEdges) != tc.cutEdges {
+				t.Errorf("cut edges, expected %v, got %v: %v", tc.cutEdges, len(got.CutEdges), got.CutEdges)
+			}
+		}
+	}
+
+	square := func(minx, miny, maxx, maxy float64) []geom.Line {
+		return []geom.Line{
+			{{minx, miny}, {maxx, miny}},
+			{{maxx, miny}, {maxx, maxy}},
+			{{maxx, maxy}, {minx, maxy}},
+			{{minx, maxy}, {minx, miny}},
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {},
+		"square": {
+			lines: square(0, 0, 10, 10),
+			polygons: []geom.Polygon{
+				{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}},
+			},
+		},
+		"crossing lines with dangles": {
+			// a # shape forms a single square in the middle.
+			lines: []geom.Line{
+				{{0, 3}, {10, 3}},
+				{{0, 6}, {10, 6}},
+				{{3, 0}, {3, 10}},
+				{{6, 0}, {6, 10}},
+			},
+			polygons: []geom.Polygon{
+				{{{3, 3}, {3, 6}, {6, 6}, {6, 3}}},
+			},
+			dangles: 8,
+		},
+		"square with hole": {
+			lines: append(square(0, 0, 10, 10), square(4, 4, 6, 6)...),
+			polygons: []geom.Polygon{
+				{
+					{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+					{{4, 4}, {6, 4}, {6, 6}, {4, 6}},
+				},
+				{{{4, 4}, {4, 6}, {6, 6}, {6, 4}}},
+			},
+		},
+		"cut edge": {
+			lines: append(append(square(0, 0, 2, 2), square(5, 0, 7, 2)...), geom.Line{{2, 1}, {5, 1}}),
+			polygons: []geom.Polygon{
+				{{{0, 0}, {0, 2}, {2, 2}, {2, 1}, {2, 0}}},
+				{{{5, 0}, {5, 1}, {5, 2}, {7, 2}, {7, 0}}},
+			},
+			cutEdges: 1,
+		},
+		"shared edge": {
+			lines: append(square(0, 0, 1, 1), square(1, 0, 2, 1)...),
+			polygons: []geom.Polygon{
+				{{{0, 0}, {0, 1}, {1, 1}, {1, 0}}},
+				{{{1, 0}, {1, 1}, {2, 1}, {2, 0}}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
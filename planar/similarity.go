@@ -0,0 +1,24 @@
+package planar
+
+import (
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/similarity"
+)
+
+// HausdorffDistance returns the Hausdorff distance between the two geometries;
+// the largest distance from a point on either geometry to the closest point
+// on the other geometry. Distances are measured from the vertices of each
+// geometry to the segments of the other. If densifyFraction is between 0 and
+// 1, each segment is split into parts that are that fraction of the segment
+// to add vertices to measure from; giving a more accurate result. A
+// densifyFraction of 0 does not add any vertices.
+func HausdorffDistance(a, b geom.Geometry, densifyFraction float64) (float64, error) {
+	return similarity.HausdorffDistance(a, b, densifyFraction)
+}
+
+// FrechetDistance returns the discrete Fréchet distance between the vertices
+// of the two geometries. It takes the order of the vertices into account, so
+// is most useful for comparing linestrings.
+func FrechetDistance(a, b geom.Geometry) (float64, error) {
+	return similarity.FrechetDistance(a, b)
+}
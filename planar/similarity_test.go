@@ -0,0 +1,70 @@
+package planar
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+)
+
+// TestGeometryNear checks cmp.GeometryNear, which uses HausdorffDistance;
+// it is here as the cmp tests depend on generated test data.
+func TestGeometryNear(t *testing.T) {
+	type tcase struct {
+		g1, g2   geom.Geometry
+		maxDist  float64
+		expected bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := cmp.HiCMP.GeometryNear(tc.g1, tc.g2, tc.maxDist); got != tc.expected {
+				t.Errorf("near, expected %v, got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"rotated ring": {
+			g1:       geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			g2:       geom.Polygon{{{10, 10}, {0, 10}, {0, 0}, {10, 0}}},
+			expected: true,
+		},
+		"simplified within": {
+			g1:       geom.LineString{{0, 0}, {5, 0.5}, {10, 0}},
+			g2:       geom.LineString{{0, 0}, {10, 0}},
+			maxDist:  1,
+			expected: true,
+		},
+		"simplified outside": {
+			g1:       geom.LineString{{0, 0}, {5, 2}, {10, 0}},
+			g2:       geom.LineString{{0, 0}, {10, 0}},
+			maxDist:  1,
+			expected: false,
+		},
+		"different types": {
+			g1:       geom.MultiPoint{{0, 0}, {10, 0}},
+			g2:       geom.LineString{{0, 0}, {10, 0}},
+			maxDist:  5,
+			expected: true,
+		},
+		"same srid": {
+			g1:       geom.PointS{Srid: 4326, Xy: geom.Point{0, 0}},
+			g2:       geom.PointS{Srid: 4326, Xy: geom.Point{0, 1}},
+			maxDist:  1,
+			expected: true,
+		},
+		"different srid": {
+			g1:      geom.PointS{Srid: 4326, Xy: geom.Point{0, 0}},
+			g2:      geom.PointS{Srid: 3857, Xy: geom.Point{0, 0}},
+			maxDist: 1,
+		},
+		"unknown geometry": {
+			g1: geom.Point{0, 0},
+			g2: "point",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,375 @@
+package delaunay
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/rtreego"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+	"github.com/go-spatial/geom/winding"
+)
+
+// DefaultMaxSteinerPoints is the number of points Refine will add, if
+// Quality.MaxSteinerPoints is not set.
+const DefaultMaxSteinerPoints = 100000
+
+// ErrRefinementIncomplete is returned by Refine, along with the triangles,
+// when it stopped before every inside triangle met the quality constraints
+// and every segment was an edge of the triangulation. This happens when
+// MaxSteinerPoints is reached, a segment is too short to split, or there is
+// no point that can be added to fix the remaining triangles; such as for
+// angles between the segments that are smaller than MinAngle.
+var ErrRefinementIncomplete = errors.New("delaunay: refinement incomplete")
+
+// Quality describes the constraints on the shape and size of the triangles
+// produced by Refine.
+type Quality struct {
+	// MinAngle is the smallest angle, in degrees, allowed in a triangle.
+	// Refinement is only guaranteed to terminate for angles up to about
+	// 20.7 degrees; larger values rely on MaxSteinerPoints to stop. A value
+	// of zero or less disables the angle constraint.
+	MinAngle float64
+	// MaxArea is the largest area allowed for a triangle. A value of zero or
+	// less disables the area constraint.
+	MaxArea float64
+	// MaxSteinerPoints is the maximum number of points that will be added
+	// to the triangulation. If zero DefaultMaxSteinerPoints is used.
+	MaxSteinerPoints int
+}
+
+// isBad returns whether the triangle fails the quality constraints.
+func (q Quality) isBad(tri geom.Triangle) bool {
+	if q.MaxArea > 0 && math.Abs(tri.Area())/2 > q.MaxArea {
+		return true
+	}
+	if q.MinAngle <= 0 {
+		return false
+	}
+	return minAngle(tri) < q.MinAngle*math.Pi/180
+}
+
+// minAngle returns the smallest angle, in radians, of the triangle.
+func minAngle(tri geom.Triangle) float64 {
+	min := math.Pi
+	for i := 0; i < 3; i++ {
+		a, b, c := tri[i], tri[(i+1)%3], tri[(i+2)%3]
+		u := [2]float64{b[0] - a[0], b[1] - a[1]}
+		v := [2]float64{c[0] - a[0], c[1] - a[1]}
+		angle := math.Abs(math.Atan2(u[0]*v[1]-u[1]*v[0], u[0]*v[0]+u[1]*v[1]))
+		if angle < min {
+			min = angle
+		}
+	}
+	return min
+}
+
+// circumcenter returns the center of the circle going through all three
+// points of the triangle. ok is false if the triangle is degenerate.
+func circumcenter(tri geom.Triangle) (center [2]float64, ok bool) {
+	a, b, c := tri[0], tri[1], tri[2]
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	d := 2 * (bx*cy - by*cx)
+	if d == 0 {
+		return center, false
+	}
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	return [2]float64{
+		a[0] + (cy*b2-by*c2)/d,
+		a[1] + (bx*c2-cx*b2)/d,
+	}, true
+}
+
+// encroaches returns whether pt is strictly inside the diametral circle of
+// the segment.
+func encroaches(seg geom.Line, pt [2]float64) bool {
+	if pt == seg[0] || pt == seg[1] {
+		return false
+	}
+	ax, ay := seg[0][0]-pt[0], seg[0][1]-pt[1]
+	bx, by := seg[1][0]-pt[0], seg[1][1]-pt[1]
+	return ax*bx+ay*by < 0
+}
+
+func roundPoint(pt [2]float64) [2]float64 {
+	return [2]float64{
+		math.Round(pt[0]*subdivision.RoundingFactor) / subdivision.RoundingFactor,
+		math.Round(pt[1]*subdivision.RoundingFactor) / subdivision.RoundingFactor,
+	}
+}
+
+type vertexRect struct {
+	pt   [2]float64
+	rect *rtreego.Rect
+}
+
+func (vr *vertexRect) Bounds() *rtreego.Rect { return vr.rect }
+
+// refiner holds the state used while refining a triangulation.
+type refiner struct {
+	sd       *subdivision.Subdivision
+	quality  Quality
+	segments []geom.Line
+	vertices *rtreego.Rtree
+	added    int
+	// minLength is the length below which a segment will not be split.
+	minLength float64
+	// extent is the extent of the original points and segments, no
+	// points are added outside of it.
+	extent *geom.Extent
+}
+
+func (r *refiner) insert(pt [2]float64) bool {
+	pt = roundPoint(pt)
+	if len(r.vertices.SearchIntersect(rtreego.Point{pt[0], pt[1]}.ToRect(tinEpsilon))) != 0 {
+		// already in the triangulation.
+		return false
+	}
+	if !r.sd.InsertSite(geom.Point(pt)) {
+		return false
+	}
+	r.vertices.Insert(&vertexRect{pt: pt, rect: rtreego.Point{pt[0], pt[1]}.ToRect(tinEpsilon)})
+	r.added++
+	return true
+}
+
+// encroachedBy returns the index of the segments encroached by the given point.
+func (r *refiner) encroachedBy(pt [2]float64) (idxs []int) {
+	for i, seg := range r.segments {
+		if encroaches(seg, pt) {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// isEncroached returns whether any vertex of the triangulation is inside the
+// diametral circle of the segment.
+func (r *refiner) isEncroached(seg geom.Line) bool {
+	mid := [2]float64{(seg[0][0] + seg[1][0]) / 2, (seg[0][1] + seg[1][1]) / 2}
+	radius := math.Sqrt(seg.LengthSquared()) / 2
+	for _, s := range r.vertices.SearchIntersect(rtreego.Point{mid[0], mid[1]}.ToRect(radius)) {
+		vr, ok := s.(*vertexRect)
+		if !ok {
+			continue
+		}
+		if encroaches(seg, vr.pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// split splits the segment at idx at its midpoint, returns false if the
+// segment is too small to split.
+func (r *refiner) split(idx int) bool {
+	seg := r.segments[idx]
+	if math.Sqrt(seg.LengthSquared()) < r.minLength {
+		return false
+	}
+	mid := roundPoint([2]float64{(seg[0][0] + seg[1][0]) / 2, (seg[0][1] + seg[1][1]) / 2})
+	if mid == seg[0] || mid == seg[1] {
+		return false
+	}
+	r.segments[idx] = geom.Line{seg[0], mid}
+	r.segments = append(r.segments, geom.Line{mid, seg[1]})
+	r.insert(mid)
+	return true
+}
+
+func (r *refiner) done() bool { return r.added >= r.quality.MaxSteinerPoints }
+
+// splitEncroached splits all the segments that are encroached, till no segments
+// are encroached.
+func (r *refiner) splitEncroached(ctx context.Context) (didSplit bool, err error) {
+	for changed := true; changed && !r.done(); {
+		changed = false
+		for i := 0; i < len(r.segments) && !r.done(); i++ {
+			if ctx.Err() != nil {
+				return didSplit, ctx.Err()
+			}
+			if !r.isEncroached(r.segments[i]) {
+				continue
+			}
+			if r.split(i) {
+				changed, didSplit = true, true
+			}
+		}
+	}
+	return didSplit, nil
+}
+
+// Refine will triangulate the given points and segments, adding Steiner points
+// until every triangle whose center is inside, meets the given quality
+// constraints. Only the inside triangles are returned. The segments are kept
+// as edges of the triangulation by splitting them, so the returned
+// triangulation is a conforming Delaunay triangulation. If inside is nil, all
+// triangles are refined and returned.
+//
+// If the refinement stops early the triangles are returned along with
+// ErrRefinementIncomplete; they may not meet the quality constraints, and
+// may cross the segments.
+//
+// The refinement follows Ruppert's algorithm: encroached segments are split
+// at their midpoint, and bad triangles have their circumcenter inserted,
+// unless it would encroach a segment, in which case the segment is split
+// instead.
+func Refine(ctx context.Context, order winding.Order, pts [][2]float64, segments []geom.Line, quality Quality, inside func(pt [2]float64) bool) ([]geom.Triangle, error) {
+	if inside == nil {
+		inside = func([2]float64) bool { return true }
+	}
+	if quality.MaxSteinerPoints <= 0 {
+		quality.MaxSteinerPoints = DefaultMaxSteinerPoints
+	}
+
+	r := refiner{
+		quality:   quality,
+		minLength: 4.0 / subdivision.RoundingFactor,
+	}
+
+	var allPts [][2]float64
+	for _, pt := range pts {
+		allPts = append(allPts, roundPoint(pt))
+	}
+	for _, seg := range segments {
+		seg = geom.Line{roundPoint(seg[0]), roundPoint(seg[1])}
+		if seg[0] == seg[1] {
+			continue
+		}
+		allPts = append(allPts, seg[0], seg[1])
+		r.segments = append(r.segments, seg)
+	}
+	if len(allPts) == 0 {
+		return nil, nil
+	}
+	r.extent = geom.NewExtent(allPts...)
+
+	var err error
+	// NewForPoints modifies the slice it is given, so we give it a copy.
+	r.sd, err = subdivision.NewForPoints(ctx, order, append([][2]float64(nil), allPts...))
+	if err != nil {
+		return nil, err
+	}
+
+	vertices := make([]rtreego.Spatial, 0, len(allPts))
+	seen := make(map[[2]float64]bool, len(allPts))
+	for _, pt := range allPts {
+		if seen[pt] {
+			continue
+		}
+		seen[pt] = true
+		vertices = append(vertices, &vertexRect{pt: pt, rect: rtreego.Point{pt[0], pt[1]}.ToRect(tinEpsilon)})
+	}
+	r.vertices = rtreego.NewTree(2, 25, 50, vertices...)
+
+	for !r.done() {
+		if _, err := r.splitEncroached(ctx); err != nil {
+			return nil, err
+		}
+
+		tris, err := r.triangles(inside)
+		if err != nil {
+			return nil, err
+		}
+
+		var bad []geom.Triangle
+		for _, tri := range tris {
+			if r.quality.isBad(tri) {
+				bad = append(bad, tri)
+			}
+		}
+		if len(bad) == 0 {
+			break
+		}
+		// Work on the worst triangles first.
+		sort.Slice(bad, func(i, j int) bool { return minAngle(bad[i]) < minAngle(bad[j]) })
+
+		type circle struct {
+			center [2]float64
+			radius float64
+		}
+		var (
+			inserted []circle
+			progress bool
+		)
+	BadTriangles:
+		for _, tri := range bad {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if r.done() {
+				break
+			}
+			center, ok := circumcenter(tri)
+			if !ok {
+				continue
+			}
+			radius := math.Hypot(center[0]-tri[0][0], center[1]-tri[0][1])
+			// Don't insert points that are too close to points we
+			// already inserted in this pass; the triangulation around
+			// them has changed.
+			for _, c := range inserted {
+				if math.Hypot(center[0]-c.center[0], center[1]-c.center[1]) < math.Max(radius, c.radius) {
+					continue BadTriangles
+				}
+			}
+			if idxs := r.encroachedBy(center); len(idxs) != 0 {
+				for _, idx := range idxs {
+					if r.split(idx) {
+						progress = true
+					}
+				}
+				continue
+			}
+			if !r.extent.ContainsPoint(center) || !inside(center) {
+				// The circumcenter is not part of the area being
+				// refined and does not encroach on a segment; there
+				// is nothing we can do for this triangle.
+				continue
+			}
+			if r.insert(center) {
+				inserted = append(inserted, circle{center: center, radius: radius})
+				progress = true
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+
+	tris, err := r.triangles(inside)
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range r.segments {
+		if r.isEncroached(seg) {
+			return tris, ErrRefinementIncomplete
+		}
+	}
+	for _, tri := range tris {
+		if r.quality.isBad(tri) {
+			return tris, ErrRefinementIncomplete
+		}
+	}
+	return tris, nil
+}
+
+// triangles returns the triangles in the subdivision that are inside.
+func (r *refiner) triangles(inside func([2]float64) bool) ([]geom.Triangle, error) {
+	triangles, err := r.sd.Triangles(false)
+	if err != nil {
+		return nil, err
+	}
+	tris := make([]geom.Triangle, 0, len(triangles))
+	for _, tri := range triangles {
+		gtri := geom.Triangle{[2]float64(tri[0]), [2]float64(tri[1]), [2]float64(tri[2])}
+		if !inside(gtri.Center()) {
+			continue
+		}
+		tris = append(tris, gtri)
+	}
+	return tris, nil
+}
@@ -0,0 +1,129 @@
+package delaunay_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+	"github.com/go-spatial/geom/winding"
+)
+
+func triangleMinAngle(tri geom.Triangle) float64 {
+	min := 180.0
+	for i := 0; i < 3; i++ {
+		a, b, c := tri[i], tri[(i+1)%3], tri[(i+2)%3]
+		ab := math.Atan2(b[1]-a[1], b[0]-a[0])
+		ac := math.Atan2(c[1]-a[1], c[0]-a[0])
+		angle := math.Abs(ab-ac) * 180 / math.Pi
+		if angle > 180 {
+			angle = 360 - angle
+		}
+		min = math.Min(min, angle)
+	}
+	return min
+}
+
+func TestRefine(t *testing.T) {
+	type tcase struct {
+		segments []geom.Line
+		quality  delaunay.Quality
+		inside   func([2]float64) bool
+		area     float64
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			ctx := context.Background()
+			tris, err := delaunay.Refine(ctx, winding.Order{}, nil, tc.segments, tc.quality, tc.inside)
+			if err != tc.err {
+				t.Fatalf("error, expected %v, got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				if len(tris) == 0 {
+					t.Errorf("triangles, expected the partly refined triangles got none")
+				}
+				return
+			}
+			var area float64
+			for _, tri := range tris {
+				triArea := math.Abs(tri.Area()) / 2
+				area += triArea
+				if tc.quality.MaxArea > 0 && triArea > tc.quality.MaxArea {
+					t.Errorf("triangle %v area, expected <= %v, got %v", tri, tc.quality.MaxArea, triArea)
+				}
+				if angle := triangleMinAngle(tri); angle < tc.quality.MinAngle {
+					t.Errorf("triangle %v min angle, expected >= %v, got %v", tri, tc.quality.MinAngle, angle)
+				}
+			}
+			if math.Abs(area-tc.area) > 1e-6 {
+				t.Errorf("area, expected %v, got %v", tc.area, area)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"sliver": {
+			segments: []geom.Line{
+				{{0, 0}, {20, 0}},
+				{{20, 0}, {20, 1}},
+				{{20, 1}, {0, 1}},
+				{{0, 1}, {0, 0}},
+			},
+			quality: delaunay.Quality{MinAngle: 20},
+			area:    20,
+		},
+		"square max area": {
+			segments: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{10, 0}, {10, 10}},
+				{{10, 10}, {0, 10}},
+				{{0, 10}, {0, 0}},
+			},
+			quality: delaunay.Quality{MinAngle: 20, MaxArea: 2},
+			area:    100,
+		},
+		"square with hole": {
+			segments: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{10, 0}, {10, 10}},
+				{{10, 10}, {0, 10}},
+				{{0, 10}, {0, 0}},
+				{{4, 4}, {6, 4}},
+				{{6, 4}, {6, 6}},
+				{{6, 6}, {4, 6}},
+				{{4, 6}, {4, 4}},
+			},
+			quality: delaunay.Quality{MinAngle: 25, MaxArea: 5},
+			inside: func(pt [2]float64) bool {
+				return !(pt[0] > 4 && pt[0] < 6 && pt[1] > 4 && pt[1] < 6)
+			},
+			area: 96,
+		},
+		"too few steiner points": {
+			segments: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{10, 0}, {10, 10}},
+				{{10, 10}, {0, 10}},
+				{{0, 10}, {0, 0}},
+			},
+			quality: delaunay.Quality{MinAngle: 20, MaxArea: 2, MaxSteinerPoints: 5},
+			err:     delaunay.ErrRefinementIncomplete,
+		},
+		"small input angle": {
+			// the angle at the origin can not be made larger.
+			segments: []geom.Line{
+				{{0, 0}, {10, 0}},
+				{{10, 0}, {10, 1}},
+				{{10, 1}, {0, 0}},
+			},
+			quality: delaunay.Quality{MinAngle: 20},
+			err:     delaunay.ErrRefinementIncomplete,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
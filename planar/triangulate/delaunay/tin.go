@@ -0,0 +1,332 @@
+package delaunay
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/rtreego"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay/subdivision"
+	"github.com/go-spatial/geom/winding"
+)
+
+// tinEpsilon is the tolerance used when deciding if a point lies within a
+// triangle of the TIN.
+const tinEpsilon = 1e-9
+
+// TIN is a triangulated irregular network. It is a Delaunay triangulation
+// of a set of PointZ samples, where each vertex of the triangulation keeps
+// the Z value of the sample it was created from.
+type TIN struct {
+	triangles [][3]geom.PointZ
+	tree      *rtreego.Rtree
+}
+
+type tinTriangle struct {
+	idx  int
+	rect *rtreego.Rect
+}
+
+func (tt *tinTriangle) Bounds() *rtreego.Rect { return tt.rect }
+
+// NewTIN will triangulate the given points using the x and y values, and carry
+// the z value over to the vertices of the resulting triangles. Points are
+// snapped to the precision of the subdivision (subdivision.RoundingFactor);
+// if more than one point snaps to the same location the first point's z value
+// is used.
+func NewTIN(ctx context.Context, order winding.Order, pts []geom.PointZ) (*TIN, error) {
+	if len(pts) < 3 {
+		return &TIN{}, nil
+	}
+
+	xy := make([][2]float64, len(pts))
+	for i := range pts {
+		xy[i] = [2]float64{pts[i][0], pts[i][1]}
+	}
+
+	// NewForPoints rounds the given points in place, so after this call
+	// xy[i] is the rounded location of pts[i].
+	sd, err := subdivision.NewForPoints(ctx, order, xy)
+	if err != nil {
+		return nil, err
+	}
+
+	zs := make(map[[2]float64]float64, len(xy))
+	for i := range xy {
+		if _, ok := zs[xy[i]]; ok {
+			continue
+		}
+		zs[xy[i]] = pts[i][2]
+	}
+
+	tris, err := sd.Triangles(false)
+	if err != nil {
+		return nil, err
+	}
+
+	tin := TIN{
+		triangles: make([][3]geom.PointZ, 0, len(tris)),
+	}
+	for _, tri := range tris {
+		var ztri [3]geom.PointZ
+		for i := range tri {
+			z, ok := zs[[2]float64(tri[i])]
+			if !ok {
+				// A vertex we did not add; only frame vertices should do this
+				// and those triangles have already been removed.
+				z = math.NaN()
+			}
+			ztri[i] = geom.PointZ{tri[i][0], tri[i][1], z}
+		}
+		tin.triangles = append(tin.triangles, ztri)
+	}
+	tin.buildIndex()
+	return &tin, nil
+}
+
+func (tin *TIN) buildIndex() {
+	if len(tin.triangles) == 0 {
+		return
+	}
+	rects := make([]rtreego.Spatial, len(tin.triangles))
+	for i, tri := range tin.triangles {
+		ext := geom.NewExtent(tri[0].XY(), tri[1].XY(), tri[2].XY())
+		rect, err := rtreego.NewRect(
+			rtreego.Point{ext.MinX(), ext.MinY()},
+			[]float64{ext.XSpan() + tinEpsilon, ext.YSpan() + tinEpsilon},
+		)
+		if err != nil {
+			// We add epsilon to the lengths so this can not happen.
+			panic("Assumption broken:" + err.Error())
+		}
+		rects[i] = &tinTriangle{idx: i, rect: rect}
+	}
+	tin.tree = rtreego.NewTree(2, 2, 5, rects...)
+}
+
+// Triangles returns the triangles of the TIN.
+func (tin *TIN) Triangles() [][3]geom.PointZ {
+	if tin == nil {
+		return nil
+	}
+	return tin.triangles
+}
+
+// Extent returns the 2D extent of the TIN, or nil if the TIN has no triangles.
+func (tin *TIN) Extent() *geom.Extent {
+	if tin == nil || len(tin.triangles) == 0 {
+		return nil
+	}
+	var ext *geom.Extent
+	for _, tri := range tin.triangles {
+		for i := range tri {
+			if ext == nil {
+				ext = geom.NewExtent(tri[i].XY())
+				continue
+			}
+			ext.AddPoints(tri[i].XY())
+		}
+	}
+	return ext
+}
+
+// barycentric returns the barycentric coordinates of pt with respect to the
+// triangle tri.
+func barycentric(tri [3]geom.PointZ, pt [2]float64) (l0, l1, l2 float64, ok bool) {
+	x0, y0 := tri[0][0], tri[0][1]
+	x1, y1 := tri[1][0], tri[1][1]
+	x2, y2 := tri[2][0], tri[2][1]
+
+	det := (y1-y2)*(x0-x2) + (x2-x1)*(y0-y2)
+	if det == 0 {
+		return 0, 0, 0, false
+	}
+	l0 = ((y1-y2)*(pt[0]-x2) + (x2-x1)*(pt[1]-y2)) / det
+	l1 = ((y2-y0)*(pt[0]-x2) + (x0-x2)*(pt[1]-y2)) / det
+	l2 = 1 - l0 - l1
+	return l0, l1, l2, true
+}
+
+// Interpolate returns the z value at the given point, by linearly
+// interpolating the z values of the vertices of the triangle containing the
+// point. If the point is outside of the TIN, ok will be false.
+func (tin *TIN) Interpolate(pt geom.Point) (z float64, ok bool) {
+	if tin == nil || tin.tree == nil {
+		return 0, false
+	}
+	results := tin.tree.SearchIntersect(rtreego.Point{pt[0], pt[1]}.ToRect(tinEpsilon))
+	for _, r := range results {
+		tt, ok := r.(*tinTriangle)
+		if !ok {
+			continue
+		}
+		tri := tin.triangles[tt.idx]
+		l0, l1, l2, ok := barycentric(tri, [2]float64(pt))
+		if !ok {
+			continue
+		}
+		if l0 < -tinEpsilon || l1 < -tinEpsilon || l2 < -tinEpsilon {
+			continue
+		}
+		return l0*tri[0][2] + l1*tri[1][2] + l2*tri[2][2], true
+	}
+	return 0, false
+}
+
+// zRange returns the min and max z values of the TIN.
+func (tin *TIN) zRange() (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, tri := range tin.triangles {
+		for i := range tri {
+			if math.IsNaN(tri[i][2]) {
+				continue
+			}
+			min = math.Min(min, tri[i][2])
+			max = math.Max(max, tri[i][2])
+		}
+	}
+	return min, max
+}
+
+// crossing returns the point on the edge a,b where the z value is equal to
+// level. The points are ordered before interpolating so the shared edge of
+// two neighboring triangles produces exactly the same point.
+func crossing(a, b geom.PointZ, level float64) [3]float64 {
+	if a[0] > b[0] || (a[0] == b[0] && a[1] > b[1]) {
+		a, b = b, a
+	}
+	t := (level - a[2]) / (b[2] - a[2])
+	return [3]float64{
+		a[0] + t*(b[0]-a[0]),
+		a[1] + t*(b[1]-a[1]),
+		level,
+	}
+}
+
+// contourSegments returns the segments for each triangle crossed by the given
+// level. Vertices with a z value equal to the level are considered to be
+// above the level, this ensures each triangle produces at most one segment.
+func (tin *TIN) contourSegments(level float64) (segs [][2][3]float64) {
+	for _, tri := range tin.triangles {
+		var (
+			pts   [2][3]float64
+			count int
+		)
+		for i := 0; i < 3; i++ {
+			a, b := tri[i], tri[(i+1)%3]
+			if math.IsNaN(a[2]) || math.IsNaN(b[2]) {
+				count = 0
+				break
+			}
+			if (a[2] >= level) == (b[2] >= level) {
+				continue
+			}
+			if count < 2 {
+				pts[count] = crossing(a, b, level)
+			}
+			count++
+		}
+		if count != 2 || pts[0] == pts[1] {
+			continue
+		}
+		segs = append(segs, pts)
+	}
+	return segs
+}
+
+// chainSegments joins segments that share end points into linestrings. Closed
+// contours will have the first point repeated as the last point.
+func chainSegments(segs [][2][3]float64) (lines []geom.LineStringZ) {
+	type key = [2]float64
+	keyOf := func(pt [3]float64) key { return key{pt[0], pt[1]} }
+
+	adjacent := make(map[key][]int, len(segs)*2)
+	for i, seg := range segs {
+		adjacent[keyOf(seg[0])] = append(adjacent[keyOf(seg[0])], i)
+		adjacent[keyOf(seg[1])] = append(adjacent[keyOf(seg[1])], i)
+	}
+	used := make([]bool, len(segs))
+
+	// next returns the end point of an unused segment connected to pt.
+	next := func(pt [3]float64) ([3]float64, bool) {
+		for _, idx := range adjacent[keyOf(pt)] {
+			if used[idx] {
+				continue
+			}
+			used[idx] = true
+			if keyOf(segs[idx][0]) == keyOf(pt) {
+				return segs[idx][1], true
+			}
+			return segs[idx][0], true
+		}
+		return [3]float64{}, false
+	}
+
+	walk := func(start int) geom.LineStringZ {
+		used[start] = true
+		line := geom.LineStringZ{segs[start][0], segs[start][1]}
+		for pt, ok := next(line[len(line)-1]); ok; pt, ok = next(line[len(line)-1]) {
+			line = append(line, pt)
+		}
+		// extend backwards from the starting point.
+		var head geom.LineStringZ
+		for pt, ok := next(line[0]); ok; pt, ok = next(pt) {
+			head = append(head, pt)
+		}
+		if len(head) == 0 {
+			return line
+		}
+		for i, j := 0, len(head)-1; i < j; i, j = i+1, j-1 {
+			head[i], head[j] = head[j], head[i]
+		}
+		return append(head, line...)
+	}
+
+	// Start with the open contours, those that have an end point
+	// touching only one segment.
+	for i := range segs {
+		if used[i] {
+			continue
+		}
+		if len(adjacent[keyOf(segs[i][0])]) != 1 && len(adjacent[keyOf(segs[i][1])]) != 1 {
+			continue
+		}
+		if len(adjacent[keyOf(segs[i][0])]) != 1 {
+			segs[i][0], segs[i][1] = segs[i][1], segs[i][0]
+		}
+		lines = append(lines, walk(i))
+	}
+	// What is left are closed contours.
+	for i := range segs {
+		if used[i] {
+			continue
+		}
+		lines = append(lines, walk(i))
+	}
+	return lines
+}
+
+// ContourLevel returns the isolines of the TIN for the given z value.
+func (tin *TIN) ContourLevel(level float64) []geom.LineStringZ {
+	if tin == nil {
+		return nil
+	}
+	return chainSegments(tin.contourSegments(level))
+}
+
+// Contours returns the isolines of the TIN at every multiple of the given
+// interval that is within the z range of the TIN. The isolines are generated
+// by marching through the triangles of the TIN.
+func (tin *TIN) Contours(interval float64) (lines []geom.LineStringZ) {
+	if tin == nil || len(tin.triangles) == 0 || !(interval > 0) {
+		return nil
+	}
+	min, max := tin.zRange()
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		return nil
+	}
+	for i := math.Ceil(min / interval); i*interval <= max; i++ {
+		lines = append(lines, tin.ContourLevel(i*interval)...)
+	}
+	return lines
+}
@@ -0,0 +1,116 @@
+package delaunay_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/triangulate/delaunay"
+	"github.com/go-spatial/geom/winding"
+)
+
+// planeGrid returns a grid of points, of size by size, on the plane z = x + 2y
+func planeGrid(size int) (pts []geom.PointZ) {
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			pts = append(pts, geom.PointZ{float64(x), float64(y), float64(x + 2*y)})
+		}
+	}
+	return pts
+}
+
+func TestTINInterpolate(t *testing.T) {
+	tin, err := delaunay.NewTIN(context.Background(), winding.Order{}, planeGrid(5))
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	if len(tin.Triangles()) != 32 {
+		t.Errorf("triangles, expected 32, got %v", len(tin.Triangles()))
+	}
+
+	type tcase struct {
+		pt geom.Point
+		z  float64
+		ok bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			z, ok := tin.Interpolate(tc.pt)
+			if ok != tc.ok {
+				t.Errorf("ok, expected %v, got %v", tc.ok, ok)
+				return
+			}
+			if !tc.ok {
+				return
+			}
+			if math.Abs(z-tc.z) > 1e-9 {
+				t.Errorf("z, expected %v, got %v", tc.z, z)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"vertex":   {pt: geom.Point{2, 2}, z: 6, ok: true},
+		"inside":   {pt: geom.Point{1.25, 3.5}, z: 8.25, ok: true},
+		"on edge":  {pt: geom.Point{0, 1.5}, z: 3, ok: true},
+		"corner":   {pt: geom.Point{4, 4}, z: 12, ok: true},
+		"outside":  {pt: geom.Point{-1, 2}},
+		"outside2": {pt: geom.Point{2, 4.5}},
+	}
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestTINContours(t *testing.T) {
+	// z = x on a 5x5 grid; contours should be vertical lines.
+	var pts []geom.PointZ
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			pts = append(pts, geom.PointZ{float64(x), float64(y), float64(x)})
+		}
+	}
+	tin, err := delaunay.NewTIN(context.Background(), winding.Order{}, pts)
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+
+	lines := tin.Contours(1.5)
+	if len(lines) != 2 {
+		t.Fatalf("number of contours, expected 2, got %v: %v", len(lines), lines)
+	}
+	for i, line := range lines {
+		level := float64(i+1) * 1.5
+		var miny, maxy = math.Inf(1), math.Inf(-1)
+		for _, pt := range line {
+			if math.Abs(pt[0]-level) > 1e-9 || pt[2] != level {
+				t.Errorf("contour %v, expected points at x = z = %v, got %v", i, level, pt)
+			}
+			miny, maxy = math.Min(miny, pt[1]), math.Max(maxy, pt[1])
+		}
+		if miny != 0 || maxy != 4 {
+			t.Errorf("contour %v, expected to span y from 0 to 4, got %v to %v", i, miny, maxy)
+		}
+	}
+
+	// A cone should produce closed contours.
+	pts = pts[:0]
+	for x := -4; x <= 4; x++ {
+		for y := -4; y <= 4; y++ {
+			pts = append(pts, geom.PointZ{float64(x), float64(y), 10 - math.Hypot(float64(x), float64(y))})
+		}
+	}
+	tin, err = delaunay.NewTIN(context.Background(), winding.Order{}, pts)
+	if err != nil {
+		t.Fatalf("error, expected nil, got %v", err)
+	}
+	lines = tin.ContourLevel(8)
+	if len(lines) != 1 {
+		t.Fatalf("number of contours, expected 1, got %v", len(lines))
+	}
+	if first, last := lines[0][0], lines[0][len(lines[0])-1]; first != last {
+		t.Errorf("contour, expected closed ring, got %v != %v", first, last)
+	}
+}
@@ -0,0 +1,121 @@
+// Package reproject converts geometries between the coordinate systems
+// supported by github.com/go-spatial/proj.
+package reproject
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/transform"
+	"github.com/go-spatial/proj"
+)
+
+// ErrUnsupportedEPSGCode is returned when a coordinate system is not supported
+// by proj.
+type ErrUnsupportedEPSGCode struct {
+	Code proj.EPSGCode
+}
+
+func (e ErrUnsupportedEPSGCode) Error() string {
+	return fmt.Sprintf("reproject: unsupported epsg code: %d", e.Code)
+}
+
+// Transformer converts geometries from one coordinate system to another.
+type Transformer struct {
+	From proj.EPSGCode
+	To   proj.EPSGCode
+
+	// MaxSegmentLength, if greater than zero, is the longest a segment of a
+	// line or ring can be, in the units of From, before it is transformed.
+	// Longer segments are split, adding vertices so that the edges follow
+	// the curve of the projection.
+	MaxSegmentLength float64
+}
+
+// Transform converts the geometry from one coordinate system to another. See
+// Transformer.Transform.
+func Transform(g geom.Geometry, from, to proj.EPSGCode) (geom.Geometry, error) {
+	return Transformer{From: from, To: to}.Transform(g)
+}
+
+// TransformExtent converts the extent from one coordinate system to another.
+// See Transformer.TransformExtent.
+func TransformExtent(e *geom.Extent, from, to proj.EPSGCode) (*geom.Extent, error) {
+	return Transformer{From: from, To: to}.TransformExtent(e)
+}
+
+// supported checks that proj can convert to and from the code.
+func supported(code proj.EPSGCode) error {
+	if code == proj.EPSG4326 {
+		return nil
+	}
+	if _, err := proj.Convert(code, []float64{0, 0}); err != nil {
+		return ErrUnsupportedEPSGCode{Code: code}
+	}
+	return nil
+}
+
+func (t Transformer) mapper() (transform.Mapper, error) {
+	if err := supported(t.From); err != nil {
+		return transform.Mapper{}, err
+	}
+	if err := supported(t.To); err != nil {
+		return transform.Mapper{}, err
+	}
+	srid := uint32(t.To)
+	m := transform.Mapper{
+		Transform: t.point,
+		SRID:      &srid,
+	}
+	if t.MaxSegmentLength > 0 {
+		m.Densify = func(a, b [2]float64) int {
+			return int(math.Ceil(math.Hypot(b[0]-a[0], b[1]-a[1]) / t.MaxSegmentLength))
+		}
+	}
+	return m, nil
+}
+
+// point converts a point, going through 4326 if needed.
+func (t Transformer) point(x, y, z float64) (float64, float64, float64, error) {
+	if t.From == t.To {
+		return x, y, z, nil
+	}
+	pt := []float64{x, y}
+	var err error
+	if t.From != proj.EPSG4326 {
+		if pt, err = proj.Inverse(t.From, pt); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	if t.To != proj.EPSG4326 {
+		if pt, err = proj.Convert(t.To, pt); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+	return pt[0], pt[1], z, nil
+}
+
+// Transform converts the geometry from one coordinate system to another,
+// returning a new geometry of the same type. Z and M values are not changed.
+// The SRID of geometries with an SRID is set to To, whatever it was
+// before. A geom.Extent is converted to the extent containing its converted
+// edges, set MaxSegmentLength to have the edges follow the curve of the
+// projection.
+func (t Transformer) Transform(g geom.Geometry) (geom.Geometry, error) {
+	m, err := t.mapper()
+	if err != nil {
+		return nil, err
+	}
+	return m.Geometry(g)
+}
+
+// TransformExtent converts the extent from one coordinate system to another,
+// returning the extent containing the converted edges of the extent.
+func (t Transformer) TransformExtent(e *geom.Extent) (*geom.Extent, error) {
+	m, err := t.mapper()
+	if err != nil {
+		return nil, err
+	}
+	return m.Extent(e)
+}
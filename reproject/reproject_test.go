@@ -0,0 +1,160 @@
+package reproject_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/cmp"
+	"github.com/go-spatial/geom/reproject"
+	"github.com/go-spatial/proj"
+)
+
+// webMercatorMax is the largest x value in web mercator.
+const webMercatorMax = 20037508.342789244
+
+func TestTransform(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		from, to proj.EPSGCode
+		expected geom.Geometry
+		err      error
+	}
+
+	compare := cmp.New(1e-6)
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := reproject.Transform(tc.geom, tc.from, tc.to)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error, expected nil got %v", err)
+				return
+			}
+			if !compare.GeometryEqual(got, tc.expected) {
+				t.Errorf("transform, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"same": {
+			geom:     geom.Point{1, 2},
+			from:     proj.EPSG3857,
+			to:       proj.EPSG3857,
+			expected: geom.Point{1, 2},
+		},
+		"4326 to 3857": {
+			geom:     geom.Point{180, 0},
+			from:     proj.EPSG4326,
+			to:       proj.EPSG3857,
+			expected: geom.Point{webMercatorMax, 0},
+		},
+		"3857 to 4326": {
+			geom:     geom.LineString{{0, 0}, {-webMercatorMax, 0}},
+			from:     proj.EPSG3857,
+			to:       proj.EPSG4326,
+			expected: geom.LineString{{0, 0}, {-180, 0}},
+		},
+		"3857 to 4087": {
+			geom:     geom.MultiPoint{{webMercatorMax, 0}},
+			from:     proj.EPSG3857,
+			to:       proj.EPSG4087,
+			expected: geom.MultiPoint{{webMercatorMax, 0}},
+		},
+		"srid is updated": {
+			geom:     geom.PointS{Srid: 4326, Xy: geom.Point{-180, 0}},
+			from:     proj.EPSG4326,
+			to:       proj.EPSG3857,
+			expected: geom.PointS{Srid: 3857, Xy: geom.Point{-webMercatorMax, 0}},
+		},
+		"unsupported": {
+			geom: geom.Point{0, 0},
+			from: proj.EPSG4326,
+			to:   proj.EPSGCode(2000),
+			err:  reproject.ErrUnsupportedEPSGCode{Code: 2000},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestTransformZM(t *testing.T) {
+	got, err := reproject.Transform(geom.LineStringZM{{180, 0, 10, 1}, {0, 0, 20, 2}}, proj.EPSG4326, proj.EPSG3857)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	ls := got.(geom.LineStringZM)
+	expected := geom.LineStringZM{{webMercatorMax, 0, 10, 1}, {0, 0, 20, 2}}
+	for i := range ls {
+		for j := range ls[i] {
+			if math.Abs(ls[i][j]-expected[i][j]) > 1e-6 {
+				t.Fatalf("transform, expected %v got %v", expected, ls)
+			}
+		}
+	}
+}
+
+func TestTransformerDensify(t *testing.T) {
+	tr := reproject.Transformer{
+		From:             proj.EPSG4326,
+		To:               proj.EPSG3857,
+		MaxSegmentLength: 1,
+	}
+
+	got, err := tr.Transform(geom.LineStringM{{0, 0, 0}, {0, 10, 10}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	ls := got.(geom.LineStringM)
+	if len(ls) != 11 {
+		t.Fatalf("number of points, expected 11 got %v", len(ls))
+	}
+	for i := range ls {
+		if ls[i][2] != float64(i) {
+			t.Errorf("m value %v, expected %v got %v", i, float64(i), ls[i][2])
+		}
+	}
+
+	// polygons are densified including the closing segment.
+	got, err = tr.Transform(geom.Polygon{{{0, 0}, {2, 0}, {2, 2}}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if n := len(got.(geom.Polygon)[0]); n != 7 {
+		t.Errorf("number of points, expected 7 got %v", n)
+	}
+}
+
+func TestTransformExtent(t *testing.T) {
+	ext, err := reproject.TransformExtent(geom.NewExtent([2]float64{-180, -85}, [2]float64{180, 85}), proj.EPSG4326, proj.EPSG3857)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if math.Abs(ext.MinX()+webMercatorMax) > 1e-6 || math.Abs(ext.MaxX()-webMercatorMax) > 1e-6 {
+		t.Errorf("x, expected ±%v got %v, %v", webMercatorMax, ext.MinX(), ext.MaxX())
+	}
+	if math.Abs(ext.MaxY()+ext.MinY()) > 1e-6 || ext.MaxY() < 19971868 || ext.MaxY() > 19971869 {
+		t.Errorf("y, expected ±19971868.88 got %v, %v", ext.MinY(), ext.MaxY())
+	}
+
+	// reprojecting to 4326 and back should give the same extent.
+	back, err := reproject.TransformExtent(ext, proj.EPSG3857, proj.EPSG4326)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	expected := geom.Extent{-180, -85, 180, 85}
+	for i := range expected {
+		if math.Abs(back[i]-expected[i]) > 1e-6 {
+			t.Fatalf("extent, expected %v got %v", expected, *back)
+		}
+	}
+}
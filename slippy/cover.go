@@ -0,0 +1,256 @@
+package slippy
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/boundary"
+	"github.com/go-spatial/geom/planar"
+)
+
+// MaxCoverTiles is the largest number of tiles Cover will return; a geometry
+// covering more tiles at the requested zoom is an error.
+const MaxCoverTiles = 1 << 20
+
+// ErrTooManyTiles is returned by Cover and CompactCover when the geometry
+// covers more than MaxCoverTiles tiles.
+var ErrTooManyTiles = errors.New("slippy: too many tiles")
+
+// CoverOptions are the options for Cover and CompactCover.
+type CoverOptions struct {
+	// Buffer is the number of pixels the tiles are extended by on each
+	// side; a tile is in the cover if the geometry intersects the tile
+	// extended by the buffer. This is usually the same as the buffer used
+	// to encode the tiles.
+	Buffer float64
+	// TileDim is the number of pixels along the side of a tile; if 0,
+	// MvtTileDim is used.
+	TileDim uint64
+}
+
+func (opts *CoverOptions) buffer() float64 {
+	if opts == nil || opts.Buffer < 0 {
+		return 0
+	}
+	return opts.Buffer
+}
+
+func (opts *CoverOptions) tileDim() float64 {
+	if opts == nil || opts.TileDim == 0 {
+		return MvtTileDim
+	}
+	return float64(opts.TileDim)
+}
+
+// bufferedExtent returns the extent of the tile, extended by the buffer in
+// pixels.
+func bufferedExtent(g TileGridder, tile Tile, buffer, tileDim float64) (*geom.Extent, error) {
+	ext, err := Extent(g, tile)
+	if err != nil {
+		return nil, err
+	}
+	if buffer == 0 {
+		return ext, nil
+	}
+	dx, dy := ext.XSpan()*buffer/tileDim, ext.YSpan()*buffer/tileDim
+	return &geom.Extent{ext.MinX() - dx, ext.MinY() - dy, ext.MaxX() + dx, ext.MaxY() + dy}, nil
+}
+
+// isQuadLevel returns whether each tile at zoom z has exactly the four
+// tiles of FamilyAt as its children at z+1.
+func isQuadLevel(g TileGridder, z Zoom) bool {
+	size, ok := g.Size(z)
+	if !ok {
+		return false
+	}
+	next, ok := g.Size(z + 1)
+	return ok && next.X == 2*size.X && next.Y == 2*size.Y
+}
+
+// Cover returns the tiles, at the given zoom, that the geometry intersects;
+// unlike FromBounds, which returns all of the tiles in the geometry's extent.
+// A tile touching the geometry intersects it, and polygons include their
+// interior. The geometry is in the grid's coordinates. The tiles are sorted
+// using Tile.Less.
+//
+// The tiles are found by walking down from the lowest zoom, skipping the
+// children of tiles the geometry does not intersect.
+//
+// Possible errors:
+//	 ErrTooManyTiles
+func Cover(g TileGridder, geo geom.Geometry, z Zoom, opts *CoverOptions) ([]Tile, error) {
+	bounds, err := geom.NewExtentFromGeometry(geo)
+	if err != nil {
+		return nil, err
+	}
+	if bounds == nil {
+		return nil, nil
+	}
+	buffer, tileDim := opts.buffer(), opts.tileDim()
+
+	// start at the lowest zoom from which each level splits every tile in
+	// four.
+	start := z
+	for start > 0 && isQuadLevel(g, start-1) {
+		start--
+	}
+	candidates, err := FromBounds(g, bounds, start)
+	if err != nil {
+		return nil, err
+	}
+	if buffer > 0 {
+		candidates = growTiles(g, candidates, int(math.Ceil(buffer/tileDim)))
+	}
+
+	var (
+		tiles []Tile
+		visit func(tile Tile) error
+		// a tile that intersects a polygon but not its boundary is inside
+		// of it, along with all of its descendants.
+		rings = boundary.Polygons(geo)
+	)
+	visit = func(tile Tile) error {
+		// The buffer of the ancestors is the same number of pixels, at their
+		// zoom; so it contains the buffer of all of their descendants.
+		ext, err := bufferedExtent(g, tile, buffer, tileDim)
+		if err != nil {
+			return err
+		}
+		d, err := planar.Distance(ext.AsPolygon(), geo)
+		if err != nil {
+			return err
+		}
+		if d != 0 {
+			return nil
+		}
+		if tile.Z == z {
+			if len(tiles) == MaxCoverTiles {
+				return ErrTooManyTiles
+			}
+			tiles = append(tiles, tile)
+			return nil
+		}
+		if len(rings) > 0 {
+			d, err := planar.Distance(ext.AsPolygon(), rings)
+			if err != nil {
+				return err
+			}
+			if d != 0 {
+				// each level below start splits a tile in four.
+				if n := z - tile.Z; 2*n > 30 || len(tiles)+1<<(2*n) > MaxCoverTiles {
+					return ErrTooManyTiles
+				}
+				tile.FamilyAt(z)(func(tile Tile) bool {
+					tiles = append(tiles, tile)
+					return true
+				})
+				return nil
+			}
+		}
+		tile.FamilyAt(tile.Z + 1)(func(child Tile) bool {
+			err = visit(child)
+			return err == nil
+		})
+		return err
+	}
+	for _, tile := range candidates {
+		if err := visit(tile); err != nil {
+			return nil, err
+		}
+	}
+	sortTiles(tiles)
+	return tiles, nil
+}
+
+// growTiles returns the tiles with the tiles up to n rows and columns around
+// them added; limited to the grid.
+func growTiles(g TileGridder, tiles []Tile, n int) []Tile {
+	if len(tiles) == 0 || n == 0 {
+		return tiles
+	}
+	size, ok := g.Size(tiles[0].Z)
+	if !ok {
+		return tiles
+	}
+	minX, minY, maxX, maxY := tiles[0].X, tiles[0].Y, tiles[0].X, tiles[0].Y
+	for _, tile := range tiles[1:] {
+		minX, maxX = minUint(minX, tile.X), maxUint(maxX, tile.X)
+		minY, maxY = minUint(minY, tile.Y), maxUint(maxY, tile.Y)
+	}
+	grown := make([]Tile, 0, len(tiles))
+	for x := int(minX) - n; x <= int(maxX)+n; x++ {
+		for y := int(minY) - n; y <= int(maxY)+n; y++ {
+			if x < 0 || y < 0 || x >= int(size.X) || y >= int(size.Y) {
+				continue
+			}
+			grown = append(grown, Tile{Z: tiles[0].Z, X: uint(x), Y: uint(y)})
+		}
+	}
+	return grown
+}
+
+func minUint(a, b uint) uint {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func sortTiles(tiles []Tile) {
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].Less(tiles[j]) })
+}
+
+// CompactCover returns the tiles the geometry intersects, as Cover does at
+// maxZoom; but with the tiles replaced by their parent whenever all four of
+// the parent's children are in the cover, down to minZoom. Every point
+// covered by the result is covered by the same tiles at maxZoom, so the
+// result is a smaller set for seeding or expiring a tile cache. The tiles are
+// sorted using Tile.Less.
+//
+// Possible errors:
+//	 ErrTooManyTiles
+func CompactCover(g TileGridder, geo geom.Geometry, minZoom, maxZoom Zoom, opts *CoverOptions) ([]Tile, error) {
+	if minZoom > maxZoom {
+		minZoom, maxZoom = maxZoom, minZoom
+	}
+	tiles, err := Cover(g, geo, maxZoom, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var compact []Tile
+	for z := maxZoom; z > minZoom && isQuadLevel(g, z-1); z-- {
+		children := make(map[Tile]int)
+		for _, tile := range tiles {
+			children[Tile{Z: z - 1, X: tile.X / 2, Y: tile.Y / 2}]++
+		}
+		var parents []Tile
+		for _, tile := range tiles {
+			parent := Tile{Z: z - 1, X: tile.X / 2, Y: tile.Y / 2}
+			if children[parent] != 4 {
+				compact = append(compact, tile)
+				continue
+			}
+			// add each parent once; from its first child
+			if tile.X%2 == 0 && tile.Y%2 == 0 {
+				parents = append(parents, parent)
+			}
+		}
+		tiles = parents
+		if len(tiles) == 0 {
+			break
+		}
+	}
+	compact = append(compact, tiles...)
+	sortTiles(compact)
+	return compact, nil
+}
@@ -0,0 +1,178 @@
+package slippy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestCover(t *testing.T) {
+	type tcase struct {
+		Grid TileGridder // if nil, we will default to Grid4326
+		Geom geom.Geometry
+		Z    Zoom
+		Opts *CoverOptions
+		// Tiles are the expected tiles; if nil Len and Missing are checked.
+		Tiles   []Tile
+		Len     int
+		Missing []Tile
+		Err     error
+	}
+
+	fn := tcCurry(func(tc tcase, t *testing.T) {
+		if tc.Grid == nil {
+			tc.Grid = Grid4326{}
+		}
+		tiles, err := Cover(tc.Grid, tc.Geom, tc.Z, tc.Opts)
+		if err != tc.Err {
+			t.Fatalf("error expected %v, got %v", tc.Err, err)
+		}
+		if tc.Err != nil {
+			return
+		}
+		if tc.Tiles != nil || tc.Len == 0 {
+			if !reflect.DeepEqual(tiles, tc.Tiles) {
+				t.Errorf("tiles expected %v, got %v", tc.Tiles, tiles)
+			}
+			return
+		}
+		if len(tiles) != tc.Len {
+			t.Errorf("len expected %v, got %v", tc.Len, len(tiles))
+		}
+		for _, missing := range tc.Missing {
+			for _, tile := range tiles {
+				if tile.Equal(missing) {
+					t.Errorf("tile %v, expected to be missing", tile)
+				}
+			}
+		}
+	})
+
+	testcases := map[string]tcase{
+		"too many tiles": {
+			// the interior of the polygon is filled in without visiting
+			// each tile.
+			Geom: geom.Polygon{{{-170, -80}, {170, -80}, {170, 80}, {-170, 80}}},
+			Z:    14,
+			Err:  ErrTooManyTiles,
+		},
+		"point": {
+			Geom:  geom.Point{-22.5, 20},
+			Z:     3,
+			Tiles: []Tile{{Z: 3, X: 3, Y: 3}},
+		},
+		"point near an edge": {
+			Geom:  geom.Point{-0.01, 20},
+			Z:     3,
+			Tiles: []Tile{{Z: 3, X: 3, Y: 3}},
+		},
+		"point near an edge with a buffer": {
+			// the point is less than a pixel from the tile to the east
+			Geom:  geom.Point{-0.01, 20},
+			Z:     3,
+			Opts:  &CoverOptions{Buffer: 64},
+			Tiles: []Tile{{Z: 3, X: 3, Y: 3}, {Z: 3, X: 4, Y: 3}},
+		},
+		"diagonal line": {
+			// FromBounds returns eight tiles
+			Geom: geom.LineString{{-170, -60}, {170, 50}},
+			Z:    2,
+			Tiles: []Tile{
+				{Z: 2, X: 0, Y: 2},
+				{Z: 2, X: 1, Y: 2},
+				{Z: 2, X: 2, Y: 1},
+				{Z: 2, X: 2, Y: 2},
+				{Z: 2, X: 3, Y: 1},
+			},
+		},
+		"ring": {
+			Geom: geom.Polygon{
+				{{-170, -80}, {170, -80}, {170, 80}, {-170, 80}},
+				{{-60, -50}, {60, -50}, {60, 50}, {-60, 50}},
+			},
+			Z:   3,
+			Len: 60,
+			Missing: []Tile{
+				{Z: 3, X: 3, Y: 3}, {Z: 3, X: 3, Y: 4},
+				{Z: 3, X: 4, Y: 3}, {Z: 3, X: 4, Y: 4},
+			},
+		},
+		"inside a polygon": {
+			Geom: geom.Polygon{{{-180, -85}, {180, -85}, {180, 85}, {-180, 85}}},
+			Z:    6,
+			Len:  64 * 64,
+		},
+		"empty": {
+			Geom: geom.LineString{},
+			Z:    3,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCompactCover(t *testing.T) {
+	type tcase struct {
+		Geom    geom.Geometry
+		MinZoom Zoom
+		MaxZoom Zoom
+		Tiles   []Tile
+	}
+
+	fn := tcCurry(func(tc tcase, t *testing.T) {
+		tiles, err := CompactCover(Grid4326{}, tc.Geom, tc.MinZoom, tc.MaxZoom, nil)
+		if err != nil {
+			t.Fatalf("error expected nil, got %v", err)
+		}
+		if !reflect.DeepEqual(tiles, tc.Tiles) {
+			t.Errorf("tiles expected %v, got %v", tc.Tiles, tiles)
+		}
+	})
+
+	quarter := geom.Polygon{{{-179.9, 0.1}, {-0.1, 0.1}, {-0.1, 85}, {-179.9, 85}}}
+	testcases := map[string]tcase{
+		"quarter": {
+			Geom:    quarter,
+			MinZoom: 0,
+			MaxZoom: 3,
+			Tiles:   []Tile{{Z: 1, X: 0, Y: 0}},
+		},
+		"quarter above min zoom": {
+			Geom:    quarter,
+			MinZoom: 2,
+			MaxZoom: 3,
+			Tiles: []Tile{
+				{Z: 2, X: 0, Y: 0}, {Z: 2, X: 0, Y: 1},
+				{Z: 2, X: 1, Y: 0}, {Z: 2, X: 1, Y: 1},
+			},
+		},
+		"quarter and a point": {
+			Geom: geom.MultiPolygon{
+				quarter[:],
+				{{{10, 10}, {11, 10}, {11, 11}}},
+			},
+			MinZoom: 0,
+			MaxZoom: 3,
+			Tiles:   []Tile{{Z: 1, X: 0, Y: 0}, {Z: 3, X: 4, Y: 3}},
+		},
+		"line": {
+			Geom:    geom.LineString{{-170, -60}, {170, 50}},
+			MinZoom: 0,
+			MaxZoom: 2,
+			Tiles: []Tile{
+				{Z: 2, X: 0, Y: 2},
+				{Z: 2, X: 1, Y: 2},
+				{Z: 2, X: 2, Y: 1},
+				{Z: 2, X: 2, Y: 2},
+				{Z: 2, X: 3, Y: 1},
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, fn(tc))
+	}
+}
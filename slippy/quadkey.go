@@ -0,0 +1,61 @@
+package slippy
+
+// TMS returns the zoom, column and row of the tile in the TMS scheme, where
+// rows are numbered from the bottom of the grid instead of the top.
+func (tile Tile) TMS() (Zoom, uint, uint) {
+	return tile.Z, tile.X, tile.Z.TileSize().Y - 1 - tile.Y
+}
+
+// FromTMS returns the tile for the zoom, column and row in the TMS scheme;
+// see Tile.TMS.
+func FromTMS(z Zoom, x, y uint) Tile {
+	return Tile{Z: z, X: x, Y: z.TileSize().Y - 1 - y}
+}
+
+// Quadkey returns the Bing Maps quadkey of the tile; a string of one digit,
+// 0 to 3, for each zoom. The quadkey of the tile at zoom 0 is empty.
+func (tile Tile) Quadkey() string {
+	key := make([]byte, tile.Z)
+	for i := range key {
+		mask := uint(1) << (uint(tile.Z) - 1 - uint(i))
+		digit := byte('0')
+		if tile.X&mask != 0 {
+			digit++
+		}
+		if tile.Y&mask != 0 {
+			digit += 2
+		}
+		key[i] = digit
+	}
+	return string(key)
+}
+
+// FromQuadkey returns the tile for the Bing Maps quadkey; see Tile.Quadkey.
+//
+// Possible errors:
+//
+//	ErrInvalidQuadkey
+//	ErrTileOutOfRange
+func FromQuadkey(key string) (Tile, error) {
+	if len(key) > MaxZoom {
+		return Tile{}, ErrTileOutOfRange
+	}
+	tile := Tile{Z: Zoom(len(key))}
+	for i := 0; i < len(key); i++ {
+		tile.X <<= 1
+		tile.Y <<= 1
+		switch key[i] {
+		case '0':
+		case '1':
+			tile.X |= 1
+		case '2':
+			tile.Y |= 1
+		case '3':
+			tile.X |= 1
+			tile.Y |= 1
+		default:
+			return Tile{}, ErrInvalidQuadkey
+		}
+	}
+	return tile, nil
+}
@@ -0,0 +1,60 @@
+package slippy
+
+import "testing"
+
+func TestQuadkey(t *testing.T) {
+	type tcase struct {
+		tile Tile
+		key  string
+		tms  uint
+	}
+
+	fn := tcCurry(func(tc tcase, t *testing.T) {
+		if key := tc.tile.Quadkey(); key != tc.key {
+			t.Errorf("quadkey expected %v, got %v", tc.key, key)
+		}
+		tile, err := FromQuadkey(tc.key)
+		if err != nil {
+			t.Fatalf("error expected nil, got %v", err)
+		}
+		if tile != tc.tile {
+			t.Errorf("tile expected %v, got %v", tc.tile, tile)
+		}
+
+		z, x, y := tc.tile.TMS()
+		if z != tc.tile.Z || x != tc.tile.X || y != tc.tms {
+			t.Errorf("tms expected %v/%v/%v, got %v/%v/%v", tc.tile.Z, tc.tile.X, tc.tms, z, x, y)
+		}
+		if tile := FromTMS(z, x, y); tile != tc.tile {
+			t.Errorf("from tms expected %v, got %v", tc.tile, tile)
+		}
+	})
+
+	testcases := map[string]tcase{
+		"zoom 0": {tile: Tile{}, key: "", tms: 0},
+		"bing":   {tile: Tile{Z: 3, X: 3, Y: 5}, key: "213", tms: 2},
+		"bottom right": {
+			tile: Tile{Z: 4, X: 15, Y: 15},
+			key:  "3333",
+			tms:  0,
+		},
+		"max zoom": {
+			tile: Tile{Z: 22, X: 1, Y: 4194303},
+			key:  "2222222222222222222223",
+			tms:  0,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, fn(tc))
+	}
+
+	for _, key := range []string{"0124", "21a"} {
+		if _, err := FromQuadkey(key); err != ErrInvalidQuadkey {
+			t.Errorf("%v error expected %v, got %v", key, ErrInvalidQuadkey, err)
+		}
+	}
+	if _, err := FromQuadkey("00000000000000000000000"); err != ErrTileOutOfRange {
+		t.Errorf("error expected %v, got %v", ErrTileOutOfRange, err)
+	}
+}
@@ -4,12 +4,19 @@ import (
 	"errors"
 	"math"
 	"strconv"
+	"strings"
 
 	"github.com/go-spatial/geom"
 )
 
 var (
 	ErrNilBounds = errors.New("slippy: Bounds cannot be nil")
+	// ErrInvalidTile is returned when parsing a tile that is not of the form z/x/y.
+	ErrInvalidTile = errors.New("slippy: invalid tile")
+	// ErrInvalidQuadkey is returned when parsing a quadkey with digits other than 0 to 3.
+	ErrInvalidQuadkey = errors.New("slippy: invalid quadkey")
+	// ErrTileOutOfRange is returned when a tile's zoom is above MaxZoom, or its column or row is outside of the zoom's grid.
+	ErrTileOutOfRange = errors.New("slippy: tile out of range")
 )
 
 // MaxZoom is the lowest zoom (furthest in)
@@ -65,6 +72,121 @@ func (tile Tile) String() string {
 		strconv.FormatInt(int64(tile.Y), 10)
 }
 
+// Valid returns whether the zoom is at most MaxZoom, and the column and row
+// are inside of the zoom's grid.
+func (tile Tile) Valid() bool {
+	if tile.Z > MaxZoom {
+		return false
+	}
+	size := tile.Z.TileSize()
+	return tile.X < size.X && tile.Y < size.Y
+}
+
+// ParseTile parses a tile of the form z/x/y, as returned by String.
+//
+// Possible errors:
+//
+//	ErrInvalidTile
+//	ErrTileOutOfRange
+func ParseTile(s string) (Tile, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != 3 {
+		return Tile{}, ErrInvalidTile
+	}
+	var zxy [3]uint64
+	for i, part := range parts {
+		v, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return Tile{}, ErrInvalidTile
+		}
+		zxy[i] = v
+	}
+	if zxy[0] > MaxZoom {
+		return Tile{}, ErrTileOutOfRange
+	}
+	tile := Tile{Z: Zoom(zxy[0]), X: uint(zxy[1]), Y: uint(zxy[2])}
+	if !tile.Valid() {
+		return Tile{}, ErrTileOutOfRange
+	}
+	return tile, nil
+}
+
+// Parent returns the tile at the zoom above that contains the tile; ok is
+// false for a tile at zoom 0.
+func (tile Tile) Parent() (parent Tile, ok bool) {
+	if tile.Z == 0 {
+		return Tile{}, false
+	}
+	return Tile{Z: tile.Z - 1, X: tile.X / 2, Y: tile.Y / 2}, true
+}
+
+// Children returns the four tiles at the zoom below that make up the tile;
+// in quadkey order: top left, top right, bottom left, bottom right.
+func (tile Tile) Children() [4]Tile {
+	z, x, y := tile.Z+1, tile.X*2, tile.Y*2
+	return [4]Tile{
+		{Z: z, X: x, Y: y},
+		{Z: z, X: x + 1, Y: y},
+		{Z: z, X: x, Y: y + 1},
+		{Z: z, X: x + 1, Y: y + 1},
+	}
+}
+
+// Siblings returns the other three tiles with the same parent, in quadkey
+// order; a tile at zoom 0 has none.
+func (tile Tile) Siblings() []Tile {
+	parent, ok := tile.Parent()
+	if !ok {
+		return nil
+	}
+	siblings := make([]Tile, 0, 3)
+	for _, child := range parent.Children() {
+		if child != tile {
+			siblings = append(siblings, child)
+		}
+	}
+	return siblings
+}
+
+// Offset returns the tile dx columns east and dy rows south of the tile.
+// Columns wrap around the antimeridian; ok is false if the row is past the
+// top or bottom of the grid.
+func (tile Tile) Offset(dx, dy int) (neighbor Tile, ok bool) {
+	n := int64(tile.Z.TileSize().X)
+	y := int64(tile.Y) + int64(dy)
+	if y < 0 || y >= n {
+		return Tile{}, false
+	}
+	x := (int64(tile.X) + int64(dx)) % n
+	if x < 0 {
+		x += n
+	}
+	return Tile{Z: tile.Z, X: uint(x), Y: uint(y)}, true
+}
+
+// Neighbors returns the tiles around the tile; starting with the one to the
+// north, and going clockwise. Columns wrap around the antimeridian, and there
+// are no neighbors past the top or bottom of the grid. The tile itself, and
+// tiles seen twice at the lowest zooms, are left out.
+func (tile Tile) Neighbors() []Tile {
+	offsets := [8][2]int{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+	neighbors := make([]Tile, 0, len(offsets))
+NextOffset:
+	for _, off := range offsets {
+		neighbor, ok := tile.Offset(off[0], off[1])
+		if !ok || neighbor == tile {
+			continue
+		}
+		for _, seen := range neighbors {
+			if seen == neighbor {
+				continue NextOffset
+			}
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors
+}
+
 // FamilyAt returns an iterator function that will call the yield function with every related tile at the requested
 // zoom. This will include the provided tile itself. (if the same zoom is provided). The parent (overlapping tile at a lower zoom level),
 // or children (overlapping tiles at a higher zoom level).
@@ -0,0 +1,372 @@
+package slippy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/proj"
+)
+
+// ErrInvalidTileMatrixSet is returned for a tile matrix set definition that can not be used as a grid.
+var ErrInvalidTileMatrixSet = errors.New("slippy: invalid tile matrix set")
+
+const (
+	// metersPerDegree is the length of a degree at the equator of WGS84, used
+	// by OGC to convert the scale denominators of geographic tile matrix sets.
+	metersPerDegree = 2 * math.Pi * 6378137 / 360
+	// pixelSize is the size, in meters, of the standard rendering pixel.
+	pixelSize = 0.00028
+)
+
+// Corners of origin of a TileMatrix.
+const (
+	CornerTopLeft    = "topLeft"
+	CornerBottomLeft = "bottomLeft"
+)
+
+// TileMatrix is a level of a TileMatrixSet; the json form is the one of the
+// OGC Two Dimensional Tile Matrix Set standard (17-083r4).
+type TileMatrix struct {
+	ID               string  `json:"id"`
+	ScaleDenominator float64 `json:"scaleDenominator"`
+	// CellSize is the size of a pixel in the units of the CRS; if 0 it is
+	// computed from the ScaleDenominator.
+	CellSize float64 `json:"cellSize,omitempty"`
+	// CornerOfOrigin is CornerTopLeft or CornerBottomLeft; if empty
+	// CornerTopLeft is used. The rows of the matrix are numbered from this
+	// corner.
+	CornerOfOrigin string `json:"cornerOfOrigin,omitempty"`
+	// PointOfOrigin is in the axis order of the CRS; see TileMatrixSet.OrderedAxes.
+	PointOfOrigin [2]float64 `json:"pointOfOrigin"`
+	TileWidth     uint       `json:"tileWidth"`
+	TileHeight    uint       `json:"tileHeight"`
+	MatrixWidth   uint       `json:"matrixWidth"`
+	MatrixHeight  uint       `json:"matrixHeight"`
+}
+
+// TileMatrixSet is a TileGridder for an OGC tile matrix set. Each tile
+// matrix is a zoom; the first, with the largest scale denominator, is zoom 0.
+// The levels do not have to be square, or double in size from one zoom to
+// the next, so national grids can be used. The geometries are in the
+// coordinates of the set's CRS, with x first.
+//
+// Tile matrices with variable widths are not supported.
+type TileMatrixSet struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+	URI   string `json:"uri,omitempty"`
+	// CRS is the URI of the coordinate reference system; e.g.
+	// http://www.opengis.net/def/crs/EPSG/0/3857
+	CRS string `json:"crs"`
+	// OrderedAxes are the abbreviations of the axes of the CRS, in the order
+	// of the coordinates of the points of origin; if empty, x is first.
+	OrderedAxes  []string     `json:"orderedAxes,omitempty"`
+	TileMatrices []TileMatrix `json:"tileMatrices"`
+
+	srid   proj.EPSGCode
+	levels []tmsLevel
+}
+
+// tmsLevel is a tile matrix ready for computing tiles.
+type tmsLevel struct {
+	// origin in x, y order
+	originX, originY float64
+	// size of a tile in the units of the CRS
+	spanX, spanY  float64
+	width, height uint
+	bottomLeft    bool
+	// tileWidth is the width of a tile in pixels
+	tileWidth uint
+}
+
+// sridFromCRS returns the EPSG code for a CRS URI; CRS84 is returned as 4326.
+func sridFromCRS(crs string) (proj.EPSGCode, bool) {
+	if strings.Contains(strings.ToUpper(crs), "CRS84") {
+		return proj.EPSG4326, true
+	}
+	i := strings.LastIndexAny(crs, "/:")
+	code, err := strconv.Atoi(crs[i+1:])
+	if err != nil || code <= 0 {
+		return 0, false
+	}
+	return proj.EPSGCode(code), true
+}
+
+// firstAxisIsY returns whether the first of the ordered axes is the
+// northing or latitude.
+func firstAxisIsY(axes []string) bool {
+	if len(axes) == 0 {
+		return false
+	}
+	switch strings.ToUpper(axes[0]) {
+	case "Y", "N", "LAT", "NORTHING":
+		return true
+	}
+	return false
+}
+
+// prepare checks the tile matrices, sorts them by decreasing scale
+// denominator and computes the levels.
+func (tms *TileMatrixSet) prepare() error {
+	srid, ok := sridFromCRS(tms.CRS)
+	if !ok {
+		return fmt.Errorf("%w: unknown crs %q", ErrInvalidTileMatrixSet, tms.CRS)
+	}
+	if len(tms.TileMatrices) == 0 {
+		return fmt.Errorf("%w: no tile matrices", ErrInvalidTileMatrixSet)
+	}
+	sort.SliceStable(tms.TileMatrices, func(i, j int) bool {
+		return tms.TileMatrices[i].ScaleDenominator > tms.TileMatrices[j].ScaleDenominator
+	})
+
+	metersPerUnit := 1.0
+	if srid == proj.EPSG4326 {
+		// the units are degrees
+		metersPerUnit = metersPerDegree
+	}
+	swap := firstAxisIsY(tms.OrderedAxes)
+
+	levels := make([]tmsLevel, len(tms.TileMatrices))
+	for i, tm := range tms.TileMatrices {
+		if tm.TileWidth == 0 || tm.TileHeight == 0 || tm.MatrixWidth == 0 || tm.MatrixHeight == 0 {
+			return fmt.Errorf("%w: tile matrix %q has an empty size", ErrInvalidTileMatrixSet, tm.ID)
+		}
+		cellSize := tm.CellSize
+		if cellSize == 0 {
+			cellSize = tm.ScaleDenominator * pixelSize / metersPerUnit
+		}
+		if !(cellSize > 0) {
+			return fmt.Errorf("%w: tile matrix %q has no cell size", ErrInvalidTileMatrixSet, tm.ID)
+		}
+		l := tmsLevel{
+			originX:   tm.PointOfOrigin[0],
+			originY:   tm.PointOfOrigin[1],
+			spanX:     cellSize * float64(tm.TileWidth),
+			spanY:     cellSize * float64(tm.TileHeight),
+			width:     tm.MatrixWidth,
+			height:    tm.MatrixHeight,
+			tileWidth: tm.TileWidth,
+		}
+		if swap {
+			l.originX, l.originY = l.originY, l.originX
+		}
+		switch tm.CornerOfOrigin {
+		case "", CornerTopLeft:
+		case CornerBottomLeft:
+			l.bottomLeft = true
+		default:
+			return fmt.Errorf("%w: tile matrix %q has an unknown corner of origin %q", ErrInvalidTileMatrixSet, tm.ID, tm.CornerOfOrigin)
+		}
+		levels[i] = l
+	}
+	tms.srid, tms.levels = srid, levels
+	return nil
+}
+
+// ParseTileMatrixSet returns the tile matrix set of the OGC json definition.
+//
+// Possible errors:
+//
+//	ErrInvalidTileMatrixSet
+func ParseTileMatrixSet(data []byte) (*TileMatrixSet, error) {
+	// The crs is decoded on its own, as it can be a uri or an object with
+	// one; the fields of aux hide the ones of plain.
+	type plain TileMatrixSet
+	aux := struct {
+		*plain
+		CRS          json.RawMessage `json:"crs"`
+		TileMatrices []struct {
+			TileMatrix
+			VariableMatrixWidths json.RawMessage `json:"variableMatrixWidths"`
+		} `json:"tileMatrices"`
+	}{plain: new(plain)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTileMatrixSet, err)
+	}
+	tms := TileMatrixSet(*aux.plain)
+
+	var crs struct {
+		URI string `json:"uri"`
+	}
+	switch {
+	case json.Unmarshal(aux.CRS, &tms.CRS) == nil:
+	case json.Unmarshal(aux.CRS, &crs) == nil:
+		tms.CRS = crs.URI
+	default:
+		return nil, fmt.Errorf("%w: invalid crs", ErrInvalidTileMatrixSet)
+	}
+	for _, tm := range aux.TileMatrices {
+		if len(tm.VariableMatrixWidths) > 0 && string(tm.VariableMatrixWidths) != "null" {
+			return nil, fmt.Errorf("%w: tile matrix %q has variable matrix widths, which are not supported", ErrInvalidTileMatrixSet, tm.ID)
+		}
+		tms.TileMatrices = append(tms.TileMatrices, tm.TileMatrix)
+	}
+	if err := tms.prepare(); err != nil {
+		return nil, err
+	}
+	return &tms, nil
+}
+
+// level returns the level for the zoom.
+func (tms *TileMatrixSet) level(z Zoom) (tmsLevel, error) {
+	if int(z) >= len(tms.levels) {
+		return tmsLevel{}, fmt.Errorf("%w: zoom %v is not in tile matrix set %v", ErrTileOutOfRange, z, tms.ID)
+	}
+	return tms.levels[z], nil
+}
+
+// SRID returns the EPSG code of the CRS of the set; 4326 for CRS84.
+func (tms *TileMatrixSet) SRID() proj.EPSGCode { return tms.srid }
+
+// Size returns the number of columns and rows of the tile matrix at the zoom.
+func (tms *TileMatrixSet) Size(z Zoom) (Tile, bool) {
+	l, err := tms.level(z)
+	if err != nil {
+		return Tile{}, false
+	}
+	return Tile{Z: z, X: l.width, Y: l.height}, true
+}
+
+// FromNative returns the tile containing the point; points outside of the
+// tile matrix are in the tiles along its edges.
+func (tms *TileMatrixSet) FromNative(z Zoom, pt geom.Point) (Tile, error) {
+	l, err := tms.level(z)
+	if err != nil {
+		return Tile{}, err
+	}
+	col := (pt[0] - l.originX) / l.spanX
+	row := (l.originY - pt[1]) / l.spanY
+	if l.bottomLeft {
+		row = -row
+	}
+	// nudge points on the edge of a tile into it; see floatVariance
+	nudge := floatVariance / float64(l.tileWidth)
+	return Tile{
+		Z: z,
+		X: clampIndex(col+nudge, l.width),
+		Y: clampIndex(row+nudge, l.height),
+	}, nil
+}
+
+func clampIndex(v float64, n uint) uint {
+	switch {
+	case !(v > 0):
+		return 0
+	case v >= float64(n):
+		return n - 1
+	default:
+		return uint(v)
+	}
+}
+
+// ToNative returns the corner of the tile at the corner of origin of the
+// tile matrix; the top left corner unless the origin is the bottom left. As
+// the interface requires, the tiles one past the last column and row are
+// allowed, so Extent works for all of the tiles.
+func (tms *TileMatrixSet) ToNative(tile Tile) (geom.Point, error) {
+	l, err := tms.level(tile.Z)
+	if err != nil {
+		return geom.Point{}, err
+	}
+	if tile.X > l.width || tile.Y > l.height {
+		return geom.Point{}, fmt.Errorf("%w: tile %v", ErrTileOutOfRange, tile)
+	}
+	y := l.originY - float64(tile.Y)*l.spanY
+	if l.bottomLeft {
+		y = l.originY + float64(tile.Y)*l.spanY
+	}
+	return geom.Point{l.originX + float64(tile.X)*l.spanX, y}, nil
+}
+
+// newQuadTileMatrixSet returns a set where each level doubles the number of
+// rows and columns of the previous one.
+func newQuadTileMatrixSet(id, title, crs string, firstID, levels int, scale, cellSize float64, origin [2]float64, width, height uint) *TileMatrixSet {
+	tms := &TileMatrixSet{
+		ID:    id,
+		Title: title,
+		URI:   "http://www.opengis.net/def/tilematrixset/OGC/1.0/" + id,
+		CRS:   crs,
+	}
+	for i := 0; i < levels; i++ {
+		f := math.Exp2(float64(i))
+		tms.TileMatrices = append(tms.TileMatrices, TileMatrix{
+			ID:               strconv.Itoa(firstID + i),
+			ScaleDenominator: scale / f,
+			CellSize:         cellSize / f,
+			CornerOfOrigin:   CornerTopLeft,
+			PointOfOrigin:    origin,
+			TileWidth:        DefaultTileSize,
+			TileHeight:       DefaultTileSize,
+			MatrixWidth:      width << uint(i),
+			MatrixHeight:     height << uint(i),
+		})
+	}
+	if err := tms.prepare(); err != nil {
+		panic("Assumption broken:" + err.Error())
+	}
+	return tms
+}
+
+// The well known tile matrix sets of the OGC standard.
+var (
+	// WebMercatorQuad is the usual web mercator tiling, in EPSG:3857
+	// coordinates; the same tiles as NewGrid(3857, 0).
+	WebMercatorQuad = newQuadTileMatrixSet(
+		"WebMercatorQuad", "Google Maps Compatible for the World",
+		"http://www.opengis.net/def/crs/EPSG/0/3857",
+		0, 25, 559082264.028717, 156543.033928041,
+		[2]float64{-20037508.3427892, 20037508.3427892}, 1, 1,
+	)
+	// WorldCRS84Quad is the equirectangular tiling of the world, in longitude
+	// and latitude, with two tiles at zoom 0.
+	WorldCRS84Quad = newQuadTileMatrixSet(
+		"WorldCRS84Quad", "CRS84 for the World",
+		"http://www.opengis.net/def/crs/OGC/1.3/CRS84",
+		0, 24, 279541132.014358, 0.703125,
+		[2]float64{-180, 90}, 2, 1,
+	)
+)
+
+// UTMWGS84Quad returns the well known tile matrix set for the northern UTM
+// zone, in the coordinates of the zone's EPSG:326xx CRS. The identifiers of
+// the tile matrices start at 1, which is zoom 0.
+//
+// Possible errors:
+//
+//	ErrInvalidTileMatrixSet
+func UTMWGS84Quad(zone int) (*TileMatrixSet, error) {
+	if zone < 1 || zone > 60 {
+		return nil, fmt.Errorf("%w: invalid utm zone %v", ErrInvalidTileMatrixSet, zone)
+	}
+	return newQuadTileMatrixSet(
+		fmt.Sprintf("UTM%02dWGS84Quad", zone), fmt.Sprintf("Permissive tile matrix set for UTM WGS84 zone %02d", zone),
+		fmt.Sprintf("http://www.opengis.net/def/crs/EPSG/0/%d", 32600+zone),
+		1, 24, 279541132.014358, 78271.5169640204,
+		[2]float64{-9501965.72931276, 20003931.4586255}, 1, 1,
+	), nil
+}
+
+// TileMatrixSetByID returns the well known tile matrix set with the id;
+// WebMercatorQuad, WorldCRS84Quad or UTMxxWGS84Quad.
+func TileMatrixSetByID(id string) (*TileMatrixSet, bool) {
+	switch id {
+	case WebMercatorQuad.ID:
+		return WebMercatorQuad, true
+	case WorldCRS84Quad.ID:
+		return WorldCRS84Quad, true
+	}
+	var zone int
+	if n, err := fmt.Sscanf(id, "UTM%02dWGS84Quad", &zone); err == nil && n == 1 {
+		tms, err := UTMWGS84Quad(zone)
+		if err == nil && tms.ID == id {
+			return tms, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,193 @@
+package slippy
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/proj"
+)
+
+// swissGrid is a national grid with levels that are not powers of two, in
+// the style of the EPSG:2056 (LV95) tile matrix set.
+const swissGrid = `{
+	"id": "SwissLV95",
+	"crs": {"uri": "http://www.opengis.net/def/crs/EPSG/0/2056"},
+	"orderedAxes": ["E", "N"],
+	"tileMatrices": [
+		{"id": "1", "scaleDenominator": 14285714.2857, "cellSize": 4000, "pointOfOrigin": [2420000, 1350000],
+			"tileWidth": 256, "tileHeight": 256, "matrixWidth": 1, "matrixHeight": 1},
+		{"id": "0", "scaleDenominator": 14642857.1429, "cellSize": 4100, "pointOfOrigin": [2420000, 1350000],
+			"tileWidth": 256, "tileHeight": 256, "matrixWidth": 1, "matrixHeight": 1},
+		{"id": "2", "scaleDenominator": 892857.142857, "cellSize": 250, "pointOfOrigin": [2420000, 1350000],
+			"tileWidth": 256, "tileHeight": 256, "matrixWidth": 8, "matrixHeight": 5}
+	]
+}`
+
+func TestParseTileMatrixSet(t *testing.T) {
+	tms, err := ParseTileMatrixSet([]byte(swissGrid))
+	if err != nil {
+		t.Fatalf("error expected nil, got %v", err)
+	}
+	if tms.SRID() != 2056 {
+		t.Errorf("srid expected 2056, got %v", tms.SRID())
+	}
+	// the levels are sorted by scale
+	if tms.TileMatrices[0].ID != "0" || tms.TileMatrices[2].ID != "2" {
+		t.Errorf("tile matrices, expected to be sorted by scale, got %v", tms.TileMatrices)
+	}
+	if size, ok := tms.Size(2); !ok || size != (Tile{Z: 2, X: 8, Y: 5}) {
+		t.Errorf("size expected 2/8/5, got %v %v", size, ok)
+	}
+	if _, ok := tms.Size(3); ok {
+		t.Errorf("size of zoom 3, expected none")
+	}
+
+	ext, err := Extent(tms, Tile{Z: 2, X: 1, Y: 2})
+	if err != nil {
+		t.Fatalf("error expected nil, got %v", err)
+	}
+	expected := geom.Extent{2420000 + 64000, 1350000 - 3*64000, 2420000 + 2*64000, 1350000 - 2*64000}
+	if *ext != expected {
+		t.Errorf("extent expected %v, got %v", expected, *ext)
+	}
+
+	// Bern to Zurich
+	tiles, err := FromBounds(tms, &geom.Extent{2600000, 1200000, 2683000, 1248000}, 2)
+	if err != nil {
+		t.Fatalf("error expected nil, got %v", err)
+	}
+	expectedTiles := []Tile{{Z: 2, X: 2, Y: 1}, {Z: 2, X: 2, Y: 2}, {Z: 2, X: 3, Y: 1}, {Z: 2, X: 3, Y: 2}, {Z: 2, X: 4, Y: 1}, {Z: 2, X: 4, Y: 2}}
+	if len(tiles) != len(expectedTiles) {
+		t.Fatalf("tiles expected %v, got %v", expectedTiles, tiles)
+	}
+	for i := range tiles {
+		if tiles[i] != expectedTiles[i] {
+			t.Errorf("tiles expected %v, got %v", expectedTiles, tiles)
+		}
+	}
+
+	// points outside of the matrix are in the edge tiles
+	if tile, _ := tms.FromNative(2, geom.Point{0, 0}); tile != (Tile{Z: 2, X: 0, Y: 4}) {
+		t.Errorf("tile expected 2/0/4, got %v", tile)
+	}
+}
+
+func TestParseTileMatrixSetAxes(t *testing.T) {
+	tms, err := ParseTileMatrixSet([]byte(`{
+		"id": "Finland",
+		"crs": "http://www.opengis.net/def/crs/EPSG/0/3067",
+		"orderedAxes": ["N", "E"],
+		"tileMatrices": [
+			{"id": "0", "scaleDenominator": 29257142.85714286, "pointOfOrigin": [8388608, -548576],
+				"tileWidth": 256, "tileHeight": 256, "matrixWidth": 1, "matrixHeight": 1},
+			{"id": "1", "scaleDenominator": 14628571.42857143, "cornerOfOrigin": "topLeft", "pointOfOrigin": [8388608, -548576],
+				"tileWidth": 256, "tileHeight": 256, "matrixWidth": 2, "matrixHeight": 2}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("error expected nil, got %v", err)
+	}
+	// the cell size is computed from the scale denominator
+	ext, err := Extent(tms, Tile{Z: 1, X: 1, Y: 1})
+	if err != nil {
+		t.Fatalf("error expected nil, got %v", err)
+	}
+	expected := geom.Extent{-548576 + 4096*256, 8388608 - 2*4096*256, -548576 + 2*4096*256, 8388608 - 4096*256}
+	for i := range expected {
+		if math.Abs(ext[i]-expected[i]) > 1e-6 {
+			t.Errorf("extent expected %v, got %v", expected, *ext)
+			break
+		}
+	}
+}
+
+func TestParseTileMatrixSetBottomLeft(t *testing.T) {
+	tms, err := ParseTileMatrixSet([]byte(`{
+		"id": "BottomLeft",
+		"crs": "EPSG:3857",
+		"tileMatrices": [
+			{"id": "0", "scaleDenominator": 1000, "cellSize": 1, "cornerOfOrigin": "bottomLeft", "pointOfOrigin": [0, 0],
+				"tileWidth": 10, "tileHeight": 10, "matrixWidth": 3, "matrixHeight": 2}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("error expected nil, got %v", err)
+	}
+	tile, _ := tms.FromNative(0, geom.Point{25, 5})
+	if tile != (Tile{Z: 0, X: 2, Y: 0}) {
+		t.Errorf("tile expected 0/2/0, got %v", tile)
+	}
+	ext, _ := Extent(tms, tile)
+	if *ext != (geom.Extent{20, 0, 30, 10}) {
+		t.Errorf("extent expected [20 0 30 10], got %v", *ext)
+	}
+}
+
+func TestParseTileMatrixSetErrors(t *testing.T) {
+	tcases := map[string]string{
+		"not json":       `{`,
+		"unknown crs":    `{"crs": "LOCAL", "tileMatrices": [{"id": "0", "cellSize": 1, "tileWidth": 1, "tileHeight": 1, "matrixWidth": 1, "matrixHeight": 1}]}`,
+		"no matrices":    `{"crs": "EPSG:3857", "tileMatrices": []}`,
+		"empty matrix":   `{"crs": "EPSG:3857", "tileMatrices": [{"id": "0", "cellSize": 1}]}`,
+		"unknown corner": `{"crs": "EPSG:3857", "tileMatrices": [{"id": "0", "cellSize": 1, "cornerOfOrigin": "middle", "tileWidth": 1, "tileHeight": 1, "matrixWidth": 1, "matrixHeight": 1}]}`,
+		"variable widths": `{"crs": "EPSG:3857", "tileMatrices": [{"id": "0", "cellSize": 1, "tileWidth": 1, "tileHeight": 1, "matrixWidth": 1, "matrixHeight": 1,
+			"variableMatrixWidths": [{"coalesce": 2, "minTileRow": 0, "maxTileRow": 0}]}]}`,
+	}
+	for name, data := range tcases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseTileMatrixSet([]byte(data)); !errors.Is(err, ErrInvalidTileMatrixSet) {
+				t.Errorf("error expected %v, got %v", ErrInvalidTileMatrixSet, err)
+			}
+		})
+	}
+}
+
+func TestWellKnownTileMatrixSets(t *testing.T) {
+	// WebMercatorQuad has the same tiles as the 3857 grid
+	grid := NewGrid(proj.EPSG3857, 0)
+	for _, tile := range []Tile{{Z: 0}, {Z: 3, X: 2, Y: 5}, {Z: 12, X: 655, Y: 1583}} {
+		got, err := Extent(WebMercatorQuad, tile)
+		if err != nil {
+			t.Fatalf("error expected nil, got %v", err)
+		}
+		expected, err := Extent(grid, tile)
+		if err != nil {
+			t.Fatalf("error expected nil, got %v", err)
+		}
+		for i := range expected {
+			if math.Abs(got[i]-expected[i]) > 1e-3 {
+				t.Errorf("%v: extent expected %v, got %v", tile, *expected, *got)
+				break
+			}
+		}
+		center := geom.Point{(got.MinX() + got.MaxX()) / 2, (got.MinY() + got.MaxY()) / 2}
+		if back, _ := WebMercatorQuad.FromNative(tile.Z, center); back != tile {
+			t.Errorf("tile expected %v, got %v", tile, back)
+		}
+	}
+
+	if size, _ := WorldCRS84Quad.Size(0); size != (Tile{Z: 0, X: 2, Y: 1}) {
+		t.Errorf("size expected 0/2/1, got %v", size)
+	}
+	if ext, _ := Extent(WorldCRS84Quad, Tile{Z: 0, X: 1}); *ext != (geom.Extent{0, -90, 180, 90}) {
+		t.Errorf("extent expected [0 -90 180 90], got %v", *ext)
+	}
+	if tile, _ := WorldCRS84Quad.FromNative(1, geom.Point{-100, 40}); tile != (Tile{Z: 1, X: 0, Y: 0}) {
+		t.Errorf("tile expected 1/0/0, got %v", tile)
+	}
+
+	utm, ok := TileMatrixSetByID("UTM31WGS84Quad")
+	if !ok {
+		t.Fatalf("UTM31WGS84Quad, expected to be found")
+	}
+	if utm.SRID() != 32631 || utm.TileMatrices[0].ID != "1" {
+		t.Errorf("srid and first id expected 32631 and 1, got %v and %v", utm.SRID(), utm.TileMatrices[0].ID)
+	}
+	for _, id := range []string{"UTM61WGS84Quad", "UTM1WGS84Quad", "Unknown"} {
+		if _, ok := TileMatrixSetByID(id); ok {
+			t.Errorf("%v, expected not to be found", id)
+		}
+	}
+}
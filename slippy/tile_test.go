@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"testing"
 	"unicode"
@@ -398,3 +399,123 @@ func TestFromBounds2(t *testing.T) {
 	}
 
 }
+
+func TestParseTile(t *testing.T) {
+	type tcase struct {
+		str  string
+		tile Tile
+		err  error
+	}
+
+	fn := tcCurry(func(tc tcase, t *testing.T) {
+		tile, err := ParseTile(tc.str)
+		if err != tc.err {
+			t.Fatalf("error expected %v, got %v", tc.err, err)
+		}
+		if tile != tc.tile {
+			t.Errorf("tile expected %v, got %v", tc.tile, tile)
+		}
+		if err == nil && tile.String() != tc.str {
+			t.Errorf("string expected %v, got %v", tc.str, tile.String())
+		}
+	})
+
+	testcases := map[string]tcase{
+		"zero":          {str: "0/0/0", tile: Tile{}},
+		"tile":          {str: "10/163/395", tile: Tile{Z: 10, X: 163, Y: 395}},
+		"max zoom":      {str: "22/4194303/0", tile: Tile{Z: 22, X: 4194303}},
+		"x out of grid": {str: "2/4/0", err: ErrTileOutOfRange},
+		"y out of grid": {str: "2/0/4", err: ErrTileOutOfRange},
+		"zoom too high": {str: "23/0/0", err: ErrTileOutOfRange},
+		"two parts":     {str: "10/163", err: ErrInvalidTile},
+		"four parts":    {str: "10/163/395/1", err: ErrInvalidTile},
+		"negative":      {str: "10/-1/395", err: ErrInvalidTile},
+		"not a number":  {str: "z/x/y", err: ErrInvalidTile},
+		"empty":         {str: "", err: ErrInvalidTile},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestTileFamily(t *testing.T) {
+	tile := Tile{Z: 3, X: 5, Y: 2}
+
+	parent, ok := tile.Parent()
+	if !ok || parent != (Tile{Z: 2, X: 2, Y: 1}) {
+		t.Errorf("parent expected 2/2/1, got %v %v", parent, ok)
+	}
+	if _, ok := (Tile{}).Parent(); ok {
+		t.Errorf("parent of 0/0/0, expected none")
+	}
+
+	children := parent.Children()
+	expected := [4]Tile{{Z: 3, X: 4, Y: 2}, {Z: 3, X: 5, Y: 2}, {Z: 3, X: 4, Y: 3}, {Z: 3, X: 5, Y: 3}}
+	if children != expected {
+		t.Errorf("children expected %v, got %v", expected, children)
+	}
+	for _, child := range children {
+		if p, _ := child.Parent(); p != parent {
+			t.Errorf("parent of %v expected %v, got %v", child, parent, p)
+		}
+	}
+
+	siblings := tile.Siblings()
+	if !reflect.DeepEqual(siblings, []Tile{{Z: 3, X: 4, Y: 2}, {Z: 3, X: 4, Y: 3}, {Z: 3, X: 5, Y: 3}}) {
+		t.Errorf("siblings, got %v", siblings)
+	}
+	if siblings := (Tile{}).Siblings(); siblings != nil {
+		t.Errorf("siblings of 0/0/0 expected none, got %v", siblings)
+	}
+}
+
+func TestTileNeighbors(t *testing.T) {
+	type tcase struct {
+		tile      Tile
+		neighbors []Tile
+	}
+
+	fn := tcCurry(func(tc tcase, t *testing.T) {
+		neighbors := tc.tile.Neighbors()
+		if !reflect.DeepEqual(neighbors, tc.neighbors) {
+			t.Errorf("neighbors expected %v, got %v", tc.neighbors, neighbors)
+		}
+	})
+
+	testcases := map[string]tcase{
+		"middle": {
+			tile: Tile{Z: 3, X: 4, Y: 4},
+			neighbors: []Tile{
+				{Z: 3, X: 4, Y: 3}, {Z: 3, X: 5, Y: 3}, {Z: 3, X: 5, Y: 4}, {Z: 3, X: 5, Y: 5},
+				{Z: 3, X: 4, Y: 5}, {Z: 3, X: 3, Y: 5}, {Z: 3, X: 3, Y: 4}, {Z: 3, X: 3, Y: 3},
+			},
+		},
+		"antimeridian": {
+			tile: Tile{Z: 3, X: 7, Y: 4},
+			neighbors: []Tile{
+				{Z: 3, X: 7, Y: 3}, {Z: 3, X: 0, Y: 3}, {Z: 3, X: 0, Y: 4}, {Z: 3, X: 0, Y: 5},
+				{Z: 3, X: 7, Y: 5}, {Z: 3, X: 6, Y: 5}, {Z: 3, X: 6, Y: 4}, {Z: 3, X: 6, Y: 3},
+			},
+		},
+		"top left": {
+			tile: Tile{Z: 3, X: 0, Y: 0},
+			neighbors: []Tile{
+				{Z: 3, X: 1, Y: 0}, {Z: 3, X: 1, Y: 1}, {Z: 3, X: 0, Y: 1},
+				{Z: 3, X: 7, Y: 1}, {Z: 3, X: 7, Y: 0},
+			},
+		},
+		"zoom 1": {
+			tile:      Tile{Z: 1, X: 0, Y: 0},
+			neighbors: []Tile{{Z: 1, X: 1, Y: 0}, {Z: 1, X: 1, Y: 1}, {Z: 1, X: 0, Y: 1}},
+		},
+		"zoom 0": {
+			tile:      Tile{},
+			neighbors: []Tile{},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, fn(tc))
+	}
+}
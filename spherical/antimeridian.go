@@ -0,0 +1,435 @@
+package spherical
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/intersect"
+	"github.com/go-spatial/geom/planar/polygonize"
+)
+
+// WrapLongitude returns the longitude wrapped into the range [-180, 180].
+func WrapLongitude(lng float64) float64 {
+	if lng >= -180 && lng <= 180 {
+		return lng
+	}
+	return math.Remainder(lng, 360)
+}
+
+// lngDelta returns the shortest change in longitude going from a to b.
+func lngDelta(a, b float64) float64 {
+	return math.Remainder(b-a, 360)
+}
+
+// unwrap returns the points of the linestring, with the first point wrapped
+// into [-180, 180] and each following point at the shortest longitude from
+// the previous point; so longitudes may go past ±180 but consecutive points
+// are never more then 180° apart.
+func unwrap(pts [][2]float64) [][2]float64 {
+	if len(pts) == 0 {
+		return nil
+	}
+	out := make([][2]float64, len(pts))
+	out[0] = [2]float64{WrapLongitude(pts[0][0]), pts[0][1]}
+	for i := 1; i < len(pts); i++ {
+		out[i] = [2]float64{out[i-1][0] + lngDelta(pts[i-1][0], pts[i][0]), pts[i][1]}
+	}
+	return out
+}
+
+// openRing returns the ring without the closing point, if it has one.
+func openRing(ring [][2]float64) [][2]float64 {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		return ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// shiftLng returns the points moved by the given longitude.
+func shiftLng(pts [][2]float64, by float64) [][2]float64 {
+	out := make([][2]float64, len(pts))
+	for i := range pts {
+		out[i] = [2]float64{pts[i][0] + by, pts[i][1]}
+	}
+	return out
+}
+
+// strip returns the index of the 360° wide strip, centered on 0, containing
+// the longitude.
+func strip(lng float64) float64 {
+	return math.Floor((lng + 180) / 360)
+}
+
+// inStrip returns the strip containing all of the points, if there is one.
+// Points on the edge of a strip are in both strips.
+func inStrip(pts ...[2]float64) (s float64, ok bool) {
+	if len(pts) == 0 {
+		return 0, true
+	}
+	minx, maxx := pts[0][0], pts[0][0]
+	for _, pt := range pts[1:] {
+		minx, maxx = math.Min(minx, pt[0]), math.Max(maxx, pt[0])
+	}
+	s = strip((minx + maxx) / 2)
+	return s, minx >= 360*s-180 && maxx <= 360*s+180
+}
+
+// polarLatitude returns the latitude of the pole, ±90, the ring goes around,
+// or zero if it does not go around a pole. A ring that goes all the way round
+// the globe is taken to contain the pole that it is closest to.
+func polarLatitude(ring [][2]float64) float64 {
+	ring = openRing(ring)
+	if len(ring) < 3 {
+		return 0
+	}
+	var total, lat float64
+	for i := range ring {
+		total += lngDelta(ring[i][0], ring[(i+1)%len(ring)][0])
+		lat += ring[i][1]
+	}
+	if math.Abs(total) < 180 {
+		return 0
+	}
+	return math.Copysign(90, lat)
+}
+
+// unwrapPolygon unwraps the rings of the polygon, moving the holes to be
+// close to the exterior ring. The rings are returned without a closing point.
+// If the exterior ring goes around a pole, polar is the latitude of the pole
+// and the exterior ring ends 360° from where it started.
+func unwrapPolygon(plyg [][][2]float64) (rings [][][2]float64, polar float64) {
+	if len(plyg) == 0 {
+		return nil, 0
+	}
+	shell := unwrap(openRing(plyg[0]))
+	if len(shell) < 3 {
+		return [][][2]float64{shell}, 0
+	}
+	if polar = polarLatitude(shell); polar != 0 {
+		// close the ring at the same point, 360° away.
+		last := shell[len(shell)-1]
+		total := last[0] + lngDelta(last[0], shell[0][0]) - shell[0][0]
+		shell = append(shell, [2]float64{shell[0][0] + total, shell[0][1]})
+	}
+
+	minx, maxx := shell[0][0], shell[0][0]
+	for _, pt := range shell {
+		minx, maxx = math.Min(minx, pt[0]), math.Max(maxx, pt[0])
+	}
+	center := (minx + maxx) / 2
+	rings = append(rings, shell)
+	for _, hole := range plyg[1:] {
+		hole = unwrap(openRing(hole))
+		if len(hole) == 0 {
+			continue
+		}
+		rings = append(rings, shiftLng(hole, 360*math.Round((center-hole[0][0])/360)))
+	}
+	return rings, polar
+}
+
+// NormalizeLongitudes returns a new geometry, of the same type, where the
+// longitudes of each linestring and ring are unwrapped: the first point is in
+// the range [-180, 180], and every following point is at the shortest
+// distance, in longitude, from the previous point. This means a linestring
+// going from 179° to -179° will go from 179° to 181°. The holes of polygons
+// are moved to be next to the exterior ring. Points are wrapped into the
+// range [-180, 180].
+func NormalizeLongitudes(g geom.Geometry) (geom.Geometry, error) {
+	switch geo := g.(type) {
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+
+	case geom.Point:
+		return geom.Point{WrapLongitude(geo[0]), geo[1]}, nil
+
+	case geom.MultiPoint:
+		mp := make(geom.MultiPoint, len(geo))
+		for i := range geo {
+			mp[i] = [2]float64{WrapLongitude(geo[i][0]), geo[i][1]}
+		}
+		return mp, nil
+
+	case geom.LineString:
+		return geom.LineString(unwrap(geo)), nil
+
+	case geom.MultiLineString:
+		mls := make(geom.MultiLineString, len(geo))
+		for i := range geo {
+			mls[i] = unwrap(geo[i])
+		}
+		return mls, nil
+
+	case geom.Polygon:
+		return normalizePolygon(geo), nil
+
+	case geom.MultiPolygon:
+		mp := make(geom.MultiPolygon, len(geo))
+		for i := range geo {
+			mp[i] = normalizePolygon(geo[i])
+		}
+		return mp, nil
+
+	case geom.Collection:
+		col := make(geom.Collection, len(geo))
+		for i := range geo {
+			var err error
+			if col[i], err = NormalizeLongitudes(geo[i]); err != nil {
+				return nil, err
+			}
+		}
+		return col, nil
+	}
+}
+
+func normalizePolygon(plyg [][][2]float64) geom.Polygon {
+	rings := make(geom.Polygon, 0, len(plyg))
+	for i, ring := range plyg {
+		ring = unwrap(ring)
+		if i > 0 && len(ring) > 0 && len(rings) > 0 && len(rings[0]) > 0 {
+			ring = shiftLng(ring, 360*math.Round((rings[0][0][0]-ring[0][0])/360))
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// SplitAntimeridian returns the geometry cut where it crosses the
+// antimeridian (±180°), with all longitudes in the range [-180, 180]. Each
+// segment is assumed to take the shortest path in longitude. Cut points are
+// linearly interpolated in longitude and latitude.
+//
+// Linestrings that cross the antimeridian are returned as multilinestrings,
+// and polygons that cross it as multipolygons; otherwise the geometry type is
+// kept. A polygon whose exterior ring goes all the way around the globe is
+// taken to enclose the pole closest to it, and is extended to that pole. The
+// returned polygons of a split polygon have their exterior rings clockwise.
+func SplitAntimeridian(g geom.Geometry) (geom.Geometry, error) {
+	switch geo := g.(type) {
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+
+	case geom.Point, geom.MultiPoint:
+		return NormalizeLongitudes(geo)
+
+	case geom.LineString:
+		mls := splitLineString(geo)
+		if len(mls) == 1 {
+			return geom.LineString(mls[0]), nil
+		}
+		return geom.MultiLineString(mls), nil
+
+	case geom.MultiLineString:
+		var mls geom.MultiLineString
+		for _, ls := range geo {
+			mls = append(mls, splitLineString(ls)...)
+		}
+		return mls, nil
+
+	case geom.Polygon:
+		mp, err := splitPolygon(geo)
+		if err != nil {
+			return nil, err
+		}
+		if len(mp) == 1 {
+			return geom.Polygon(mp[0]), nil
+		}
+		return mp, nil
+
+	case geom.MultiPolygon:
+		var mp geom.MultiPolygon
+		for _, plyg := range geo {
+			parts, err := splitPolygon(plyg)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, parts...)
+		}
+		return mp, nil
+
+	case geom.Collection:
+		col := make(geom.Collection, len(geo))
+		for i := range geo {
+			var err error
+			if col[i], err = SplitAntimeridian(geo[i]); err != nil {
+				return nil, err
+			}
+		}
+		return col, nil
+	}
+}
+
+// splitLineString splits the linestring into the parts that are in each
+// 360° strip, and moves each part into [-180, 180].
+func splitLineString(ls [][2]float64) (mls [][][2]float64) {
+	pts := unwrap(ls)
+	if s, ok := inStrip(pts...); ok {
+		return [][][2]float64{shiftLng(pts, -360*s)}
+	}
+
+	var (
+		part      [][2]float64
+		partStrip float64
+	)
+	addSegment := func(a, b [2]float64) {
+		s := strip((a[0] + b[0]) / 2)
+		if len(part) != 0 && s != partStrip {
+			mls = append(mls, shiftLng(part, -360*partStrip))
+			part = nil
+		}
+		if len(part) == 0 {
+			part, partStrip = append(part, a), s
+		}
+		part = append(part, b)
+	}
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		// cut the segment at each of the antimeridians it crosses.
+		for _, x := range antimeridiansBetween(a[0], b[0]) {
+			pt := [2]float64{x, a[1] + (b[1]-a[1])*(x-a[0])/(b[0]-a[0])}
+			addSegment(a, pt)
+			a = pt
+		}
+		addSegment(a, b)
+	}
+	if len(part) != 0 {
+		mls = append(mls, shiftLng(part, -360*partStrip))
+	}
+	return mls
+}
+
+// antimeridiansBetween returns the longitudes of the antimeridians strictly
+// between a and b, in order going from a to b.
+func antimeridiansBetween(a, b float64) (xs []float64) {
+	lo, hi := math.Min(a, b), math.Max(a, b)
+	for x := 360*math.Floor((lo+180)/360) + 180; x < hi; x += 360 {
+		if x > lo {
+			xs = append(xs, x)
+		}
+	}
+	if a > b {
+		for i, j := 0, len(xs)-1; i < j; i, j = i+1, j-1 {
+			xs[i], xs[j] = xs[j], xs[i]
+		}
+	}
+	return xs
+}
+
+// splitPolygon splits the polygon into the parts that are in each 360° strip,
+// and moves each part into [-180, 180].
+func splitPolygon(plyg [][][2]float64) (geom.MultiPolygon, error) {
+	rings, polar := unwrapPolygon(plyg)
+	if len(rings) == 0 {
+		return nil, nil
+	}
+	if polar != 0 {
+		shell := rings[0]
+		first, last := shell[0], shell[len(shell)-1]
+		rings[0] = append(shell, [2]float64{last[0], polar}, [2]float64{first[0], polar})
+	}
+
+	var all [][2]float64
+	for _, ring := range rings {
+		all = append(all, ring...)
+	}
+	if s, ok := inStrip(all...); ok {
+		plyg := make(geom.Polygon, len(rings))
+		for i := range rings {
+			plyg[i] = shiftLng(rings[i], -360*s)
+		}
+		return geom.MultiPolygon{plyg}, nil
+	}
+
+	// Use all the edges of the rings, and the antimeridians, to build the
+	// faces the polygon is cut into.
+	var lines []geom.Line
+	ext := geom.NewExtent(all...)
+	for _, ring := range rings {
+		for i := range ring {
+			lines = append(lines, geom.Line{ring[i], ring[(i+1)%len(ring)]})
+		}
+	}
+	for _, x := range antimeridiansBetween(ext.MinX(), ext.MaxX()) {
+		lines = append(lines, geom.Line{{x, ext.MinY()}, {x, ext.MaxY()}})
+	}
+	result, err := polygonize.Polygonize(context.Background(), lines)
+	if err != nil {
+		return nil, err
+	}
+
+	// the faces inside the polygon are the ones inside the exterior ring, and
+	// not inside one of the holes.
+	irings := make([]*intersect.Ring, len(rings))
+	for i := range rings {
+		irings[i] = intersect.NewRingFromPoints(rings[i]...)
+	}
+	inside := func(pt [2]float64) bool {
+		if !irings[0].ContainsPoint(pt) {
+			return false
+		}
+		for _, hole := range irings[1:] {
+			if hole.ContainsPoint(pt) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var mp geom.MultiPolygon
+	for _, face := range result.Polygons {
+		pt, ok := interiorPoint(face)
+		if !ok || !inside(pt) {
+			continue
+		}
+		s := strip(pt[0])
+		part := make(geom.Polygon, len(face))
+		for i := range face {
+			part[i] = shiftLng(face[i], -360*s)
+		}
+		mp = append(mp, part)
+	}
+	return mp, nil
+}
+
+// interiorPoint returns a point that is inside the polygon, and not in any of
+// its holes.
+func interiorPoint(plyg [][][2]float64) (pt [2]float64, ok bool) {
+	if len(plyg) == 0 || len(plyg[0]) < 3 {
+		return pt, false
+	}
+	// Use a horizontal line, half way between the two vertices of the
+	// exterior ring that are furthest apart in latitude, so it does not go
+	// through any of the vertices of the exterior ring.
+	ys := make([]float64, len(plyg[0]))
+	for i := range plyg[0] {
+		ys[i] = plyg[0][i][1]
+	}
+	sort.Float64s(ys)
+	var y, gap float64
+	for i := 1; i < len(ys); i++ {
+		if d := ys[i] - ys[i-1]; d > gap {
+			y, gap = (ys[i]+ys[i-1])/2, d
+		}
+	}
+	if gap == 0 {
+		return pt, false
+	}
+	var xs []float64
+	for _, ring := range plyg {
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if (a[1] > y) != (b[1] > y) {
+				xs = append(xs, a[0]+(y-a[1])*(b[0]-a[0])/(b[1]-a[1]))
+			}
+		}
+	}
+	if len(xs) < 2 {
+		return pt, false
+	}
+	sort.Float64s(xs)
+	// the first crossing goes into the polygon, and the second one out of
+	// it, or into a hole.
+	return [2]float64{(xs[0] + xs[1]) / 2, y}, true
+}
@@ -0,0 +1,251 @@
+package spherical
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestNormalizeLongitudes(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		expected geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := NormalizeLongitudes(tc.geom)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("normalize, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"point": {
+			geom:     geom.Point{190, 10},
+			expected: geom.Point{-170, 10},
+		},
+		"linestring": {
+			geom:     geom.LineString{{179, 0}, {-179, 1}, {-170, 2}},
+			expected: geom.LineString{{179, 0}, {181, 1}, {190, 2}},
+		},
+		"linestring west": {
+			geom:     geom.LineString{{-179, 0}, {179, 1}},
+			expected: geom.LineString{{-179, 0}, {-181, 1}},
+		},
+		"polygon with hole": {
+			geom: geom.Polygon{
+				{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}},
+				{{-178, 2}, {178, 2}, {178, 8}},
+			},
+			expected: geom.Polygon{
+				{{170, 0}, {190, 0}, {190, 10}, {170, 10}},
+				{{182, 2}, {178, 2}, {178, 8}},
+			},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+// sortParts makes the order of the parts of a split geometry predictable.
+func sortParts(g geom.Geometry) geom.Geometry {
+	switch geo := g.(type) {
+	case geom.MultiLineString:
+		sort.Slice(geo, func(i, j int) bool { return geo[i][0][0] < geo[j][0][0] })
+	case geom.MultiPolygon:
+		sort.Slice(geo, func(i, j int) bool {
+			return geom.NewExtent(geo[i][0]...).MinX() < geom.NewExtent(geo[j][0]...).MinX()
+		})
+	}
+	return g
+}
+
+func TestSplitAntimeridian(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		expected geom.Geometry
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := SplitAntimeridian(tc.geom)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(sortParts(got), tc.expected) {
+				t.Errorf("split, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"linestring not crossing": {
+			geom:     geom.LineString{{170, 0}, {175, 5}},
+			expected: geom.LineString{{170, 0}, {175, 5}},
+		},
+		"linestring crossing": {
+			geom: geom.LineString{{179, 0}, {-179, 2}},
+			expected: geom.MultiLineString{
+				{{-180, 1}, {-179, 2}},
+				{{179, 0}, {180, 1}},
+			},
+		},
+		"linestring crossing back": {
+			geom: geom.LineString{{170, 0}, {-170, 0}, {170, 10}},
+			expected: geom.MultiLineString{
+				{{-180, 0}, {-170, 0}, {-180, 5}},
+				{{170, 0}, {180, 0}},
+				{{180, 5}, {170, 10}},
+			},
+		},
+		"linestring ending on antimeridian": {
+			geom:     geom.LineString{{170, 0}, {180, 0}},
+			expected: geom.LineString{{170, 0}, {180, 0}},
+		},
+		"polygon not crossing": {
+			geom:     geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+			expected: geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}},
+		},
+		"polygon crossing": {
+			geom: geom.Polygon{{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}}},
+			expected: geom.MultiPolygon{
+				{{{-170, 10}, {-170, 0}, {-180, 0}, {-180, 10}}},
+				{{{180, 10}, {180, 0}, {170, 0}, {170, 10}}},
+			},
+		},
+		"polygon around the north pole": {
+			geom: geom.Polygon{{{-120, 80}, {0, 80}, {120, 80}}},
+			expected: geom.MultiPolygon{
+				{{{-120, 90}, {-120, 80}, {-180, 80}, {-180, 90}}},
+				{{{180, 90}, {180, 80}, {120, 80}, {0, 80}, {-120, 80}, {-120, 90}}},
+			},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestSplitAntimeridianWithHole(t *testing.T) {
+	plyg := geom.Polygon{
+		{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}},
+		{{175, -5}, {175, 5}, {-175, 5}, {-175, -5}},
+	}
+	got, err := SplitAntimeridian(plyg)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	mp, ok := got.(geom.MultiPolygon)
+	if !ok || len(mp) != 2 {
+		t.Fatalf("split, expected a multipolygon with 2 polygons got %v", got)
+	}
+	// the hole is cut as well, so neither part has a hole, and the area is
+	// kept.
+	var area float64
+	for _, p := range mp {
+		if len(p) != 1 {
+			t.Errorf("number of rings, expected 1 got %v", len(p))
+		}
+		for _, pt := range p[0] {
+			if pt[0] < -180 || pt[0] > 180 {
+				t.Errorf("longitude %v is out of range", pt[0])
+			}
+		}
+		area += math.Abs(planarRingArea(p[0]))
+	}
+	if area != 300 {
+		t.Errorf("area, expected 300 got %v", area)
+	}
+}
+
+func planarRingArea(ring [][2]float64) (area float64) {
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		area += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	return area / 2
+}
+
+func TestGeometryExtent(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		expected *geom.Extent
+		wrapped  bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := GeometryExtent(tc.geom)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("extent, expected %v got %v", tc.expected, got)
+			}
+			if IsWrapped(got) != tc.wrapped {
+				t.Errorf("wrapped, expected %v got %v", tc.wrapped, IsWrapped(got))
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"not crossing": {
+			geom:     geom.LineString{{-10, 0}, {10, 5}},
+			expected: &geom.Extent{-10, 0, 10, 5},
+		},
+		"crossing": {
+			geom:     geom.LineString{{170, 0}, {-170, 5}},
+			expected: &geom.Extent{170, 0, -170, 5},
+			wrapped:  true,
+		},
+		"points": {
+			geom:     geom.MultiPoint{{-175, 0}, {175, 5}, {-178, 2}},
+			expected: &geom.Extent{175, 0, -175, 5},
+			wrapped:  true,
+		},
+		"polar": {
+			geom:     geom.Polygon{{{-120, 80}, {0, 80}, {120, 80}}},
+			expected: &geom.Extent{-180, 80, 180, 90},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestWrappedExtent(t *testing.T) {
+	e := Hull([2]float64{170, 0}, [2]float64{-170, 10})
+	if !IsWrapped(e) {
+		t.Fatalf("wrapped, expected true got false for %v", e)
+	}
+	if w := ExtentWidth(e); w != 20 {
+		t.Errorf("width, expected 20 got %v", w)
+	}
+	for _, pt := range [][2]float64{{175, 5}, {-175, 5}, {180, 5}, {-180, 5}} {
+		if !ExtentContainsPoint(e, pt) {
+			t.Errorf("contains %v, expected true got false", pt)
+		}
+	}
+	for _, pt := range [][2]float64{{0, 5}, {175, 15}} {
+		if ExtentContainsPoint(e, pt) {
+			t.Errorf("contains %v, expected false got true", pt)
+		}
+	}
+	parts := SplitExtent(e)
+	expected := []*geom.Extent{{170, 0, 180, 10}, {-180, 0, -170, 10}}
+	if !reflect.DeepEqual(parts, expected) {
+		t.Errorf("split, expected %v got %v", expected, parts)
+	}
+}
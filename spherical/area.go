@@ -0,0 +1,178 @@
+package spherical
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/coord"
+)
+
+// ringExcess returns the spherical excess, in steradians, of the ring,
+// treating the edges as great circle arcs. Longitudes and latitudes are in
+// radians. The area is signed, and is measured to the equator, so for rings
+// that go around a pole it is not the area of the ring.
+func ringExcess(ring [][2]float64) float64 {
+	var sum float64
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		dlng := math.Remainder(b[0]-a[0], 2*math.Pi)
+		ta, tb := math.Tan(a[1]/2), math.Tan(b[1]/2)
+		sum += 2 * math.Atan2(math.Tan(dlng/2)*(ta+tb), 1+ta*tb)
+	}
+	return sum
+}
+
+// ringArea returns the area of the ring, in steradians. The lat function is
+// used to convert each latitude before computing the area.
+func ringArea(ring [][2]float64, lat func(float64) float64) float64 {
+	ring = openRing(ring)
+	if len(ring) < 3 {
+		return 0
+	}
+	rad := make([][2]float64, len(ring))
+	for i, pt := range ring {
+		rad[i] = [2]float64{pt[0] * math.Pi / 180, lat(pt[1] * math.Pi / 180)}
+	}
+	excess := math.Abs(ringExcess(rad))
+	if polarLatitude(ring) != 0 {
+		// The excess is measured to the equator; the ring contains the
+		// pole on the other side of it.
+		return 2*math.Pi - excess
+	}
+	return excess
+}
+
+// area returns the area, in steradians, of the polygons in the geometry.
+func area(g geom.Geometry, lat func(float64) float64) (float64, error) {
+	polygon := func(plyg [][][2]float64) (a float64) {
+		for i, ring := range plyg {
+			if i == 0 {
+				a += ringArea(ring, lat)
+				continue
+			}
+			a -= ringArea(ring, lat)
+		}
+		return a
+	}
+
+	switch geo := g.(type) {
+	default:
+		return 0, geom.ErrUnknownGeometry{Geom: g}
+	case geom.Pointer, geom.MultiPointer, geom.LineStringer, geom.MultiLineStringer:
+		return 0, nil
+	case geom.Polygoner:
+		return polygon(geo.LinearRings()), nil
+	case geom.MultiPolygoner:
+		var a float64
+		for _, plyg := range geo.Polygons() {
+			a += polygon(plyg)
+		}
+		return a, nil
+	case geom.Collectioner:
+		var a float64
+		for _, child := range geo.Geometries() {
+			ca, err := area(child, lat)
+			if err != nil {
+				return 0, err
+			}
+			a += ca
+		}
+		return a, nil
+	}
+}
+
+// Area returns the area, in square kilometers, of the polygons in the long/lat
+// geometry, on a sphere with a radius of EarthRadiusKm. The edges of the
+// polygons are taken to be great circle arcs, and the area is computed
+// using the spherical excess; so polygons crossing the antimeridian are
+// handled. A ring that goes all the way around the globe is taken to contain
+// the pole it is closest to. Points and lines have no area.
+func Area(g geom.Geometry) (float64, error) {
+	a, err := area(g, func(lat float64) float64 { return lat })
+	if err != nil {
+		return 0, err
+	}
+	return a * EarthRadiusKm * EarthRadiusKm, nil
+}
+
+// EllipsoidArea returns the area of the polygons in the long/lat geometry on
+// the ellipsoid, in the square of the units of the ellipsoid's radius; square
+// meters for the common ellipsoids. The latitudes are converted to authalic
+// latitudes, and the area is computed on the sphere with the same surface
+// area as the ellipsoid. As with Area, the edges are taken to be great circle
+// arcs on that sphere. The ellipsoid's Eccentricity is the square of the
+// eccentricity.
+func EllipsoidArea(g geom.Geometry, ellips coord.Ellipsoid) (float64, error) {
+	e2 := ellips.Eccentricity
+	if e2 == 0 {
+		a, err := area(g, func(lat float64) float64 { return lat })
+		return a * ellips.Radius * ellips.Radius, err
+	}
+	e := math.Sqrt(e2)
+	q := func(sin float64) float64 {
+		return (1 - e2) * (sin/(1-e2*sin*sin) - math.Log((1-e*sin)/(1+e*sin))/(2*e))
+	}
+	qp := q(1)
+	authalic := func(lat float64) float64 {
+		return math.Asin(math.Max(-1, math.Min(1, q(math.Sin(lat))/qp)))
+	}
+	a, err := area(g, authalic)
+	if err != nil {
+		return 0, err
+	}
+	// the radius of the sphere with the same area as the ellipsoid.
+	r2 := ellips.Radius * ellips.Radius * qp / 2
+	return a * r2, nil
+}
+
+// ringContainsLngLat returns whether the ring, with great circle edges,
+// contains the point. It counts the number of times the meridian going north
+// from the point crosses the ring; and uses whether the ring contains the
+// north pole for the parity.
+func ringContainsLngLat(ring [][2]float64, pt [2]float64) bool {
+	ring = openRing(ring)
+	if len(ring) < 3 {
+		return false
+	}
+	inside := polarLatitude(ring) == 90
+	if pt[1] >= 90 {
+		return inside
+	}
+	lng := pt[0] * math.Pi / 180
+	sin, cos := math.Sincos(lng)
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		da, db := lngDelta(pt[0], a[0]), lngDelta(pt[0], b[0])
+		// the edge has to cross the meridian of the point, and not the
+		// meridian on the other side of the globe.
+		if (da > 0) == (db > 0) || math.Abs(db-da) >= 180 {
+			continue
+		}
+		n := toVec3(a).cross(toVec3(b))
+		// latitude where the great circle of the edge crosses the meridian.
+		lat := math.Atan(-(n[0]*cos+n[1]*sin)/n[2]) * 180 / math.Pi
+		if lat > pt[1] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ContainsPoint returns whether the long/lat point is inside the polygon, and
+// not in one of its holes. The edges of the polygon are taken to be great
+// circle arcs, so polygons crossing the antimeridian are handled. A ring that
+// goes all the way around the globe is taken to contain the pole it is
+// closest to. Points on the edges may be reported as either inside or
+// outside.
+func ContainsPoint(plyg geom.Polygoner, pt [2]float64) bool {
+	rings := plyg.LinearRings()
+	if len(rings) == 0 || !ringContainsLngLat(rings[0], pt) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if ringContainsLngLat(hole, pt) {
+			return false
+		}
+	}
+	return true
+}
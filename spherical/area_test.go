@@ -0,0 +1,149 @@
+package spherical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar/coord"
+)
+
+// triangleArea returns the area of the spherical triangle, using the formula
+// by Van Oosterom and Strackee.
+func triangleArea(pa, pb, pc [2]float64) float64 {
+	a, b, c := toVec3(pa), toVec3(pb), toVec3(pc)
+	excess := 2 * math.Atan2(math.Abs(a.dot(b.cross(c))), 1+a.dot(b)+b.dot(c)+c.dot(a))
+	return excess * EarthRadiusKm * EarthRadiusKm
+}
+
+func TestArea(t *testing.T) {
+	type tcase struct {
+		geom     geom.Geometry
+		expected float64
+		// tolerance as a fraction of expected.
+		tolerance float64
+	}
+
+	r2 := EarthRadiusKm * EarthRadiusKm
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Area(tc.geom)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if math.Abs(got-tc.expected) > tc.tolerance*tc.expected {
+				t.Errorf("area, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"octant": {
+			// a triangle covering an eighth of the sphere.
+			geom:      geom.Polygon{{{0, 0}, {90, 0}, {0, 90}}},
+			expected:  4 * math.Pi * r2 / 8,
+			tolerance: 1e-12,
+		},
+		"degree square at the equator": {
+			geom:      geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+			expected:  12363.7,
+			tolerance: 1e-4,
+		},
+		"across the antimeridian": {
+			geom:      geom.Polygon{{{179.5, 0}, {-179.5, 0}, {-179.5, 1}, {179.5, 1}}},
+			expected:  12363.7,
+			tolerance: 1e-4,
+		},
+		"northern hemisphere": {
+			geom:      geom.Polygon{{{0, 0}, {90, 0}, {180, 0}, {-90, 0}}},
+			expected:  2 * math.Pi * r2,
+			tolerance: 1e-12,
+		},
+		"south polar triangle": {
+			geom:      geom.Polygon{{{0, -60}, {120, -60}, {-120, -60}}},
+			expected:  triangleArea([2]float64{0, -60}, [2]float64{120, -60}, [2]float64{-120, -60}),
+			tolerance: 1e-12,
+		},
+		"octant with hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {90, 0}, {0, 90}},
+				{{0, 0}, {90, 0}, {0, 90}},
+			},
+		},
+		"collection": {
+			geom: geom.Collection{
+				geom.Point{0, 0},
+				geom.MultiPolygon{{{{0, 0}, {90, 0}, {0, 90}}}, {{{0, 0}, {-90, 0}, {0, 90}}}},
+			},
+			expected:  4 * math.Pi * r2 / 4,
+			tolerance: 1e-12,
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestEllipsoidArea(t *testing.T) {
+	wgs84 := coord.Ellipsoid{Name: "WGS_84", Radius: 6378137, Eccentricity: 0.00669438}
+	// The total area of the WGS84 ellipsoid is 510065621.7 km²
+	hemisphere := geom.Polygon{{{0, 0}, {90, 0}, {180, 0}, {-90, 0}}}
+	got, err := EllipsoidArea(hemisphere, wgs84)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if expected := 510065621.7e6 / 2; math.Abs(got-expected) > 1e6 {
+		t.Errorf("area, expected %v got %v", expected, got)
+	}
+
+	sphere := coord.Ellipsoid{Radius: EarthRadiusKm}
+	got, err = EllipsoidArea(hemisphere, sphere)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if expected, _ := Area(hemisphere); math.Abs(got-expected) > 1e-6 {
+		t.Errorf("area, expected %v got %v", expected, got)
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	type tcase struct {
+		polygon  geom.Polygon
+		pt       [2]float64
+		expected bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			if got := ContainsPoint(tc.polygon, tc.pt); got != tc.expected {
+				t.Errorf("contains, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	dateline := geom.Polygon{
+		{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}},
+		{{178, -1}, {-178, -1}, {-178, 1}, {178, 1}},
+	}
+	arctic := geom.Polygon{{{-120, 70}, {0, 70}, {120, 70}}}
+	tcases := map[string]tcase{
+		"inside":                          {polygon: square, pt: [2]float64{5, 5}, expected: true},
+		"outside":                         {polygon: square, pt: [2]float64{15, 5}},
+		"outside on the other side":       {polygon: square, pt: [2]float64{-175, -5}},
+		"across the antimeridian east":    {polygon: dateline, pt: [2]float64{175, 5}, expected: true},
+		"across the antimeridian west":    {polygon: dateline, pt: [2]float64{-175, -5}, expected: true},
+		"across the antimeridian outside": {polygon: dateline, pt: [2]float64{0, 5}},
+		"in the hole":                     {polygon: dateline, pt: [2]float64{180, 0}},
+		"polar":                           {polygon: arctic, pt: [2]float64{45, 85}, expected: true},
+		"polar at the pole":               {polygon: arctic, pt: [2]float64{0, 90}, expected: true},
+		"polar outside":                   {polygon: arctic, pt: [2]float64{45, 50}},
+		// the great circle edges bulge towards the pole.
+		"polar great circle": {polygon: arctic, pt: [2]float64{60, 70.5}},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
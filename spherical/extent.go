@@ -2,6 +2,7 @@ package spherical
 
 import (
 	"math"
+	"sort"
 
 	"github.com/go-spatial/geom"
 )
@@ -29,6 +30,7 @@ func Hull(a, b [2]float64) *geom.Extent {
 
 // Extent is an explicit definition of a region of a sphere without taking into account the antimeridian.
 // The extent is a segment of a sphere from two long/lat points, with the first point being the westmost point and the second being the eastmost point; in following format [4]float64{ West, South, East, North }.
+// If the region crosses the antimeridian West will be greater than East, see IsWrapped.
 func Extent(westy, easty [2]float64) *geom.Extent {
 	north, south := westy[1], easty[1]
 	if north < south {
@@ -37,3 +39,147 @@ func Extent(westy, easty [2]float64) *geom.Extent {
 
 	return &geom.Extent{westy[0], south, easty[0], north}
 }
+
+// IsWrapped returns whether the extent crosses the antimeridian; that is its
+// west edge is east of its east edge.
+func IsWrapped(e *geom.Extent) bool {
+	return e != nil && e.MinX() > e.MaxX()
+}
+
+// ExtentWidth returns the width, in degrees of longitude, of the extent;
+// taking into account extents that cross the antimeridian.
+func ExtentWidth(e *geom.Extent) float64 {
+	if IsWrapped(e) {
+		return 360 - e.MinX() + e.MaxX()
+	}
+	return e.XSpan()
+}
+
+// ExtentContainsPoint returns whether the long/lat point is in the extent;
+// taking into account extents that cross the antimeridian.
+func ExtentContainsPoint(e *geom.Extent, pt [2]float64) bool {
+	if e == nil {
+		return false
+	}
+	if pt[1] < e.MinY() || pt[1] > e.MaxY() {
+		return false
+	}
+	lng := WrapLongitude(pt[0])
+	if IsWrapped(e) {
+		return lng >= e.MinX() || lng <= e.MaxX()
+	}
+	return (lng >= e.MinX() && lng <= e.MaxX()) ||
+		// ±180 are the same meridian.
+		(math.Abs(lng) == 180 && (e.MinX() <= -lng && -lng <= e.MaxX()))
+}
+
+// SplitExtent returns the extent as one or two extents that do not cross the
+// antimeridian. An extent that crosses the antimeridian is split into its
+// western part, ending at 180°, and its eastern part, starting at -180°.
+func SplitExtent(e *geom.Extent) []*geom.Extent {
+	if e == nil {
+		return nil
+	}
+	if !IsWrapped(e) {
+		return []*geom.Extent{e.Clone()}
+	}
+	return []*geom.Extent{
+		{e.MinX(), e.MinY(), 180, e.MaxY()},
+		{-180, e.MinY(), e.MaxX(), e.MaxY()},
+	}
+}
+
+// GeometryExtent returns the smallest extent, in long/lat, that contains the
+// geometry. Unlike geom.NewExtentFromGeometry the returned extent may cross
+// the antimeridian, in which case its west edge will be greater then its east
+// edge. Geometries whose linestrings or rings go all the way around the globe
+// will have an extent from -180° to 180°, and polygons that go around a pole
+// will extend to that pole.
+func GeometryExtent(g geom.Geometry) (*geom.Extent, error) {
+	var (
+		lngs     []float64
+		ext      *geom.Extent
+		fullLngs bool
+	)
+	add := func(pts [][2]float64) {
+		for _, pt := range pts {
+			lngs = append(lngs, WrapLongitude(pt[0]))
+		}
+		if ext == nil {
+			ext = geom.NewExtent(pts...)
+		} else {
+			ext.AddPoints(pts...)
+		}
+	}
+	addLine := func(pts [][2]float64) {
+		add(pts)
+		if ls := unwrap(pts); len(ls) > 0 {
+			if e := geom.NewExtent(ls...); e.XSpan() >= 360 {
+				fullLngs = true
+			}
+		}
+	}
+	addPolygon := func(plyg [][][2]float64) {
+		rings, polar := unwrapPolygon(plyg)
+		if len(rings) == 0 {
+			return
+		}
+		addLine(rings[0])
+		if polar != 0 {
+			fullLngs = true
+			ext.AddPoints([2]float64{0, polar})
+		}
+	}
+
+	var walk func(g geom.Geometry) error
+	walk = func(g geom.Geometry) error {
+		switch geo := g.(type) {
+		default:
+			return geom.ErrUnknownGeometry{Geom: g}
+		case geom.Pointer:
+			add([][2]float64{geo.XY()})
+		case geom.MultiPointer:
+			add(geo.Points())
+		case geom.LineStringer:
+			addLine(geo.Vertices())
+		case geom.MultiLineStringer:
+			for _, ls := range geo.LineStrings() {
+				addLine(ls)
+			}
+		case geom.Polygoner:
+			addPolygon(geo.LinearRings())
+		case geom.MultiPolygoner:
+			for _, plyg := range geo.Polygons() {
+				addPolygon(plyg)
+			}
+		case geom.Collectioner:
+			for _, child := range geo.Geometries() {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(g); err != nil {
+		return nil, err
+	}
+	if ext == nil {
+		return nil, nil
+	}
+	if fullLngs || len(lngs) == 0 {
+		return &geom.Extent{-180, ext.MinY(), 180, ext.MaxY()}, nil
+	}
+
+	// The extent is everything except for the largest gap between the
+	// longitudes.
+	sort.Float64s(lngs)
+	west, east := lngs[0], lngs[len(lngs)-1]
+	gap := 360 - (east - west)
+	for i := 1; i < len(lngs); i++ {
+		if d := lngs[i] - lngs[i-1]; d > gap {
+			west, east, gap = lngs[i], lngs[i-1], d
+		}
+	}
+	return &geom.Extent{west, ext.MinY(), east, ext.MaxY()}, nil
+}
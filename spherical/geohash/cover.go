@@ -0,0 +1,149 @@
+package geohash
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/internal/boundary"
+	"github.com/go-spatial/geom/planar"
+)
+
+// MaxCoverCells is the largest number of geohashes Cover will return; a
+// geometry covering more cells at the requested precision is an error.
+const MaxCoverCells = 1 << 20
+
+// ErrTooManyCells is returned by Cover when the geometry covers more than
+// MaxCoverCells cells.
+var ErrTooManyCells = errors.New("geohash: too many cells")
+
+// Cover returns the geohashes, of the given length, of the cells that
+// intersect the geometry; including the cells that only touch it, except for
+// points, which are in the single cell given by Encode. Polygons include
+// their interior. The hashes are sorted, and there are none for an empty
+// geometry.
+//
+// The coordinates are longitudes and latitudes, and the parts of the geometry
+// outside of the world are ignored. Geometries crossing the antimeridian
+// should be split first; see spherical.SplitAntimeridian.
+//
+// The cells are found by walking down from the one character hashes,
+// skipping the cells of the hashes the geometry does not intersect.
+//
+// Possible errors:
+//	 ErrInvalidPrecision
+//	 ErrTooManyCells
+//	 geom.ErrUnknownGeometry
+func Cover(g geom.Geometry, precision int) ([]string, error) {
+	if precision < 1 || precision > MaxPrecision {
+		return nil, ErrInvalidPrecision
+	}
+
+	switch gg := g.(type) {
+	case geom.Pointer:
+		return coverPoints(precision, gg.XY())
+	case geom.MultiPointer:
+		return coverPoints(precision, gg.Points()...)
+	}
+
+	extent, err := geom.NewExtentFromGeometry(g)
+	if err != nil {
+		return nil, err
+	}
+	if extent == nil {
+		return nil, nil
+	}
+
+	var (
+		hashes []string
+		visit  func(hash string) error
+		// a cell that intersects a polygon but not its boundary is inside
+		// of it, along with all of its descendants.
+		rings = boundary.Polygons(g)
+	)
+	visit = func(hash string) error {
+		cell, err := Decode(hash)
+		if err != nil {
+			return err
+		}
+		if cell.MinX() > extent.MaxX() || cell.MaxX() < extent.MinX() ||
+			cell.MinY() > extent.MaxY() || cell.MaxY() < extent.MinY() {
+			return nil
+		}
+		d, err := planar.Distance(cell.AsPolygon(), g)
+		if err != nil {
+			return err
+		}
+		if d != 0 {
+			return nil
+		}
+		if len(hash) == precision {
+			if len(hashes) == MaxCoverCells {
+				return ErrTooManyCells
+			}
+			hashes = append(hashes, hash)
+			return nil
+		}
+		if len(rings) > 0 {
+			d, err := planar.Distance(cell.AsPolygon(), rings)
+			if err != nil {
+				return err
+			}
+			if d != 0 {
+				// 32 cells for each of the remaining characters.
+				if n := precision - len(hash); 5*n > 30 || len(hashes)+1<<uint(5*n) > MaxCoverCells {
+					return ErrTooManyCells
+				}
+				hashes = appendDescendants(hashes, hash, precision)
+				return nil
+			}
+		}
+		// the alphabet is sorted, so the hashes are added in order.
+		for i := 0; i < len(alphabet); i++ {
+			if err := visit(hash + alphabet[i:i+1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := 0; i < len(alphabet); i++ {
+		if err := visit(alphabet[i : i+1]); err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
+
+// appendDescendants appends the hashes, of the given length, of the cells in
+// the cell of the hash.
+func appendDescendants(hashes []string, hash string, precision int) []string {
+	if len(hash) == precision {
+		return append(hashes, hash)
+	}
+	for i := 0; i < len(alphabet); i++ {
+		hashes = appendDescendants(hashes, hash+alphabet[i:i+1], precision)
+	}
+	return hashes
+}
+
+// coverPoints returns the sorted, unique, geohashes of the points; points
+// outside of the world are ignored.
+func coverPoints(precision int, pts ...[2]float64) ([]string, error) {
+	seen := make(map[string]bool, len(pts))
+	var hashes []string
+	for _, pt := range pts {
+		hash, err := Encode(pt[0], pt[1], precision)
+		if err == ErrOutOfRange {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+	sort.Strings(hashes)
+	return hashes, nil
+}
@@ -0,0 +1,131 @@
+package geohash
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/planar"
+)
+
+func TestCover(t *testing.T) {
+	type tcase struct {
+		geom      geom.Geometry
+		precision int
+		expected  []string
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Cover(tc.geom, tc.precision)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("hashes, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"point": {
+			geom:      geom.Point{-5.6, 42.6},
+			precision: 5,
+			expected:  []string{"ezs42"},
+		},
+		"point on a corner": {
+			geom:      geom.Point{0, 0},
+			precision: 1,
+			expected:  []string{"s"},
+		},
+		"multi point": {
+			geom:      geom.MultiPoint{{10, 10}, {-10, 10}, {10, 10}, {200, 0}},
+			precision: 1,
+			expected:  []string{"e", "s"},
+		},
+		"inside a cell": {
+			geom:      geom.Polygon{{{-5.62, 42.59}, {-5.59, 42.59}, {-5.59, 42.62}, {-5.62, 42.62}}},
+			precision: 5,
+			expected:  []string{"ezs42"},
+		},
+		"line across cells": {
+			geom:      geom.LineString{{-5.6, 42.6}, {-5.55, 42.6}},
+			precision: 5,
+			expected:  []string{"ezs42", "ezs43"},
+		},
+		"diagonal line": {
+			// the line misses the cell to the north west.
+			geom:      geom.LineString{{10, 10}, {80, 60}},
+			precision: 1,
+			expected:  []string{"s", "t", "v"},
+		},
+		"through a corner": {
+			// cells touching the line are included.
+			geom:      geom.LineString{{10, 10}, {80, 80}},
+			precision: 1,
+			expected:  []string{"s", "t", "u", "v"},
+		},
+		"polygon with a hole": {
+			geom: geom.Polygon{
+				{{-170, -80}, {170, -80}, {170, 80}, {-170, 80}},
+				{{-40, -40}, {40, -40}, {40, 40}, {-40, 40}},
+			},
+			precision: 1,
+			expected: []string{
+				"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "b", "c", "d", "e", "f", "g",
+				"h", "j", "k", "m", "n", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z",
+			},
+		},
+		"empty":             {geom: geom.LineString{}, precision: 3},
+		"outside the world": {geom: geom.LineString{{200, 0}, {210, 10}}, precision: 3},
+		"invalid precision": {geom: geom.Point{0, 0}, precision: 0, err: ErrInvalidPrecision},
+		"too many cells": {
+			geom:      geom.Polygon{{{-170, -80}, {170, -80}, {170, 80}, {-170, 80}}},
+			precision: 6,
+			err:       ErrTooManyCells,
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestCoverGrid(t *testing.T) {
+	// the cover matches checking every cell of the grid.
+	geoms := map[string]geom.Geometry{
+		"polygon": geom.Polygon{
+			{{-20, -30}, {60, -10}, {40, 50}, {-10, 40}},
+			{{0, 0}, {20, 0}, {10, 20}},
+		},
+		"multi polygon": geom.MultiPolygon{
+			{{{100, 10}, {140, 10}, {120, 40}}},
+			{{{-120, -60}, {-80, -60}, {-80, -20}, {-120, -20}}},
+		},
+		"line": geom.LineString{{-100, 20}, {-60, 25}, {-40, -10}},
+	}
+	for name, g := range geoms {
+		t.Run(name, func(t *testing.T) {
+			got, err := Cover(g, 3)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			var expected []string
+			for _, a := range alphabet {
+				for _, b := range alphabet {
+					for _, c := range alphabet {
+						hash := string([]rune{a, b, c})
+						cell, _ := Decode(hash)
+						if d, _ := planar.Distance(cell.AsPolygon(), g); d == 0 {
+							expected = append(expected, hash)
+						}
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("hashes, expected %v got %v", len(expected), len(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Package geohash encodes longitude/latitude points as geohashes, and decodes
+geohashes into the cells of the longitude/latitude grid they stand for.
+
+A geohash is a string in a base 32 alphabet; each character adds five bits,
+which alternately halve the longitude and the latitude range of the cell,
+starting with the longitude. Hashes sharing a prefix are in the same cell of
+the shorter hash, so they make good keys for caches and databases.
+*/
+package geohash
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/spherical"
+)
+
+// MaxPrecision is the longest geohash Encode will produce. The cells of a
+// twelve character hash are a few centimeters wide; past that the bits are
+// below the precision of a float64 degree.
+const MaxPrecision = 12
+
+var (
+	// ErrInvalidPrecision is returned when the precision is not between 1 and MaxPrecision.
+	ErrInvalidPrecision = errors.New("geohash: invalid precision")
+	// ErrInvalidHash is returned when decoding an empty hash or one with characters outside of the alphabet.
+	ErrInvalidHash = errors.New("geohash: invalid hash")
+	// ErrOutOfRange is returned when encoding a longitude or latitude outside of the world.
+	ErrOutOfRange = errors.New("geohash: coordinate out of range")
+)
+
+// alphabet is the geohash base 32 alphabet; it leaves out a, i, l and o.
+const alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// decodeMap maps the lower case characters of the alphabet to their value;
+// other characters are -1.
+var decodeMap [256]int8
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decodeMap[alphabet[i]] = int8(i)
+	}
+}
+
+// Encode returns the geohash, of the given number of characters, of the cell
+// containing the point. A point on the boundary between cells is in the cell
+// to its north or east.
+//
+// Possible errors:
+//	 ErrInvalidPrecision
+//	 ErrOutOfRange
+func Encode(lng, lat float64, precision int) (string, error) {
+	if precision < 1 || precision > MaxPrecision {
+		return "", ErrInvalidPrecision
+	}
+	if !(lng >= -180 && lng <= 180 && lat >= -90 && lat <= 90) {
+		return "", ErrOutOfRange
+	}
+
+	var (
+		lngRange = [2]float64{-180, 180}
+		latRange = [2]float64{-90, 90}
+		hash     = make([]byte, precision)
+		// the next bit is for the longitude
+		even = true
+	)
+	for i := range hash {
+		var ch byte
+		for bit := 0; bit < 5; bit++ {
+			ch <<= 1
+			if even {
+				ch |= bisect(&lngRange, lng)
+			} else {
+				ch |= bisect(&latRange, lat)
+			}
+			even = !even
+		}
+		hash[i] = alphabet[ch]
+	}
+	return string(hash), nil
+}
+
+// bisect halves the range, keeping the half v is in, and returns 1 for the
+// upper half.
+func bisect(r *[2]float64, v float64) byte {
+	mid := (r[0] + r[1]) / 2
+	if v >= mid {
+		r[0] = mid
+		return 1
+	}
+	r[1] = mid
+	return 0
+}
+
+// EncodePoint returns the geohash of the point; see Encode.
+//
+// Possible errors:
+//	 ErrInvalidPrecision
+//	 ErrOutOfRange
+func EncodePoint(pt geom.Pointer, precision int) (string, error) {
+	xy := pt.XY()
+	return Encode(xy[0], xy[1], precision)
+}
+
+// Decode returns the cell of the geohash, as an extent of
+// {west, south, east, north}. Upper case hashes are accepted.
+//
+// Possible errors:
+//	 ErrInvalidHash
+func Decode(hash string) (*geom.Extent, error) {
+	if hash == "" {
+		return nil, ErrInvalidHash
+	}
+	hash = strings.ToLower(hash)
+
+	var (
+		lngRange = [2]float64{-180, 180}
+		latRange = [2]float64{-90, 90}
+		even     = true
+	)
+	for i := 0; i < len(hash); i++ {
+		v := decodeMap[hash[i]]
+		if v < 0 {
+			return nil, ErrInvalidHash
+		}
+		for bit := 4; bit >= 0; bit-- {
+			r := &latRange
+			if even {
+				r = &lngRange
+			}
+			mid := (r[0] + r[1]) / 2
+			if v&(1<<uint(bit)) != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+	return &geom.Extent{lngRange[0], latRange[0], lngRange[1], latRange[1]}, nil
+}
+
+// Center returns the longitude and latitude of the center of the cell of the
+// geohash.
+//
+// Possible errors:
+//	 ErrInvalidHash
+func Center(hash string) ([2]float64, error) {
+	e, err := Decode(hash)
+	if err != nil {
+		return [2]float64{}, err
+	}
+	return [2]float64{(e.MinX() + e.MaxX()) / 2, (e.MinY() + e.MaxY()) / 2}, nil
+}
+
+// Direction is the direction of a neighboring cell.
+type Direction uint8
+
+// The directions, in the order returned by Neighbors.
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// offsets are the steps, in cells, of each direction.
+var offsets = [...][2]float64{
+	North:     {0, 1},
+	NorthEast: {1, 1},
+	East:      {1, 0},
+	SouthEast: {1, -1},
+	South:     {0, -1},
+	SouthWest: {-1, -1},
+	West:      {-1, 0},
+	NorthWest: {-1, 1},
+}
+
+// Neighbor returns the geohash, of the same length, of the cell next to the
+// hash's cell in the given direction. Cells wrap around the antimeridian.
+// There are no cells past the poles; an empty string is returned for the
+// northern neighbors of the cells along the north pole, and the southern
+// neighbors of the ones along the south pole.
+//
+// Possible errors:
+//	 ErrInvalidHash
+//	 ErrInvalidPrecision
+func Neighbor(hash string, dir Direction) (string, error) {
+	e, err := Decode(hash)
+	if err != nil {
+		return "", err
+	}
+	if int(dir) >= len(offsets) {
+		return "", nil
+	}
+	lat := (e.MinY()+e.MaxY())/2 + offsets[dir][1]*e.YSpan()
+	if lat < -90 || lat > 90 {
+		return "", nil
+	}
+	lng := spherical.WrapLongitude((e.MinX()+e.MaxX())/2 + offsets[dir][0]*e.XSpan())
+	return Encode(lng, lat, len(hash))
+}
+
+// Neighbors returns the geohashes of the eight cells around the hash's cell,
+// in the order of the directions; starting with North and going clockwise.
+// See Neighbor for the cells along the poles.
+//
+// Possible errors:
+//	 ErrInvalidHash
+//	 ErrInvalidPrecision
+func Neighbors(hash string) ([8]string, error) {
+	var ns [8]string
+	for dir := range ns {
+		n, err := Neighbor(hash, Direction(dir))
+		if err != nil {
+			return ns, err
+		}
+		ns[dir] = n
+	}
+	return ns, nil
+}
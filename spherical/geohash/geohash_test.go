@@ -0,0 +1,154 @@
+package geohash
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func TestEncode(t *testing.T) {
+	type tcase struct {
+		lng, lat  float64
+		precision int
+		expected  string
+		err       error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Encode(tc.lng, tc.lat, tc.precision)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if got != tc.expected {
+				t.Errorf("hash, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"jutland":        {lng: 10.40744, lat: 57.64911, precision: 11, expected: "u4pruydqqvj"},
+		"spain":          {lng: -5.6, lat: 42.6, precision: 5, expected: "ezs42"},
+		"origin":         {lng: 0, lat: 0, precision: 1, expected: "s"},
+		"south west":     {lng: -180, lat: -90, precision: 3, expected: "000"},
+		"north east":     {lng: 180, lat: 90, precision: 3, expected: "zzz"},
+		"max precision":  {lng: 10.40744, lat: 57.64911, precision: 12, expected: "u4pruydqqvj8"},
+		"zero precision": {precision: 0, err: ErrInvalidPrecision},
+		"long precision": {precision: 13, err: ErrInvalidPrecision},
+		"out of range":   {lng: 181, precision: 5, err: ErrOutOfRange},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type tcase struct {
+		hash     string
+		expected *geom.Extent
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Decode(tc.hash)
+			if err != tc.err {
+				t.Fatalf("error, expected %v got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if *got != *tc.expected {
+				t.Errorf("extent, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"one character": {hash: "s", expected: &geom.Extent{0, 0, 45, 45}},
+		"ezs42": {
+			hash:     "ezs42",
+			expected: &geom.Extent{-5.625, 42.5830078125, -5.5810546875, 42.626953125},
+		},
+		"upper case": {
+			hash:     "EZS42",
+			expected: &geom.Extent{-5.625, 42.5830078125, -5.5810546875, 42.626953125},
+		},
+		"empty":             {hash: "", err: ErrInvalidHash},
+		"invalid character": {hash: "ezs4a", err: ErrInvalidHash},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	pts := [][2]float64{{10.40744, 57.64911}, {-122.4194, 37.7749}, {151.2093, -33.8688}, {-179.9999, 89.9999}}
+	for _, pt := range pts {
+		for precision := 1; precision <= MaxPrecision; precision++ {
+			hash, err := Encode(pt[0], pt[1], precision)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			e, err := Decode(hash)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !e.ContainsPoint(pt) {
+				t.Errorf("%v at %v: cell %v, expected to contain the point", pt, precision, e)
+			}
+			c, _ := Center(hash)
+			if got, _ := Encode(c[0], c[1], precision); got != hash {
+				t.Errorf("%v at %v: center, expected %v got %v", pt, precision, hash, got)
+			}
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	type tcase struct {
+		hash     string
+		expected [8]string
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Neighbors(tc.hash)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("neighbors, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"ezs42": {
+			hash:     "ezs42",
+			expected: [8]string{"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx"},
+		},
+		"u4pruyd": {
+			hash:     "u4pruyd",
+			expected: [8]string{"u4pruyf", "u4pruyg", "u4pruye", "u4pruy7", "u4pruy6", "u4pruy3", "u4pruy9", "u4pruyc"},
+		},
+		"north pole and antimeridian": {
+			hash:     "b",
+			expected: [8]string{"", "", "c", "9", "8", "x", "z", ""},
+		},
+		"south pole and antimeridian": {
+			hash:     "0",
+			expected: [8]string{"2", "3", "1", "", "", "", "p", "r"},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+
+	if _, err := Neighbor("a", North); err != ErrInvalidHash {
+		t.Errorf("error, expected %v got %v", ErrInvalidHash, err)
+	}
+}
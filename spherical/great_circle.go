@@ -0,0 +1,149 @@
+package spherical
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// EarthRadiusKm is the mean radius of the earth in kilometers.
+const EarthRadiusKm = 6371.0088
+
+// ErrAntipodal is returned when a great circle arc is needed between two
+// antipodal points; there is no single great circle between them.
+var ErrAntipodal = errors.New("spherical: antipodal points")
+
+// vec3 is a point on the unit sphere.
+type vec3 [3]float64
+
+// toVec3 converts a long/lat point, in degrees, to a point on the unit sphere.
+func toVec3(pt [2]float64) vec3 {
+	lng, lat := pt[0]*math.Pi/180, pt[1]*math.Pi/180
+	return vec3{
+		math.Cos(lat) * math.Cos(lng),
+		math.Cos(lat) * math.Sin(lng),
+		math.Sin(lat),
+	}
+}
+
+// lngLat converts the point on the sphere to a long/lat point in degrees.
+func (v vec3) lngLat() [2]float64 {
+	return [2]float64{
+		math.Atan2(v[1], v[0]) * 180 / math.Pi,
+		math.Atan2(v[2], math.Hypot(v[0], v[1])) * 180 / math.Pi,
+	}
+}
+
+func (v vec3) dot(u vec3) float64 { return v[0]*u[0] + v[1]*u[1] + v[2]*u[2] }
+
+func (v vec3) cross(u vec3) vec3 {
+	return vec3{
+		v[1]*u[2] - v[2]*u[1],
+		v[2]*u[0] - v[0]*u[2],
+		v[0]*u[1] - v[1]*u[0],
+	}
+}
+
+func (v vec3) norm() float64 { return math.Sqrt(v.dot(v)) }
+
+func (v vec3) scale(s float64) vec3 { return vec3{v[0] * s, v[1] * s, v[2] * s} }
+
+func (v vec3) add(u vec3) vec3 { return vec3{v[0] + u[0], v[1] + u[1], v[2] + u[2]} }
+
+// angle returns the angle, in radians, between the two vectors.
+func (v vec3) angle(u vec3) float64 { return math.Atan2(v.cross(u).norm(), v.dot(u)) }
+
+// antipodal returns whether the two vectors point in opposite directions.
+func (v vec3) antipodal(u vec3) bool { return v.cross(u).norm() < 1e-15 && v.dot(u) < 0 }
+
+// Distance returns the great circle distance, in kilometers, between two
+// long/lat points.
+func Distance(a, b [2]float64) float64 {
+	return toVec3(a).angle(toVec3(b)) * EarthRadiusKm
+}
+
+// Interpolate returns the point at the given fraction along the great circle
+// arc from a to b; a fraction of 0 returns a, and 1 returns b. Points are
+// long/lat in degrees, and the returned longitude is in [-180, 180].
+//
+// Possible errors:
+//	 ErrAntipodal
+func Interpolate(a, b [2]float64, fraction float64) ([2]float64, error) {
+	va, vb := toVec3(a), toVec3(b)
+	if va.antipodal(vb) {
+		return [2]float64{}, ErrAntipodal
+	}
+	angle := va.angle(vb)
+	if angle == 0 {
+		return [2]float64{WrapLongitude(a[0]), a[1]}, nil
+	}
+	sin := math.Sin(angle)
+	wa := math.Sin((1-fraction)*angle) / sin
+	wb := math.Sin(fraction*angle) / sin
+	return va.scale(wa).add(vb.scale(wb)).lngLat(), nil
+}
+
+// Densify returns the linestring with points added along the great circle
+// between each pair of points, so that no segment is longer than
+// maxSegmentKm. The original points are kept. Points are long/lat in degrees,
+// and the longitudes of added points are in [-180, 180]; use
+// SplitAntimeridian on the result if the linestring crosses the antimeridian
+// and is going to be projected. A maxSegmentKm of zero or less returns a copy
+// of the linestring. A segment between antipodal points can not be
+// densified, as there is no single great circle between them.
+//
+// Possible errors:
+//	 ErrAntipodal
+func Densify(ls geom.LineString, maxSegmentKm float64) (geom.LineString, error) {
+	if len(ls) == 0 {
+		return nil, nil
+	}
+	dense := make(geom.LineString, 0, len(ls))
+	for i := 0; i < len(ls)-1; i++ {
+		a, b := ls[i], ls[i+1]
+		dense = append(dense, a)
+		if maxSegmentKm <= 0 {
+			continue
+		}
+		n := int(math.Ceil(Distance(a, b) / maxSegmentKm))
+		for j := 1; j < n; j++ {
+			pt, err := Interpolate(a, b, float64(j)/float64(n))
+			if err != nil {
+				return nil, err
+			}
+			dense = append(dense, pt)
+		}
+	}
+	return append(dense, ls[len(ls)-1]), nil
+}
+
+// onArc returns whether pt, which is on the great circle of the arc from a
+// to b, is between a and b.
+func onArc(a, b, pt vec3) bool {
+	const epsilon = 1e-12
+	return math.Abs(a.angle(pt)+pt.angle(b)-a.angle(b)) < epsilon
+}
+
+// Intersection returns the point where the two great circle arcs cross. The
+// lines are long/lat in degrees, and the returned longitude is in
+// [-180, 180]. ok is false if the arcs do not cross, or they are on the same
+// great circle.
+func Intersection(lineA, lineB geom.Line) (pt [2]float64, ok bool) {
+	a0, a1 := toVec3(lineA[0]), toVec3(lineA[1])
+	b0, b1 := toVec3(lineB[0]), toVec3(lineB[1])
+	// the normals of the planes of the great circles.
+	na, nb := a0.cross(a1), b0.cross(b1)
+	dir := na.cross(nb)
+	n := dir.norm()
+	if n < 1e-15 || na.norm() < 1e-15 || nb.norm() < 1e-15 {
+		return pt, false
+	}
+	// the great circles cross at two antipodal points.
+	for _, cand := range []vec3{dir.scale(1 / n), dir.scale(-1 / n)} {
+		if onArc(a0, a1, cand) && onArc(b0, b1, cand) {
+			return cand.lngLat(), true
+		}
+	}
+	return pt, false
+}
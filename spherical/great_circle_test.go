@@ -0,0 +1,181 @@
+package spherical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+)
+
+func near(a, b [2]float64, tolerance float64) bool {
+	return math.Abs(a[0]-b[0]) <= tolerance && math.Abs(a[1]-b[1]) <= tolerance
+}
+
+func TestDistance(t *testing.T) {
+	// a degree along the equator
+	if d := Distance([2]float64{0, 0}, [2]float64{1, 0}); math.Abs(d-111.195) > 0.001 {
+		t.Errorf("distance, expected 111.195 got %v", d)
+	}
+	// across the antimeridian
+	if d := Distance([2]float64{179.5, 0}, [2]float64{-179.5, 0}); math.Abs(d-111.195) > 0.001 {
+		t.Errorf("distance, expected 111.195 got %v", d)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	type tcase struct {
+		a, b     [2]float64
+		fraction float64
+		expected [2]float64
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, err := Interpolate(tc.a, tc.b, tc.fraction)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if !near(got, tc.expected, 1e-9) {
+				t.Errorf("interpolate, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"start": {
+			a: [2]float64{10, 10}, b: [2]float64{20, 20},
+			fraction: 0,
+			expected: [2]float64{10, 10},
+		},
+		"end": {
+			a: [2]float64{10, 10}, b: [2]float64{20, 20},
+			fraction: 1,
+			expected: [2]float64{20, 20},
+		},
+		"equator": {
+			a: [2]float64{0, 0}, b: [2]float64{90, 0},
+			fraction: 0.5,
+			expected: [2]float64{45, 0},
+		},
+		"meridian": {
+			a: [2]float64{30, 0}, b: [2]float64{30, 60},
+			fraction: 0.25,
+			expected: [2]float64{30, 15},
+		},
+		"across the antimeridian": {
+			a: [2]float64{170, 0}, b: [2]float64{-170, 0},
+			fraction: 0.5,
+			expected: [2]float64{180, 0},
+		},
+		"over the pole": {
+			a: [2]float64{0, 80}, b: [2]float64{180, 80},
+			fraction: 0.25,
+			expected: [2]float64{0, 85},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestInterpolateGreatCircle(t *testing.T) {
+	// The great circle between two points at the same latitude goes
+	// towards the pole.
+	mid, err := Interpolate([2]float64{-74, 40.7}, [2]float64{2.35, 48.85}, 0.5)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if mid[1] < 50 {
+		t.Errorf("latitude, expected more than 50 got %v", mid[1])
+	}
+
+	// antipodal points have no single great circle between them.
+	if _, err := Interpolate([2]float64{10, 10}, [2]float64{-170, -10}, 0.5); err != ErrAntipodal {
+		t.Errorf("error, expected %v got %v", ErrAntipodal, err)
+	}
+}
+
+func TestDensify(t *testing.T) {
+	ls := geom.LineString{{0, 0}, {10, 0}, {10, 1}}
+	got, err := Densify(ls, 100)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	// 1111.95km is split into 12 segments, and 111.2km into 2.
+	if len(got) != 15 {
+		t.Fatalf("number of points, expected 15 got %v", len(got))
+	}
+	if got[0] != ls[0] || got[12] != ls[1] || got[14] != ls[2] {
+		t.Errorf("original points, expected %v got %v %v %v", ls, got[0], got[12], got[14])
+	}
+	for i := 1; i < len(got); i++ {
+		if d := Distance(got[i-1], got[i]); d > 100 {
+			t.Errorf("segment %v, expected at most 100km got %v", i, d)
+		}
+	}
+
+	if got, _ := Densify(ls, 0); len(got) != len(ls) {
+		t.Errorf("no densify, expected %v got %v", ls, got)
+	}
+
+	antipodal := geom.LineString{{0, 10}, {10, 10}, {-170, -10}}
+	if _, err := Densify(antipodal, 100); err != ErrAntipodal {
+		t.Errorf("error, expected %v got %v", ErrAntipodal, err)
+	}
+	// a segment that is not densified does not need a great circle.
+	if got, err := Densify(antipodal, 0); err != nil || len(got) != len(antipodal) {
+		t.Errorf("no densify, expected %v, nil got %v, %v", antipodal, got, err)
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	type tcase struct {
+		a, b     geom.Line
+		expected [2]float64
+		ok       bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			got, ok := Intersection(tc.a, tc.b)
+			if ok != tc.ok {
+				t.Fatalf("ok, expected %v got %v", tc.ok, ok)
+			}
+			if ok && !near(got, tc.expected, 1e-9) {
+				t.Errorf("intersection, expected %v got %v", tc.expected, got)
+			}
+		}
+	}
+
+	tcases := map[string]tcase{
+		"equator and meridian": {
+			a:        geom.Line{{-10, 0}, {10, 0}},
+			b:        geom.Line{{5, -10}, {5, 10}},
+			expected: [2]float64{5, 0},
+			ok:       true,
+		},
+		"across the antimeridian": {
+			a:        geom.Line{{170, 0}, {-170, 0}},
+			b:        geom.Line{{180, -10}, {180, 10}},
+			expected: [2]float64{180, 0},
+			ok:       true,
+		},
+		"not crossing": {
+			a: geom.Line{{-10, 0}, {10, 0}},
+			b: geom.Line{{20, -10}, {20, 10}},
+		},
+		"antipodal crossing is not on the arcs": {
+			a: geom.Line{{-10, 0}, {10, 0}},
+			b: geom.Line{{180, -10}, {180, 10}},
+		},
+		"same great circle": {
+			a: geom.Line{{-10, 0}, {10, 0}},
+			b: geom.Line{{0, 0}, {20, 0}},
+		},
+	}
+
+	for name, tc := range tcases {
+		t.Run(name, fn(tc))
+	}
+}